@@ -0,0 +1,108 @@
+package client
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	Cli "github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/engine-api/types"
+)
+
+// CmdCheckpoint is the parent subcommand for all checkpoint commands
+//
+// Usage: docker checkpoint <COMMAND> <OPTS>
+func (cli *DockerCli) CmdCheckpoint(args ...string) error {
+	description := Cli.DockerCommands["checkpoint"].Description + "\n\nCommands:\n"
+	commands := [][]string{
+		{"create", "Create a checkpoint from a running container"},
+		{"ls", "List the checkpoints of a container"},
+		{"rm", "Remove a checkpoint"},
+	}
+
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+
+	description += "\nRun 'docker checkpoint COMMAND --help' for more information on a command"
+	cmd := Cli.Subcmd("checkpoint", []string{"[COMMAND]"}, description, false)
+
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+// CmdCheckpointCreate creates a checkpoint from a running container.
+//
+// Usage: docker checkpoint create [OPTIONS] CONTAINER CHECKPOINT
+func (cli *DockerCli) CmdCheckpointCreate(args ...string) error {
+	cmd := Cli.Subcmd("checkpoint create", []string{"CONTAINER CHECKPOINT"}, "Create a checkpoint from a running container", true)
+	leaveRunning := cmd.Bool([]string{"-leave-running"}, false, "Leave the container running after the checkpoint")
+
+	cmd.Require(flag.Exact, 2)
+	cmd.ParseFlags(args, true)
+
+	options := types.CheckpointCreateOptions{
+		CheckpointID: cmd.Arg(1),
+		Exit:         !*leaveRunning,
+	}
+	if err := cli.client.CheckpointCreate(cmd.Arg(0), options); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cli.out, "%s\n", options.CheckpointID)
+	return nil
+}
+
+// CmdCheckpointLs lists the checkpoints of a container.
+//
+// Usage: docker checkpoint ls [OPTIONS] CONTAINER
+func (cli *DockerCli) CmdCheckpointLs(args ...string) error {
+	cmd := Cli.Subcmd("checkpoint ls", []string{"CONTAINER"}, "List the checkpoints of a container", true)
+	quiet := cmd.Bool([]string{"q", "-quiet"}, false, "Only display checkpoint names")
+
+	cmd.Require(flag.Exact, 1)
+	cmd.ParseFlags(args, true)
+
+	checkpoints, err := cli.client.CheckpointList(cmd.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cli.out, 20, 1, 3, ' ', 0)
+	if !*quiet {
+		fmt.Fprintf(w, "CHECKPOINT NAME\n")
+	}
+	for _, checkpoint := range checkpoints {
+		fmt.Fprintf(w, "%s\n", checkpoint.Name)
+	}
+	w.Flush()
+	return nil
+}
+
+// CmdCheckpointRm removes one or more checkpoints of a container.
+//
+// Usage: docker checkpoint rm CONTAINER CHECKPOINT [CHECKPOINT...]
+func (cli *DockerCli) CmdCheckpointRm(args ...string) error {
+	cmd := Cli.Subcmd("checkpoint rm", []string{"CONTAINER CHECKPOINT [CHECKPOINT...]"}, "Remove a checkpoint", true)
+	cmd.Require(flag.Min, 2)
+	cmd.ParseFlags(args, true)
+
+	var status = 0
+
+	container := cmd.Arg(0)
+	for _, name := range cmd.Args()[1:] {
+		if err := cli.client.CheckpointDelete(container, name); err != nil {
+			fmt.Fprintf(cli.err, "%s\n", err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(cli.out, "%s\n", name)
+	}
+
+	if status != 0 {
+		return Cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
@@ -3,12 +3,19 @@ package client
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/promise"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
 	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
 )
 
 // CmdExec runs a command in a running container.
@@ -120,6 +127,76 @@ func (cli *DockerCli) CmdExec(args ...string) error {
 	return nil
 }
 
+// CmdExecLs lists the exec processes of a container.
+//
+// Usage: docker exec ls [OPTIONS] CONTAINER
+func (cli *DockerCli) CmdExecLs(args ...string) error {
+	cmd := Cli.Subcmd("exec ls", []string{"CONTAINER"}, "List the exec processes of a container", true)
+	quiet := cmd.Bool([]string{"q", "-quiet"}, false, "Only display exec IDs")
+
+	cmd.Require(flag.Exact, 1)
+	cmd.ParseFlags(args, true)
+
+	execs, err := cli.client.ContainerExecList(cmd.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cli.out, 20, 1, 3, ' ', 0)
+	if !*quiet {
+		fmt.Fprintf(w, "EXEC ID\tCOMMAND\tCREATED\tSTATUS\tPID\tUSER\tTTY\n")
+	}
+	for _, e := range execs {
+		if *quiet {
+			fmt.Fprintf(w, "%s\n", e.ID)
+			continue
+		}
+
+		created := ""
+		if t, err := time.Parse(time.RFC3339Nano, e.CreatedAt); err == nil {
+			created = units.HumanDuration(time.Now().UTC().Sub(t)) + " ago"
+		}
+		status := "Created"
+		pid := ""
+		switch {
+		case e.Running:
+			status = "Running"
+			if e.Pid != 0 {
+				pid = strconv.Itoa(e.Pid)
+			}
+		case e.ExitCode != nil:
+			status = fmt.Sprintf("Exited (%d)", *e.ExitCode)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%t\n", e.ID, strconv.Quote(e.Command), created, status, pid, e.User, e.Tty)
+	}
+	w.Flush()
+	return nil
+}
+
+// CmdExecKill sends a signal to one or more running exec processes.
+//
+// Usage: docker exec kill [OPTIONS] EXEC [EXEC...]
+func (cli *DockerCli) CmdExecKill(args ...string) error {
+	cmd := Cli.Subcmd("exec kill", []string{"EXEC [EXEC...]"}, "Kill one or more running exec processes", true)
+	signal := cmd.String([]string{"s", "-signal"}, "KILL", "Signal to send to the exec process")
+	cmd.Require(flag.Min, 1)
+
+	cmd.ParseFlags(args, true)
+
+	var errs []string
+	for _, id := range cmd.Args() {
+		if err := cli.client.ContainerExecKill(id, *signal); err != nil {
+			errs = append(errs, fmt.Sprintf("Failed to kill exec (%s): %s", id, err))
+		} else {
+			fmt.Fprintf(cli.out, "%s\n", id)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
 // ParseExec parses the specified args for the specified command and generates
 // an ExecConfig from it.
 // If the minimal number of specified args is not right or if specified args are
@@ -131,9 +208,14 @@ func ParseExec(cmd *flag.FlagSet, args []string) (*types.ExecConfig, error) {
 		flDetach     = cmd.Bool([]string{"d", "-detach"}, false, "Detached mode: run command in the background")
 		flUser       = cmd.String([]string{"u", "-user"}, "", "Username or UID (format: <name|uid>[:<group|gid>])")
 		flPrivileged = cmd.Bool([]string{"-privileged"}, false, "Give extended privileges to the command")
+		flWorkingDir = cmd.String([]string{"w", "-workdir"}, "", "Working directory inside the container")
+		flEnv        = opts.NewListOpts(runconfigopts.ValidateEnv)
+		flEnvFile    = opts.NewListOpts(nil)
 		execCmd      []string
 		container    string
 	)
+	cmd.Var(&flEnv, []string{"e", "-env"}, "Set environment variables")
+	cmd.Var(&flEnvFile, []string{"-env-file"}, "Read in a file of environment variables")
 	cmd.Require(flag.Min, 2)
 	if err := cmd.ParseFlags(args, true); err != nil {
 		return nil, err
@@ -142,10 +224,23 @@ func ParseExec(cmd *flag.FlagSet, args []string) (*types.ExecConfig, error) {
 	parsedArgs := cmd.Args()
 	execCmd = parsedArgs[1:]
 
+	// parse the '-e' and '--env' after the env files, to allow override
+	var env []string
+	for _, ef := range flEnvFile.GetAll() {
+		parsedVars, err := runconfigopts.ParseEnvFile(ef)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, parsedVars...)
+	}
+	env = append(env, flEnv.GetAll()...)
+
 	execConfig := &types.ExecConfig{
 		User:       *flUser,
 		Privileged: *flPrivileged,
 		Tty:        *flTty,
+		Env:        env,
+		WorkingDir: *flWorkingDir,
 		Cmd:        execCmd,
 		Container:  container,
 		Detach:     *flDetach,
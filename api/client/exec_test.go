@@ -47,6 +47,16 @@ func TestParseExec(t *testing.T) {
 			Container:    "container",
 			Cmd:          []string{"command"},
 		},
+		&arguments{
+			[]string{"-e", "FOO=bar", "--env", "BAZ=", "-w", "/tmp", "container", "command"},
+		}: {
+			AttachStdout: true,
+			AttachStderr: true,
+			Env:          []string{"FOO=bar", "BAZ="},
+			WorkingDir:   "/tmp",
+			Container:    "container",
+			Cmd:          []string{"command"},
+		},
 		&arguments{
 			[]string{"-d", "container", "command"},
 		}: {
@@ -57,6 +67,14 @@ func TestParseExec(t *testing.T) {
 			Container:    "container",
 			Cmd:          []string{"command"},
 		},
+		&arguments{
+			[]string{"--", "ls", "command"},
+		}: {
+			Container:    "ls",
+			Cmd:          []string{"command"},
+			AttachStdout: true,
+			AttachStderr: true,
+		},
 		&arguments{
 			[]string{"-t", "-i", "-d", "container", "command"},
 		}: {
@@ -118,6 +136,17 @@ func compareExecConfig(config1 *types.ExecConfig, config2 *types.ExecConfig) boo
 	if config1.User != config2.User {
 		return false
 	}
+	if config1.WorkingDir != config2.WorkingDir {
+		return false
+	}
+	if len(config1.Env) != len(config2.Env) {
+		return false
+	}
+	for index, value := range config1.Env {
+		if value != config2.Env[index] {
+			return false
+		}
+	}
 	if len(config1.Cmd) != len(config2.Cmd) {
 		return false
 	}
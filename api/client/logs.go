@@ -10,11 +10,6 @@ import (
 	"github.com/docker/engine-api/types"
 )
 
-var validDrivers = map[string]bool{
-	"json-file": true,
-	"journald":  true,
-}
-
 // CmdLogs fetches the logs of a given container.
 //
 // docker logs [OPTIONS] CONTAINER
@@ -22,6 +17,7 @@ func (cli *DockerCli) CmdLogs(args ...string) error {
 	cmd := Cli.Subcmd("logs", []string{"CONTAINER"}, Cli.DockerCommands["logs"].Description, true)
 	follow := cmd.Bool([]string{"f", "-follow"}, false, "Follow log output")
 	since := cmd.String([]string{"-since"}, "", "Show logs since timestamp")
+	until := cmd.String([]string{"-until"}, "", "Show logs before timestamp")
 	times := cmd.Bool([]string{"t", "-timestamps"}, false, "Show timestamps")
 	tail := cmd.String([]string{"-tail"}, "all", "Number of lines to show from the end of the logs")
 	cmd.Require(flag.Exact, 1)
@@ -35,8 +31,8 @@ func (cli *DockerCli) CmdLogs(args ...string) error {
 		return err
 	}
 
-	if !validDrivers[c.HostConfig.LogConfig.Type] {
-		return fmt.Errorf("\"logs\" command is supported only for \"json-file\" and \"journald\" logging drivers (got: %s)", c.HostConfig.LogConfig.Type)
+	if c.HostConfig.LogConfig.Type == "none" {
+		return fmt.Errorf("\"logs\" command is not supported for the \"none\" logging driver")
 	}
 
 	options := types.ContainerLogsOptions{
@@ -44,6 +40,7 @@ func (cli *DockerCli) CmdLogs(args ...string) error {
 		ShowStdout:  true,
 		ShowStderr:  true,
 		Since:       *since,
+		Until:       *until,
 		Timestamps:  *times,
 		Follow:      *follow,
 		Tail:        *tail,
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	Cli "github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/pkg/stringutils"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+)
+
+// CmdPlugin is the parent subcommand for all plugin commands
+//
+// Usage: docker plugin <COMMAND> <OPTS>
+func (cli *DockerCli) CmdPlugin(args ...string) error {
+	description := Cli.DockerCommands["plugin"].Description + "\n\nCommands:\n"
+	commands := [][]string{
+		{"disable", "Disable a plugin"},
+		{"enable", "Enable a plugin"},
+		{"inspect", "Display detailed information on a plugin"},
+		{"install", "Install a plugin from a registry"},
+		{"ls", "List plugins"},
+		{"rm", "Remove a plugin"},
+	}
+
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+
+	description += "\nRun 'docker plugin COMMAND --help' for more information on a command"
+	cmd := Cli.Subcmd("plugin", []string{"[COMMAND]"}, description, false)
+
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+// CmdPluginInstall pulls a plugin from a registry, and enables it.
+//
+// Usage: docker plugin install [OPTIONS] PLUGIN
+func (cli *DockerCli) CmdPluginInstall(args ...string) error {
+	cmd := Cli.Subcmd("plugin install", []string{"PLUGIN"}, "Install a plugin from a registry", true)
+	disable := cmd.Bool([]string{"-disable"}, false, "Do not enable the plugin once installed")
+	grantAll := cmd.Bool([]string{"-grant-all-permissions"}, false, "Grant all the privileges the plugin requests")
+
+	cmd.Require(flag.Exact, 1)
+	cmd.ParseFlags(args, true)
+
+	ref, err := reference.ParseNamed(cmd.Arg(0))
+	if err != nil {
+		return err
+	}
+	ref = reference.WithDefaultTag(ref)
+
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return err
+	}
+	authConfig := registry.ResolveAuthConfig(cli.configFile.AuthConfigs, repoInfo.Index)
+	encodedAuth, err := encodeAuthToBase64(authConfig)
+	if err != nil {
+		return err
+	}
+
+	options := types.PluginInstallOptions{
+		Name:                  ref.String(),
+		RegistryAuth:          encodedAuth,
+		Disabled:              *disable,
+		AcceptAllPermissions:  *grantAll,
+		AcceptPermissionsFunc: cli.acceptPrivileges(cmd.Arg(0)),
+	}
+	if err := cli.client.PluginInstall(options); err != nil {
+		return err
+	}
+	fmt.Fprintf(cli.out, "%s\n", cmd.Arg(0))
+	return nil
+}
+
+// acceptPrivileges returns a function listing the privileges the plugin name
+// requests, and asking the user to grant them.
+func (cli *DockerCli) acceptPrivileges(name string) func(privileges types.PluginPrivileges) (bool, error) {
+	return func(privileges types.PluginPrivileges) (bool, error) {
+		fmt.Fprintf(cli.out, "Plugin %q is requesting the following privileges:\n", name)
+		for _, privilege := range privileges {
+			fmt.Fprintf(cli.out, " - %s: %v\n", privilege.Name, privilege.Value)
+		}
+		fmt.Fprint(cli.out, "Do you grant the above permissions? [y/N] ")
+		answer := strings.ToLower(strings.TrimSpace(readInput(cli.in, cli.out)))
+		return answer == "y" || answer == "yes", nil
+	}
+}
+
+// CmdPluginLs lists the installed plugins.
+//
+// Usage: docker plugin ls [OPTIONS]
+func (cli *DockerCli) CmdPluginLs(args ...string) error {
+	cmd := Cli.Subcmd("plugin ls", nil, "List plugins", true)
+	noTrunc := cmd.Bool([]string{"-no-trunc"}, false, "Don't truncate output")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	plugins, err := cli.client.PluginList()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cli.out, 20, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "NAME \tTAG \tDESCRIPTION\tENABLED\n")
+	for _, p := range plugins {
+		desc := strings.Replace(p.Manifest.Description, "\n", " ", -1)
+		if !*noTrunc && len(desc) > 45 {
+			desc = stringutils.Truncate(desc, 42) + "..."
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", p.Name, p.Tag, desc, p.Active)
+	}
+	w.Flush()
+	return nil
+}
+
+// CmdPluginInspect displays low-level information on one or more plugins.
+//
+// Usage: docker plugin inspect [OPTIONS] PLUGIN [PLUGIN...]
+func (cli *DockerCli) CmdPluginInspect(args ...string) error {
+	cmd := Cli.Subcmd("plugin inspect", []string{"PLUGIN [PLUGIN...]"}, "Display detailed information on a plugin", true)
+	tmplStr := cmd.String([]string{"f", "-format"}, "", "Format the output using the given go template")
+
+	cmd.Require(flag.Min, 1)
+	cmd.ParseFlags(args, true)
+
+	inspectSearcher := func(name string) (interface{}, []byte, error) {
+		p, err := cli.client.PluginInspect(name)
+		return p, nil, err
+	}
+
+	return cli.inspectElements(*tmplStr, cmd.Args(), inspectSearcher)
+}
+
+// CmdPluginEnable enables a plugin.
+//
+// Usage: docker plugin enable PLUGIN
+func (cli *DockerCli) CmdPluginEnable(args ...string) error {
+	cmd := Cli.Subcmd("plugin enable", []string{"PLUGIN"}, "Enable a plugin", true)
+	cmd.Require(flag.Exact, 1)
+	cmd.ParseFlags(args, true)
+
+	if err := cli.client.PluginEnable(cmd.Arg(0)); err != nil {
+		return err
+	}
+	fmt.Fprintf(cli.out, "%s\n", cmd.Arg(0))
+	return nil
+}
+
+// CmdPluginDisable disables a plugin.
+//
+// Usage: docker plugin disable PLUGIN
+func (cli *DockerCli) CmdPluginDisable(args ...string) error {
+	cmd := Cli.Subcmd("plugin disable", []string{"PLUGIN"}, "Disable a plugin", true)
+	cmd.Require(flag.Exact, 1)
+	cmd.ParseFlags(args, true)
+
+	if err := cli.client.PluginDisable(cmd.Arg(0)); err != nil {
+		return err
+	}
+	fmt.Fprintf(cli.out, "%s\n", cmd.Arg(0))
+	return nil
+}
+
+// CmdPluginRm removes one or more plugins.
+//
+// Usage: docker plugin rm [OPTIONS] PLUGIN [PLUGIN...]
+func (cli *DockerCli) CmdPluginRm(args ...string) error {
+	cmd := Cli.Subcmd("plugin rm", []string{"PLUGIN [PLUGIN...]"}, "Remove a plugin", true)
+	force := cmd.Bool([]string{"f", "-force"}, false, "Force the removal of an enabled plugin")
+	cmd.Require(flag.Min, 1)
+	cmd.ParseFlags(args, true)
+
+	var status = 0
+
+	for _, name := range cmd.Args() {
+		if err := cli.client.PluginRemove(name, *force); err != nil {
+			fmt.Fprintf(cli.err, "%s\n", err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(cli.out, "%s\n", name)
+	}
+
+	if status != 0 {
+		return Cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
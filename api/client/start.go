@@ -50,10 +50,15 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 	attach := cmd.Bool([]string{"a", "-attach"}, false, "Attach STDOUT/STDERR and forward signals")
 	openStdin := cmd.Bool([]string{"i", "-interactive"}, false, "Attach container's STDIN")
 	detachKeys := cmd.String([]string{"-detach-keys"}, "", "Override the key sequence for detaching a container")
+	checkpoint := cmd.String([]string{"-checkpoint"}, "", "Restore from this checkpoint")
 	cmd.Require(flag.Min, 1)
 
 	cmd.ParseFlags(args, true)
 
+	if *checkpoint != "" && cmd.NArg() > 1 {
+		return fmt.Errorf("You cannot restore multiple containers from a checkpoint at once.")
+	}
+
 	if *attach || *openStdin {
 		// We're going to attach to a container.
 		// 1. Ensure we only have one container.
@@ -108,7 +113,7 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 		})
 
 		// 3. Start the container.
-		if err := cli.client.ContainerStart(containerID); err != nil {
+		if err := cli.client.ContainerStart(containerID, *checkpoint); err != nil {
 			return err
 		}
 
@@ -131,16 +136,16 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 	} else {
 		// We're not going to attach to anything.
 		// Start as many containers as we want.
-		return cli.startContainersWithoutAttachments(cmd.Args())
+		return cli.startContainersWithoutAttachments(cmd.Args(), *checkpoint)
 	}
 
 	return nil
 }
 
-func (cli *DockerCli) startContainersWithoutAttachments(containerIDs []string) error {
+func (cli *DockerCli) startContainersWithoutAttachments(containerIDs []string, checkpoint string) error {
 	var failedContainers []string
 	for _, containerID := range containerIDs {
-		if err := cli.client.ContainerStart(containerID); err != nil {
+		if err := cli.client.ContainerStart(containerID, checkpoint); err != nil {
 			fmt.Fprintf(cli.err, "%s\n", err)
 			failedContainers = append(failedContainers, containerID)
 		} else {
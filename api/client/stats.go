@@ -12,8 +12,6 @@ import (
 
 	Cli "github.com/docker/docker/cli"
 	"github.com/docker/engine-api/types"
-	"github.com/docker/engine-api/types/events"
-	"github.com/docker/engine-api/types/filters"
 	"github.com/docker/go-units"
 )
 
@@ -47,10 +45,8 @@ func (s *containerStats) Collect(cli *DockerCli, streamStats bool) {
 	defer responseBody.Close()
 
 	var (
-		previousCPU    uint64
-		previousSystem uint64
-		dec            = json.NewDecoder(responseBody)
-		u              = make(chan error, 1)
+		dec = json.NewDecoder(responseBody)
+		u   = make(chan error, 1)
 	)
 	go func() {
 		for {
@@ -59,29 +55,7 @@ func (s *containerStats) Collect(cli *DockerCli, streamStats bool) {
 				u <- err
 				return
 			}
-
-			var memPercent = 0.0
-			var cpuPercent = 0.0
-
-			// MemoryStats.Limit will never be 0 unless the container is not running and we haven't
-			// got any data from cgroup
-			if v.MemoryStats.Limit != 0 {
-				memPercent = float64(v.MemoryStats.Usage) / float64(v.MemoryStats.Limit) * 100.0
-			}
-
-			previousCPU = v.PreCPUStats.CPUUsage.TotalUsage
-			previousSystem = v.PreCPUStats.SystemUsage
-			cpuPercent = calculateCPUPercent(previousCPU, previousSystem, v)
-			blkRead, blkWrite := calculateBlockIO(v.BlkioStats)
-			s.mu.Lock()
-			s.CPUPercentage = cpuPercent
-			s.Memory = float64(v.MemoryStats.Usage)
-			s.MemoryLimit = float64(v.MemoryStats.Limit)
-			s.MemoryPercentage = memPercent
-			s.NetworkRx, s.NetworkTx = calculateNetwork(v.Networks)
-			s.BlockRead = float64(blkRead)
-			s.BlockWrite = float64(blkWrite)
-			s.mu.Unlock()
+			s.update(v)
 			u <- nil
 			if !streamStats {
 				return
@@ -117,6 +91,94 @@ func (s *containerStats) Collect(cli *DockerCli, streamStats bool) {
 	}
 }
 
+// update computes the displayed stats of the container from a sample.
+func (s *containerStats) update(v *types.StatsJSON) {
+	var memPercent = 0.0
+	var cpuPercent = 0.0
+
+	// MemoryStats.Limit will never be 0 unless the container is not running and we haven't
+	// got any data from cgroup
+	if v.MemoryStats.Limit != 0 {
+		memPercent = float64(v.MemoryStats.Usage) / float64(v.MemoryStats.Limit) * 100.0
+	}
+
+	previousCPU := v.PreCPUStats.CPUUsage.TotalUsage
+	previousSystem := v.PreCPUStats.SystemUsage
+	cpuPercent = calculateCPUPercent(previousCPU, previousSystem, v)
+	blkRead, blkWrite := calculateBlockIO(v.BlkioStats)
+	s.mu.Lock()
+	s.CPUPercentage = cpuPercent
+	s.Memory = float64(v.MemoryStats.Usage)
+	s.MemoryLimit = float64(v.MemoryStats.Limit)
+	s.MemoryPercentage = memPercent
+	s.NetworkRx, s.NetworkTx = calculateNetwork(v.Networks)
+	s.BlockRead = float64(blkRead)
+	s.BlockWrite = float64(blkWrite)
+	s.mu.Unlock()
+}
+
+// collectAll collects the stats of the containers selected by options from
+// a single stream of batches, adding and removing the containers as they
+// appear in and disappear from the batches. ready is closed once the first
+// batch is collected. The error ending the stream, if any, is sent to
+// errChan.
+func (s *stats) collectAll(cli *DockerCli, options types.ContainerStatsAllOptions, ready chan<- struct{}, errChan chan<- error) {
+	responseBody, err := cli.client.ContainerStatsAll(options)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	defer responseBody.Close()
+
+	dec := json.NewDecoder(responseBody)
+	for first := true; ; first = false {
+		var batch []types.ContainerStatsSample
+		if err := dec.Decode(&batch); err != nil {
+			if err != io.EOF || options.Stream {
+				errChan <- err
+			}
+			return
+		}
+
+		samples := make(map[string]*types.StatsJSON, len(batch))
+		var names []string
+		for i := range batch {
+			name := batch[i].ID
+			if len(name) > 12 {
+				name = name[:12]
+			}
+			samples[name] = &batch[i].StatsJSON
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		s.mu.Lock()
+		// keep the containers already displayed in place, and append the
+		// new ones
+		var cs []*containerStats
+		for _, c := range s.cs {
+			if v, exists := samples[c.Name]; exists {
+				c.update(v)
+				cs = append(cs, c)
+				delete(samples, c.Name)
+			}
+		}
+		for _, name := range names {
+			if v, exists := samples[name]; exists {
+				c := &containerStats{Name: name}
+				c.update(v)
+				cs = append(cs, c)
+			}
+		}
+		s.cs = cs
+		s.mu.Unlock()
+
+		if first {
+			close(ready)
+		}
+	}
+}
+
 func (s *containerStats) Display(w io.Writer) error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -147,22 +209,6 @@ func (cli *DockerCli) CmdStats(args ...string) error {
 
 	names := cmd.Args()
 	showAll := len(names) == 0
-
-	if showAll {
-		options := types.ContainerListOptions{
-			All: *all,
-		}
-		cs, err := cli.client.ContainerList(options)
-		if err != nil {
-			return err
-		}
-		for _, c := range cs {
-			names = append(names, c.ID[:12])
-		}
-	}
-	if len(names) == 0 && !showAll {
-		return fmt.Errorf("No containers found")
-	}
 	sort.Strings(names)
 
 	var (
@@ -176,95 +222,46 @@ func (cli *DockerCli) CmdStats(args ...string) error {
 		}
 		io.WriteString(w, "CONTAINER\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\n")
 	}
-	for _, n := range names {
-		s := &containerStats{Name: n}
-		// no need to lock here since only the main goroutine is running here
-		cStats.cs = append(cStats.cs, s)
-		go s.Collect(cli, !*noStream)
-	}
-	closeChan := make(chan error)
+	closeChan := make(chan error, 1)
 	if showAll {
-		type watch struct {
-			cid   string
-			event string
-			err   error
+		// get the stats of all the containers in a single stream, which
+		// also adds and removes the containers as they start and stop.
+		options := types.ContainerStatsAllOptions{
+			All:    *all,
+			Stream: !*noStream,
 		}
-		getNewContainers := func(c chan<- watch) {
-			f := filters.NewArgs()
-			f.Add("type", "container")
-			options := types.EventsOptions{
-				Filters: f,
-			}
-			resBody, err := cli.client.Events(options)
-			if err != nil {
-				c <- watch{err: err}
-				return
-			}
-			defer resBody.Close()
-
-			decodeEvents(resBody, func(event events.Message, err error) error {
-				if err != nil {
-					c <- watch{err: err}
-					return nil
-				}
-
-				c <- watch{event.ID[:12], event.Action, nil}
-				return nil
-			})
+		ready := make(chan struct{})
+		go cStats.collectAll(cli, options, ready, closeChan)
+		select {
+		case <-ready:
+		case err := <-closeChan:
+			return err
 		}
-		go func(stopChan chan<- error) {
-			cChan := make(chan watch)
-			go getNewContainers(cChan)
-			for {
-				c := <-cChan
-				if c.err != nil {
-					stopChan <- c.err
-					return
-				}
-				switch c.event {
-				case "create":
-					s := &containerStats{Name: c.cid}
-					cStats.mu.Lock()
-					cStats.cs = append(cStats.cs, s)
-					cStats.mu.Unlock()
-					go s.Collect(cli, !*noStream)
-				case "stop":
-				case "die":
-					if !*all {
-						var remove int
-						// cStats cannot be O(1) with a map cause ranging over it would cause
-						// containers in stats to move up and down in the list...:(
-						cStats.mu.Lock()
-						for i, s := range cStats.cs {
-							if s.Name == c.cid {
-								remove = i
-								break
-							}
-						}
-						cStats.cs = append(cStats.cs[:remove], cStats.cs[remove+1:]...)
-						cStats.mu.Unlock()
-					}
-				}
-			}
-		}(closeChan)
 	} else {
+		for _, n := range names {
+			s := &containerStats{Name: n}
+			// no need to lock here since only the main goroutine is running here
+			cStats.cs = append(cStats.cs, s)
+			go s.Collect(cli, !*noStream)
+		}
 		close(closeChan)
-	}
-	// do a quick pause so that any failed connections for containers that do not exist are able to be
-	// evicted before we display the initial or default values.
-	time.Sleep(1500 * time.Millisecond)
-	var errs []string
-	cStats.mu.Lock()
-	for _, c := range cStats.cs {
-		c.mu.Lock()
-		if c.err != nil {
-			errs = append(errs, fmt.Sprintf("%s: %v", c.Name, c.err))
+
+		// do a quick pause so that any failed connections for containers that do not exist are able to be
+		// evicted before we display the initial or default values.
+		time.Sleep(1500 * time.Millisecond)
+		var errs []string
+		cStats.mu.Lock()
+		for _, c := range cStats.cs {
+			c.mu.Lock()
+			if c.err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", c.Name, c.err))
+			}
+			c.mu.Unlock()
+		}
+		cStats.mu.Unlock()
+		if len(errs) > 0 {
+			return fmt.Errorf("%s", strings.Join(errs, ", "))
 		}
-		c.mu.Unlock()
-	}
-	cStats.mu.Unlock()
-	if len(errs) > 0 {
-		return fmt.Errorf("%s", strings.Join(errs, ", "))
 	}
 	for range time.Tick(500 * time.Millisecond) {
 		printHeader()
@@ -293,7 +290,7 @@ func (cli *DockerCli) CmdStats(args ...string) error {
 				if err != nil {
 					// this is suppressing "unexpected EOF" in the cli when the
 					// daemon restarts so it shutdowns cleanly
-					if err == io.ErrUnexpectedEOF {
+					if err == io.EOF || err == io.ErrUnexpectedEOF {
 						return nil
 					}
 					return err
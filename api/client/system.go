@@ -0,0 +1,291 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/stringutils"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
+	"github.com/docker/go-units"
+)
+
+const (
+	pruneWarning = `WARNING! This will remove:
+	- all stopped containers
+	- all volumes not used by at least one container
+	- all networks not used by at least one container
+	- %s
+Are you sure you want to continue? [y/N] `
+	pruneFilteredWarning = `WARNING! This will remove:
+	- all stopped containers matching the filters
+	- all volumes not used by at least one container matching the filters
+	- all networks not used by at least one container matching the filters
+	- %s matching the filters
+Are you sure you want to continue? [y/N] `
+)
+
+// CmdSystem is the parent subcommand for all system commands
+//
+// Usage: docker system <COMMAND> <OPTS>
+func (cli *DockerCli) CmdSystem(args ...string) error {
+	description := Cli.DockerCommands["system"].Description + "\n\nCommands:\n"
+	commands := [][]string{
+		{"df", "Show docker disk usage"},
+		{"prune", "Remove unused data"},
+	}
+
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+
+	description += "\nRun 'docker system COMMAND --help' for more information on a command"
+	cmd := Cli.Subcmd("system", []string{"[COMMAND]"}, description, false)
+
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+// CmdSystemPrune removes the stopped containers, unused volumes and networks
+// and dangling (or all unused) images.
+//
+// Usage: docker system prune [OPTIONS]
+func (cli *DockerCli) CmdSystemPrune(args ...string) error {
+	cmd := Cli.Subcmd("system prune", nil, "Remove unused data", true)
+	all := cmd.Bool([]string{"a", "-all"}, false, "Remove all unused images not just dangling ones")
+	force := cmd.Bool([]string{"f", "-force"}, false, "Do not prompt for confirmation")
+	flFilter := opts.NewListOpts(nil)
+	cmd.Var(&flFilter, []string{"-filter"}, "Provide filter values (i.e. 'until=24h')")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	pruneFilters := filters.NewArgs()
+	for _, f := range flFilter.GetAll() {
+		var err error
+		pruneFilters, err = filters.ParseFlag(f, pruneFilters)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !*force {
+		images := "all dangling images"
+		if *all {
+			images = "all images without at least one container associated to them"
+		}
+		warning := pruneWarning
+		if pruneFilters.Len() > 0 {
+			warning = pruneFilteredWarning
+		}
+		fmt.Fprintf(cli.out, warning, images)
+		if answer := strings.ToLower(strings.TrimSpace(readInput(cli.in, cli.out))); answer != "y" && answer != "yes" {
+			return nil
+		}
+	}
+
+	var spaceReclaimed uint64
+
+	containers, err := cli.client.ContainersPrune(pruneFilters)
+	if err != nil {
+		return err
+	}
+	spaceReclaimed += containers.SpaceReclaimed
+	if len(containers.ContainersDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Containers:")
+		for _, id := range containers.ContainersDeleted {
+			fmt.Fprintln(cli.out, id)
+		}
+		fmt.Fprintln(cli.out)
+	}
+
+	volumes, err := cli.client.VolumesPrune(pruneFilters)
+	if err != nil {
+		return err
+	}
+	spaceReclaimed += volumes.SpaceReclaimed
+	if len(volumes.VolumesDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Volumes:")
+		for _, name := range volumes.VolumesDeleted {
+			fmt.Fprintln(cli.out, name)
+		}
+		fmt.Fprintln(cli.out)
+	}
+
+	networks, err := cli.client.NetworksPrune(pruneFilters)
+	if err != nil {
+		return err
+	}
+	if len(networks.NetworksDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Networks:")
+		for _, name := range networks.NetworksDeleted {
+			fmt.Fprintln(cli.out, name)
+		}
+		fmt.Fprintln(cli.out)
+	}
+
+	// The filters of the images are added to, so they are parsed again
+	// rather than shared with the other prunes.
+	imageFilters := filters.NewArgs()
+	for _, f := range flFilter.GetAll() {
+		if imageFilters, err = filters.ParseFlag(f, imageFilters); err != nil {
+			return err
+		}
+	}
+	if *all {
+		imageFilters.Add("dangling", "false")
+	}
+	images, err := cli.client.ImagesPrune(imageFilters)
+	if err != nil {
+		return err
+	}
+	spaceReclaimed += images.SpaceReclaimed
+	if len(images.ImagesDeleted) > 0 {
+		fmt.Fprintln(cli.out, "Deleted Images:")
+		for _, del := range images.ImagesDeleted {
+			if del.Deleted != "" {
+				fmt.Fprintf(cli.out, "deleted: %s\n", del.Deleted)
+			} else {
+				fmt.Fprintf(cli.out, "untagged: %s\n", del.Untagged)
+			}
+		}
+		fmt.Fprintln(cli.out)
+	}
+
+	fmt.Fprintf(cli.out, "Total reclaimed space: %s\n", units.HumanSize(float64(spaceReclaimed)))
+	return nil
+}
+
+// CmdSystemDf shows the disk space used by the images, containers and
+// local volumes of the daemon.
+//
+// Usage: docker system df [OPTIONS]
+func (cli *DockerCli) CmdSystemDf(args ...string) error {
+	cmd := Cli.Subcmd("system df", nil, "Show docker disk usage", true)
+	verbose := cmd.Bool([]string{"v", "-verbose"}, false, "Show detailed information on space usage")
+
+	cmd.Require(flag.Exact, 0)
+	cmd.ParseFlags(args, true)
+
+	du, err := cli.client.DiskUsage()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cli.out, 20, 1, 3, ' ', 0)
+	if *verbose {
+		printDiskUsageVerbose(w, du)
+	} else {
+		printDiskUsageSummary(w, du)
+	}
+	w.Flush()
+	return nil
+}
+
+func printDiskUsageSummary(w *tabwriter.Writer, du types.DiskUsage) {
+	var imagesActive int
+	var imagesReclaimable int64
+	for _, i := range du.Images {
+		if i.Containers > 0 {
+			imagesActive++
+		} else {
+			imagesReclaimable += i.Size - i.SharedSize
+		}
+	}
+
+	var containersActive int
+	var containersSize, containersReclaimable int64
+	for _, c := range du.Containers {
+		containersSize += c.SizeRw
+		if c.State == "running" || c.State == "paused" || c.State == "restarting" {
+			containersActive++
+		} else {
+			containersReclaimable += c.SizeRw
+		}
+	}
+
+	var volumesCount, volumesActive int
+	var volumesSize, volumesReclaimable int64
+	for _, v := range du.Volumes {
+		if v.UsageData == nil || v.UsageData.Size < 0 {
+			// Only local volumes have a known size
+			continue
+		}
+		volumesCount++
+		volumesSize += v.UsageData.Size
+		if v.UsageData.RefCount > 0 {
+			volumesActive++
+		} else {
+			volumesReclaimable += v.UsageData.Size
+		}
+	}
+
+	fmt.Fprintf(w, "TYPE\tTOTAL\tACTIVE\tSIZE\tRECLAIMABLE\n")
+	fmt.Fprintf(w, "Images\t%d\t%d\t%s\t%s\n", len(du.Images), imagesActive, units.HumanSize(float64(du.LayersSize)), reclaimable(imagesReclaimable, du.LayersSize))
+	fmt.Fprintf(w, "Containers\t%d\t%d\t%s\t%s\n", len(du.Containers), containersActive, units.HumanSize(float64(containersSize)), reclaimable(containersReclaimable, containersSize))
+	fmt.Fprintf(w, "Local Volumes\t%d\t%d\t%s\t%s\n", volumesCount, volumesActive, units.HumanSize(float64(volumesSize)), reclaimable(volumesReclaimable, volumesSize))
+}
+
+func printDiskUsageVerbose(w *tabwriter.Writer, du types.DiskUsage) {
+	fmt.Fprintf(w, "Images space usage:\n\n")
+	fmt.Fprintf(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE\tSHARED SIZE\tUNIQUE SIZE\tCONTAINERS\n")
+	for _, i := range du.Images {
+		id := stringid.TruncateID(strings.TrimPrefix(i.ID, "sha256:"))
+		created := units.HumanDuration(time.Now().UTC().Sub(time.Unix(i.Created, 0))) + " ago"
+		for _, repoTag := range i.RepoTags {
+			repo, tag := splitRepoTag(repoTag)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n", repo, tag, id, created,
+				units.HumanSize(float64(i.Size)), units.HumanSize(float64(i.SharedSize)),
+				units.HumanSize(float64(i.Size-i.SharedSize)), i.Containers)
+		}
+	}
+
+	fmt.Fprintf(w, "\nContainers space usage:\n\n")
+	fmt.Fprintf(w, "CONTAINER ID\tIMAGE\tCOMMAND\tSIZE\tCREATED\tSTATUS\tNAMES\n")
+	for _, c := range du.Containers {
+		var name string
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		created := units.HumanDuration(time.Now().UTC().Sub(time.Unix(c.Created, 0))) + " ago"
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", stringid.TruncateID(c.ID), c.Image,
+			strconv.Quote(stringutils.Truncate(c.Command, 20)), units.HumanSize(float64(c.SizeRw)),
+			created, c.Status, name)
+	}
+
+	fmt.Fprintf(w, "\nLocal Volumes space usage:\n\n")
+	fmt.Fprintf(w, "VOLUME NAME\tLINKS\tSIZE\n")
+	for _, v := range du.Volumes {
+		if v.UsageData == nil || v.UsageData.Size < 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", v.Name, v.UsageData.RefCount, units.HumanSize(float64(v.UsageData.Size)))
+	}
+}
+
+// reclaimable formats the reclaimable part of a total size along with its
+// percentage.
+func reclaimable(size, total int64) string {
+	if total <= 0 {
+		return units.HumanSize(float64(size))
+	}
+	return fmt.Sprintf("%s (%d%%)", units.HumanSize(float64(size)), size*100/total)
+}
+
+// splitRepoTag splits a "repository:tag" image reference, as returned in
+// the RepoTags of an image, in its repository and tag parts.
+func splitRepoTag(repoTag string) (string, string) {
+	if i := strings.LastIndex(repoTag, ":"); i > 0 && !strings.Contains(repoTag[i+1:], "/") {
+		return repoTag[:i], repoTag[i+1:]
+	}
+	return repoTag, "<none>"
+}
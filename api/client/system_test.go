@@ -0,0 +1,66 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestSplitRepoTag(t *testing.T) {
+	cases := []struct {
+		repoTag, repo, tag string
+	}{
+		{"busybox:latest", "busybox", "latest"},
+		{"localhost:5000/busybox:1.0", "localhost:5000/busybox", "1.0"},
+		{"localhost:5000/busybox", "localhost:5000/busybox", "<none>"},
+		{"<none>:<none>", "<none>", "<none>"},
+	}
+	for _, c := range cases {
+		repo, tag := splitRepoTag(c.repoTag)
+		if repo != c.repo || tag != c.tag {
+			t.Fatalf("Expected %q to be split in %q and %q, got %q and %q", c.repoTag, c.repo, c.tag, repo, tag)
+		}
+	}
+}
+
+func TestDiskUsageSummary(t *testing.T) {
+	du := types.DiskUsage{
+		LayersSize: 1000,
+		Images: []*types.Image{
+			{ID: "sha256:1", Size: 600, SharedSize: 200, Containers: 1},
+			{ID: "sha256:2", Size: 400, SharedSize: 200},
+		},
+		Containers: []*types.Container{
+			{ID: "1", SizeRw: 10, State: "running"},
+			{ID: "2", SizeRw: 30, State: "exited"},
+		},
+		Volumes: []*types.Volume{
+			{Name: "local", UsageData: &types.VolumeUsageData{Size: 50, RefCount: 0}},
+			{Name: "remote", UsageData: &types.VolumeUsageData{Size: -1, RefCount: 1}},
+		},
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 20, 1, 3, ' ', 0)
+	printDiskUsageSummary(w, du)
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %q", b.String())
+	}
+	expected := [][]string{
+		{"Images", "2", "1", "1 kB", "200 B", "(20%)"},
+		{"Containers", "2", "1", "40 B", "30 B", "(75%)"},
+		{"Local", "Volumes", "1", "0", "50 B", "50 B", "(100%)"},
+	}
+	for i, fields := range expected {
+		got := strings.Join(strings.Fields(lines[i+1]), " ")
+		if want := strings.Join(fields, " "); got != want {
+			t.Fatalf("Expected line %q, got %q", want, got)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package client
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"text/tabwriter"
 
 	Cli "github.com/docker/docker/cli"
 	"github.com/docker/docker/opts"
 	flag "github.com/docker/docker/pkg/mflag"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
 )
@@ -18,6 +22,8 @@ func (cli *DockerCli) CmdVolume(args ...string) error {
 	description := Cli.DockerCommands["volume"].Description + "\n\nCommands:\n"
 	commands := [][]string{
 		{"create", "Create a volume"},
+		{"export", "Export the content of a volume to a tar archive"},
+		{"import", "Import the content of a tar archive to a volume"},
 		{"inspect", "Return low-level information on a volume"},
 		{"ls", "List volumes"},
 		{"rm", "Remove a volume"},
@@ -116,6 +122,9 @@ func (cli *DockerCli) CmdVolumeCreate(args ...string) error {
 	flDriverOpts := opts.NewMapOpts(nil, nil)
 	cmd.Var(flDriverOpts, []string{"o", "-opt"}, "Set driver specific options")
 
+	flLabels := opts.NewListOpts(nil)
+	cmd.Var(&flLabels, []string{"-label"}, "Set metadata for a volume")
+
 	cmd.Require(flag.Exact, 0)
 	cmd.ParseFlags(args, true)
 
@@ -123,6 +132,7 @@ func (cli *DockerCli) CmdVolumeCreate(args ...string) error {
 		Driver:     *flDriver,
 		DriverOpts: flDriverOpts.GetAll(),
 		Name:       *flName,
+		Labels:     runconfigopts.ConvertKVStringsToMap(flLabels.GetAll()),
 	}
 
 	vol, err := cli.client.VolumeCreate(volReq)
@@ -158,3 +168,57 @@ func (cli *DockerCli) CmdVolumeRm(args ...string) error {
 	}
 	return nil
 }
+
+// CmdVolumeExport exports the content of a volume to a tar archive.
+//
+// The tar archive is written to STDOUT by default, or written to a file.
+//
+// Usage: docker volume export [OPTIONS] VOLUME
+func (cli *DockerCli) CmdVolumeExport(args ...string) error {
+	cmd := Cli.Subcmd("volume export", []string{"VOLUME"}, "Export the content of a volume to a tar archive (streamed to STDOUT by default)", true)
+	outfile := cmd.String([]string{"o", "-output"}, "", "Write to a file, instead of STDOUT")
+	compression := cmd.String([]string{"-compression"}, "none", "Compression of the archive (none, gzip)")
+	cmd.Require(flag.Exact, 1)
+	cmd.ParseFlags(args, true)
+
+	if *outfile == "" && cli.isTerminalOut {
+		return errors.New("Cowardly refusing to save to a terminal. Use the -o flag or redirect.")
+	}
+
+	responseBody, err := cli.client.VolumeExport(cmd.Arg(0), *compression)
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+
+	if *outfile == "" {
+		_, err := io.Copy(cli.out, responseBody)
+		return err
+	}
+
+	return copyToFile(*outfile, responseBody)
+}
+
+// CmdVolumeImport imports the content of a tar archive to a volume.
+//
+// The tar archive is read from STDIN by default, or from a tar archive file.
+//
+// Usage: docker volume import [OPTIONS] VOLUME
+func (cli *DockerCli) CmdVolumeImport(args ...string) error {
+	cmd := Cli.Subcmd("volume import", []string{"VOLUME"}, "Import the content of a tar archive to a volume", true)
+	infile := cmd.String([]string{"i", "-input"}, "", "Read from a tar archive file, instead of STDIN")
+	cmd.Require(flag.Exact, 1)
+	cmd.ParseFlags(args, true)
+
+	var input io.Reader = cli.in
+	if *infile != "" {
+		file, err := os.Open(*infile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	return cli.client.VolumeImport(cmd.Arg(0), input)
+}
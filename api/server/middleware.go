@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api"
@@ -15,6 +16,7 @@ import (
 	"github.com/docker/docker/errors"
 	"github.com/docker/docker/pkg/authorization"
 	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/metrics"
 	"github.com/docker/docker/pkg/version"
 	"golang.org/x/net/context"
 )
@@ -156,6 +158,27 @@ func versionMiddleware(handler httputils.APIFunc) httputils.APIFunc {
 	}
 }
 
+var (
+	apiRequestDuration = metrics.NewHistogram("engine_daemon_api_request_duration_seconds", "Duration of the requests to the remote API, by method and route.", metrics.DefaultBuckets, "method", "route")
+	apiRequestErrors   = metrics.NewCounter("engine_daemon_api_request_errors_total", "Number of requests to the remote API which returned an error, by method and route.", "method", "route")
+)
+
+// metricsMiddleware records the duration of the requests to the route with
+// the method, and counts the requests which returned an error.
+func metricsMiddleware(method, route string) middleware {
+	return func(handler httputils.APIFunc) httputils.APIFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+			start := time.Now()
+			err := handler(ctx, w, r, vars)
+			apiRequestDuration.ObserveSince(start, method, route)
+			if err != nil {
+				apiRequestErrors.Inc(method, route)
+			}
+			return err
+		}
+	}
+}
+
 // handleWithGlobalMiddlwares wraps the handler function for a request with
 // the server's global middlewares. The order of the middlewares is backwards,
 // meaning that the first in the list will be evaluated last.
@@ -55,3 +55,32 @@ func TestVersionMiddlewareWithErrors(t *testing.T) {
 		t.Fatalf("Expected ErrorCodeNewerClientVersion, got %v", err)
 	}
 }
+
+func TestMetricsMiddleware(t *testing.T) {
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if vars["name"] == "missing" {
+			return errors.ErrorCodeNoSuchContainer.WithArgs("missing")
+		}
+		return nil
+	}
+
+	h := metricsMiddleware("GET", "/containers/{name:.*}/json")(handler)
+	count := apiRequestDuration.Count("GET", "/containers/{name:.*}/json")
+	failed := apiRequestErrors.Value("GET", "/containers/{name:.*}/json")
+
+	req, _ := http.NewRequest("GET", "/containers/foo/json", nil)
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ = http.NewRequest("GET", "/containers/missing/json", nil)
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": "missing"}); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	if n := apiRequestDuration.Count("GET", "/containers/{name:.*}/json"); n != count+2 {
+		t.Fatalf("Expected %d requests, got %d", count+2, n)
+	}
+	if v := apiRequestErrors.Value("GET", "/containers/{name:.*}/json"); v != failed+1 {
+		t.Fatalf("Expected %v errors, got %v", failed+1, v)
+	}
+}
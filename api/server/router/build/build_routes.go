@@ -83,6 +83,7 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 	options.Dockerfile = r.FormValue("dockerfile")
 	options.SuppressOutput = httputils.BoolValue(r, "q")
 	options.NoCache = httputils.BoolValue(r, "nocache")
+	options.Squash = httputils.BoolValue(r, "squash")
 	options.ForceRemove = httputils.BoolValue(r, "forcerm")
 	options.MemorySwap = httputils.Int64ValueOrZero(r, "memswap")
 	options.Memory = httputils.Int64ValueOrZero(r, "memory")
@@ -125,6 +126,15 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 		}
 		options.BuildArgs = buildArgs
 	}
+
+	var cacheFrom = []string{}
+	cacheFromJSON := r.FormValue("cachefrom")
+	if cacheFromJSON != "" {
+		if err := json.NewDecoder(strings.NewReader(cacheFromJSON)).Decode(&cacheFrom); err != nil {
+			return nil, err
+		}
+		options.CacheFrom = cacheFrom
+	}
 	return options, nil
 }
 
@@ -10,12 +10,15 @@ import (
 	"github.com/docker/docker/pkg/version"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
+	"github.com/docker/engine-api/types/filters"
 )
 
 // execBackend includes functions to implement to provide exec functionality.
 type execBackend interface {
 	ContainerExecCreate(config *types.ExecConfig) (string, error)
 	ContainerExecInspect(id string) (*exec.Config, error)
+	ContainerExecKill(name string, sig uint64) error
+	ContainerExecList(name string) ([]*types.ExecSummary, error)
 	ContainerExecResize(name string, height, width int) error
 	ContainerExecStart(name string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error
 	ExecExists(name string) (bool, error)
@@ -39,11 +42,12 @@ type stateBackend interface {
 	ContainerResize(name string, height, width int) error
 	ContainerRestart(name string, seconds int) error
 	ContainerRm(name string, config *types.ContainerRmConfig) error
-	ContainerStart(name string, hostConfig *container.HostConfig) error
+	ContainerStart(name string, hostConfig *container.HostConfig, checkpoint string) error
 	ContainerStop(name string, seconds int) error
 	ContainerUnpause(name string) error
 	ContainerUpdate(name string, hostConfig *container.HostConfig) ([]string, error)
 	ContainerWait(name string, timeout time.Duration) (int, error)
+	ContainersPrune(pruneFilters filters.Args) (*types.ContainersPruneReport, error)
 	Exists(id string) bool
 }
 
@@ -53,6 +57,7 @@ type monitorBackend interface {
 	ContainerInspect(name string, size bool, version version.Version) (interface{}, error)
 	ContainerLogs(name string, config *daemon.ContainerLogsConfig) error
 	ContainerStats(name string, config *daemon.ContainerStatsConfig) error
+	ContainerStatsAll(config *daemon.ContainerStatsAllConfig) error
 	ContainerTop(name string, psArgs string) (*types.ContainerProcessList, error)
 
 	Containers(config *daemon.ContainersConfig) ([]*types.Container, error)
@@ -64,6 +69,13 @@ type attachBackend interface {
 	ContainerWsAttachWithLogs(name string, c *daemon.ContainerWsAttachWithLogsConfig) error
 }
 
+// checkpointBackend includes functions to implement to provide container checkpoint functionality.
+type checkpointBackend interface {
+	CheckpointCreate(name string, config types.CheckpointCreateOptions) error
+	CheckpointDelete(name, checkpointID string) error
+	CheckpointList(name string) ([]types.Checkpoint, error)
+}
+
 // Backend is all the methods that need to be implemented to provide container specific functionality.
 type Backend interface {
 	execBackend
@@ -71,4 +83,5 @@ type Backend interface {
 	stateBackend
 	monitorBackend
 	attachBackend
+	checkpointBackend
 }
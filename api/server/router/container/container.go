@@ -32,6 +32,7 @@ func (r *containerRouter) initRoutes() {
 		local.NewHeadRoute("/containers/{name:.*}/archive", r.headContainersArchive),
 		// GET
 		local.NewGetRoute("/containers/json", r.getContainersJSON),
+		local.NewGetRoute("/containers/stats", r.getContainersStatsAll),
 		local.NewGetRoute("/containers/{name:.*}/export", r.getContainersExport),
 		local.NewGetRoute("/containers/{name:.*}/changes", r.getContainersChanges),
 		local.NewGetRoute("/containers/{name:.*}/json", r.getContainersByName),
@@ -39,10 +40,13 @@ func (r *containerRouter) initRoutes() {
 		local.NewGetRoute("/containers/{name:.*}/logs", r.getContainersLogs),
 		local.NewGetRoute("/containers/{name:.*}/stats", r.getContainersStats),
 		local.NewGetRoute("/containers/{name:.*}/attach/ws", r.wsContainersAttach),
+		local.NewGetRoute("/containers/{name:.*}/exec", r.getContainerExecList),
 		local.NewGetRoute("/exec/{id:.*}/json", r.getExecByID),
 		local.NewGetRoute("/containers/{name:.*}/archive", r.getContainersArchive),
+		local.NewGetRoute("/containers/{name:.*}/checkpoints", r.getContainerCheckpoints),
 		// POST
 		local.NewPostRoute("/containers/create", r.postContainersCreate),
+		local.NewPostRoute("/containers/prune", r.postContainersPrune),
 		local.NewPostRoute("/containers/{name:.*}/kill", r.postContainersKill),
 		local.NewPostRoute("/containers/{name:.*}/pause", r.postContainersPause),
 		local.NewPostRoute("/containers/{name:.*}/unpause", r.postContainersUnpause),
@@ -56,11 +60,14 @@ func (r *containerRouter) initRoutes() {
 		local.NewPostRoute("/containers/{name:.*}/exec", r.postContainerExecCreate),
 		local.NewPostRoute("/exec/{name:.*}/start", r.postContainerExecStart),
 		local.NewPostRoute("/exec/{name:.*}/resize", r.postContainerExecResize),
+		local.NewPostRoute("/exec/{name:.*}/kill", r.postContainerExecKill),
 		local.NewPostRoute("/containers/{name:.*}/rename", r.postContainerRename),
 		local.NewPostRoute("/containers/{name:.*}/update", r.postContainerUpdate),
+		local.NewPostRoute("/containers/{name:.*}/checkpoint", r.postContainerCheckpoint),
 		// PUT
 		local.NewPutRoute("/containers/{name:.*}/archive", r.putContainersArchive),
 		// DELETE
+		local.NewDeleteRoute("/containers/{name:.*}/checkpoints/{checkpoint:.*}", r.deleteContainerCheckpoint),
 		local.NewDeleteRoute("/containers/{name:.*}", r.deleteContainers),
 	}
 }
@@ -99,6 +99,7 @@ func (r *router) initRoutes() {
 		NewPostRoute("/commit", r.postCommit),
 		NewPostRoute("/images/create", r.postImagesCreate),
 		NewPostRoute("/images/load", r.postImagesLoad),
+		NewPostRoute("/images/prune", r.postImagesPrune),
 		NewPostRoute("/images/{name:.*}/push", r.postImagesPush),
 		NewPostRoute("/images/{name:.*}/tag", r.postImagesTag),
 		// DELETE
@@ -1,6 +1,10 @@
 package network
 
 import (
+	"time"
+
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
 	"github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
 )
@@ -12,11 +16,13 @@ type Backend interface {
 	GetNetwork(idName string, by int) (libnetwork.Network, error)
 	GetNetworksByID(partialID string) []libnetwork.Network
 	GetAllNetworks() []libnetwork.Network
+	NetworkMetadata(networkID string) (time.Time, map[string]string)
 	CreateNetwork(name, driver string, ipam network.IPAM,
-		options map[string]string, internal bool) (libnetwork.Network, error)
+		options, labels map[string]string, internal bool) (libnetwork.Network, error)
 	ConnectContainerToNetwork(containerName, networkName string, endpointConfig *network.EndpointSettings) error
 	DisconnectContainerFromNetwork(containerName string,
 		network libnetwork.Network, force bool) error
 	NetworkControllerEnabled() bool
 	DeleteNetwork(name string) error
+	NetworksPrune(pruneFilters filters.Args) (*types.NetworksPruneReport, error)
 }
@@ -0,0 +1,17 @@
+package plugin
+
+import (
+	"github.com/docker/engine-api/types"
+)
+
+// Backend is the methods that need to be implemented to provide
+// plugin specific functionality
+type Backend interface {
+	Privileges(name string, metaHeaders map[string][]string, authConfig *types.AuthConfig) (types.PluginPrivileges, error)
+	Pull(name string, metaHeaders map[string][]string, authConfig *types.AuthConfig, privileges types.PluginPrivileges) (*types.Plugin, error)
+	List() []*types.Plugin
+	Inspect(name string) (*types.Plugin, error)
+	Enable(name string) error
+	Disable(name string) error
+	Remove(name string, force bool) error
+}
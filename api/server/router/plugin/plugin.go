@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"github.com/docker/docker/api/server/router"
+	"github.com/docker/docker/api/server/router/local"
+)
+
+// pluginRouter is a router to talk with the plugin manager
+type pluginRouter struct {
+	backend Backend
+	routes  []router.Route
+}
+
+// NewRouter initializes a new pluginRouter
+func NewRouter(b Backend) router.Router {
+	r := &pluginRouter{
+		backend: b,
+	}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routers to the plugin manager
+func (r *pluginRouter) Routes() []router.Route {
+	return r.routes
+}
+
+func (r *pluginRouter) initRoutes() {
+	r.routes = []router.Route{
+		// GET
+		local.NewGetRoute("/plugins", r.getPluginsList),
+		local.NewGetRoute("/plugins/privileges", r.getPluginPrivileges),
+		local.NewGetRoute("/plugins/{name:.*}/json", r.getPluginByName),
+		// POST
+		local.NewPostRoute("/plugins/pull", r.postPluginsPull),
+		local.NewPostRoute("/plugins/{name:.*}/enable", r.postPluginEnable),
+		local.NewPostRoute("/plugins/{name:.*}/disable", r.postPluginDisable),
+		// DELETE
+		local.NewDeleteRoute("/plugins/{name:.*}", r.deletePlugin),
+	}
+}
@@ -0,0 +1,107 @@
+package plugin
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+func (pr *pluginRouter) getPluginsList(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return httputils.WriteJSON(w, http.StatusOK, pr.backend.List())
+}
+
+func (pr *pluginRouter) getPluginByName(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	p, err := pr.backend.Inspect(vars["name"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, p)
+}
+
+func (pr *pluginRouter) getPluginPrivileges(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	metaHeaders, authConfig := registryHeaders(r)
+	privileges, err := pr.backend.Privileges(r.Form.Get("name"), metaHeaders, authConfig)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, privileges)
+}
+
+func (pr *pluginRouter) postPluginsPull(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	// The body lists the privileges granted to the plugin, and is empty
+	// if none are.
+	var privileges types.PluginPrivileges
+	if err := json.NewDecoder(r.Body).Decode(&privileges); err != nil && err != io.EOF {
+		return err
+	}
+
+	metaHeaders, authConfig := registryHeaders(r)
+	p, err := pr.backend.Pull(r.Form.Get("name"), metaHeaders, authConfig, privileges)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, p)
+}
+
+// registryHeaders returns the metadata headers and the registry credentials
+// sent with the request r.
+func registryHeaders(r *http.Request) (map[string][]string, *types.AuthConfig) {
+	metaHeaders := map[string][]string{}
+	for k, v := range r.Header {
+		if strings.HasPrefix(k, "X-Meta-") {
+			metaHeaders[k] = v
+		}
+	}
+
+	authEncoded := r.Header.Get("X-Registry-Auth")
+	authConfig := &types.AuthConfig{}
+	if authEncoded != "" {
+		authJSON := base64.NewDecoder(base64.URLEncoding, strings.NewReader(authEncoded))
+		if err := json.NewDecoder(authJSON).Decode(authConfig); err != nil {
+			// for a pull it is not an error if no auth was given
+			authConfig = &types.AuthConfig{}
+		}
+	}
+	return metaHeaders, authConfig
+}
+
+func (pr *pluginRouter) postPluginEnable(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := pr.backend.Enable(vars["name"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (pr *pluginRouter) postPluginDisable(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := pr.backend.Disable(vars["name"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (pr *pluginRouter) deletePlugin(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := pr.backend.Remove(vars["name"], httputils.BoolValue(r, "force")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -25,6 +25,7 @@ func NewRouter(b Backend) router.Router {
 		local.NewGetRoute("/events", r.getEvents),
 		local.NewGetRoute("/info", r.getInfo),
 		local.NewGetRoute("/version", r.getVersion),
+		local.NewGetRoute("/system/df", r.getDiskUsage),
 		local.NewPostRoute("/auth", r.postAuth),
 	}
 
@@ -2,6 +2,7 @@ package system
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -42,24 +43,39 @@ func (s *systemRouter) getVersion(ctx context.Context, w http.ResponseWriter, r
 	return httputils.WriteJSON(w, http.StatusOK, info)
 }
 
+func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	du, err := s.backend.SystemDiskUsage()
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, du)
+}
+
 func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
 	}
-	since, sinceNano, err := timetypes.ParseTimestamps(r.Form.Get("since"), -1)
+	since, err := eventTime(r.Form.Get("since"))
 	if err != nil {
 		return err
 	}
-	until, untilNano, err := timetypes.ParseTimestamps(r.Form.Get("until"), -1)
+	until, err := eventTime(r.Form.Get("until"))
 	if err != nil {
 		return err
 	}
+	if !until.IsZero() && until.Before(since) {
+		return fmt.Errorf("`since` time (%s) cannot be after `until` time (%s)", r.Form.Get("since"), r.Form.Get("until"))
+	}
 
-	timer := time.NewTimer(0)
-	timer.Stop()
-	if until > 0 || untilNano > 0 {
-		dur := time.Unix(until, untilNano).Sub(time.Now())
-		timer = time.NewTimer(dur)
+	var timeout <-chan time.Time
+	// onlyPastEvents is set when all the requested events are already
+	// recorded, in which case there is no need to wait for new ones.
+	onlyPastEvents := !until.IsZero() && until.Before(time.Now())
+	if !until.IsZero() && !onlyPastEvents {
+		timer := time.NewTimer(until.Sub(time.Now()))
+		defer timer.Stop()
+		timeout = timer.C
 	}
 
 	ef, err := filters.FromParam(r.Form.Get("filters"))
@@ -81,7 +97,7 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 
 	enc := json.NewEncoder(output)
 
-	buffered, l := s.backend.SubscribeToEvents(since, sinceNano, ef)
+	buffered, l := s.backend.SubscribeToEvents(since, until, ef)
 	defer s.backend.UnsubscribeFromEvents(l)
 
 	for _, ev := range buffered {
@@ -90,6 +106,10 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 		}
 	}
 
+	if onlyPastEvents {
+		return nil
+	}
+
 	var closeNotify <-chan bool
 	if closeNotifier, ok := w.(http.CloseNotifier); ok {
 		closeNotify = closeNotifier.CloseNotify()
@@ -106,7 +126,7 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 			if err := enc.Encode(jev); err != nil {
 				return err
 			}
-		case <-timer.C:
+		case <-timeout:
 			return nil
 		case <-closeNotify:
 			logrus.Debug("Client disconnected, stop sending events")
@@ -115,6 +135,19 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 	}
 }
 
+// eventTime parses a since or until parameter of the events endpoint.
+// An empty value results in a zero time.
+func eventTime(formTime string) (time.Time, error) {
+	t, tNano, err := timetypes.ParseTimestamps(formTime, -1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t == -1 {
+		return time.Time{}, nil
+	}
+	return time.Unix(t, tNano), nil
+}
+
 func (s *systemRouter) postAuth(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	var config *types.AuthConfig
 	err := json.NewDecoder(r.Body).Decode(&config)
@@ -1,8 +1,12 @@
 package volume
 
 import (
+	"io"
+
+	"github.com/docker/docker/pkg/archive"
 	// TODO return types need to be refactored into pkg
 	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
 )
 
 // Backend is the methods that need to be implemented to provide
@@ -10,7 +14,9 @@ import (
 type Backend interface {
 	Volumes(filter string) ([]*types.Volume, []string, error)
 	VolumeInspect(name string) (*types.Volume, error)
-	VolumeCreate(name, driverName string,
-		opts map[string]string) (*types.Volume, error)
+	VolumeCreate(name, driverName string, opts, labels map[string]string) (*types.Volume, error)
 	VolumeRm(name string) error
+	VolumesPrune(pruneFilters filters.Args) (*types.VolumesPruneReport, error)
+	VolumeArchive(name string, compression archive.Compression) (io.ReadCloser, error)
+	VolumeExtract(name string, content io.Reader) error
 }
@@ -29,9 +29,13 @@ func (r *volumeRouter) initRoutes() {
 	r.routes = []router.Route{
 		// GET
 		local.NewGetRoute("/volumes", r.getVolumesList),
+		local.NewGetRoute("/volumes/{name:.*}/archive", r.getVolumeArchive),
 		local.NewGetRoute("/volumes/{name:.*}", r.getVolumeByName),
 		// POST
 		local.NewPostRoute("/volumes/create", r.postVolumesCreate),
+		local.NewPostRoute("/volumes/prune", r.postVolumesPrune),
+		// PUT
+		local.NewPutRoute("/volumes/{name:.*}/archive", r.putVolumeArchive),
 		// DELETE
 		local.NewDeleteRoute("/volumes/{name:.*}", r.deleteVolumes),
 	}
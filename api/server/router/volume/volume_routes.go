@@ -2,10 +2,14 @@ package volume
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
 	"golang.org/x/net/context"
 )
 
@@ -47,7 +51,7 @@ func (v *volumeRouter) postVolumesCreate(ctx context.Context, w http.ResponseWri
 		return err
 	}
 
-	volume, err := v.backend.VolumeCreate(req.Name, req.Driver, req.DriverOpts)
+	volume, err := v.backend.VolumeCreate(req.Name, req.Driver, req.DriverOpts, req.Labels)
 	if err != nil {
 		return err
 	}
@@ -64,3 +68,54 @@ func (v *volumeRouter) deleteVolumes(ctx context.Context, w http.ResponseWriter,
 	w.WriteHeader(http.StatusNoContent)
 	return nil
 }
+
+func (v *volumeRouter) postVolumesPrune(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	pruneFilters, err := filters.FromParam(r.Form.Get("filters"))
+	if err != nil {
+		return err
+	}
+
+	pruneReport, err := v.backend.VolumesPrune(pruneFilters)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, pruneReport)
+}
+
+func (v *volumeRouter) getVolumeArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	var compression archive.Compression
+	switch c := r.Form.Get("compression"); c {
+	case "", "none":
+		compression = archive.Uncompressed
+	case "gzip":
+		compression = archive.Gzip
+	default:
+		return fmt.Errorf("bad parameter: unsupported compression %q", c)
+	}
+
+	content, err := v.backend.VolumeArchive(vars["name"], compression)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if compression == archive.Gzip {
+		w.Header().Set("Content-Type", "application/x-gzip")
+	} else {
+		w.Header().Set("Content-Type", "application/x-tar")
+	}
+	_, err = io.Copy(w, content)
+	return err
+}
+
+func (v *volumeRouter) putVolumeArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return v.backend.VolumeExtract(vars["name"], r.Body)
+}
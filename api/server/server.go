@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/api/server/router/container"
 	"github.com/docker/docker/api/server/router/local"
 	"github.com/docker/docker/api/server/router/network"
+	pluginrouter "github.com/docker/docker/api/server/router/plugin"
 	"github.com/docker/docker/api/server/router/system"
 	"github.com/docker/docker/api/server/router/volume"
 	"github.com/docker/docker/daemon"
@@ -146,7 +147,7 @@ func (s *Server) initTCPSocket(addr string) (l net.Listener, err error) {
 	return
 }
 
-func (s *Server) makeHTTPHandler(handler httputils.APIFunc) http.HandlerFunc {
+func (s *Server) makeHTTPHandler(method, route string, handler httputils.APIFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// log the handler call
 		logrus.Debugf("Calling %s %s", r.Method, r.URL.Path)
@@ -159,7 +160,7 @@ func (s *Server) makeHTTPHandler(handler httputils.APIFunc) http.HandlerFunc {
 		// immediate function being called should still be passed
 		// as 'args' on the function call.
 		ctx := context.Background()
-		handlerFunc := s.handleWithGlobalMiddlewares(handler)
+		handlerFunc := metricsMiddleware(method, route)(s.handleWithGlobalMiddlewares(handler))
 
 		vars := mux.Vars(r)
 		if vars == nil {
@@ -181,6 +182,7 @@ func (s *Server) InitRouters(d *daemon.Daemon) {
 	s.addRouter(system.NewRouter(d))
 	s.addRouter(volume.NewRouter(d))
 	s.addRouter(build.NewRouter(d))
+	s.addRouter(pluginrouter.NewRouter(d.PluginManager()))
 }
 
 // addRouter adds a new router to the server.
@@ -198,7 +200,7 @@ func (s *Server) createMux() *mux.Router {
 	logrus.Debugf("Registering routers")
 	for _, apiRouter := range s.routers {
 		for _, r := range apiRouter.Routes() {
-			f := s.makeHTTPHandler(r.Handler())
+			f := s.makeHTTPHandler(r.Method(), r.Path(), r.Handler())
 
 			logrus.Debugf("Registering %s, %s", r.Method(), r.Path())
 			m.Path(versionMatcher + r.Path()).Methods(r.Method()).Handler(f)
@@ -113,7 +113,7 @@ type Backend interface {
 	// Kill stops the container execution abruptly.
 	ContainerKill(containerID string, sig uint64) error
 	// Start starts a new container
-	ContainerStart(containerID string, hostConfig *container.HostConfig) error
+	ContainerStart(containerID string, hostConfig *container.HostConfig, checkpoint string) error
 	// ContainerWait stops processing until the given container is stopped.
 	ContainerWait(containerID string, timeout time.Duration) (int, error)
 
@@ -128,6 +128,19 @@ type Backend interface {
 	//ContainerCopy(name string, res string) (io.ReadCloser, error)
 	// TODO: use copyBackend api
 	BuilderCopy(containerID string, destPath string, src FileInfo, decompress bool) error
+
+	// MountImage mounts the root filesystem of the image referenced by `name`
+	// and returns its path along with a function to release the mount.
+	MountImage(name string) (string, func() error, error)
+
+	// MakeImageCache returns the cache to look up the images of the build
+	// steps in, using the images referenced by `cacheFrom` as cache sources
+	// in addition to the images built locally.
+	MakeImageCache(cacheFrom []string) ImageCache
+
+	// SquashImage creates a new image from the image `id`, with all its
+	// layers above the image `parent` merged into a single one.
+	SquashImage(id, parent string) (string, error)
 }
 
 // ImageCache abstracts an image cache store.
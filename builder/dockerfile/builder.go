@@ -49,8 +49,9 @@ type Builder struct {
 	Stdout io.Writer
 	Stderr io.Writer
 
-	docker  builder.Backend
-	context builder.Context
+	docker     builder.Backend
+	context    builder.Context
+	imageCache builder.ImageCache
 
 	dockerfile       *parser.Node
 	runConfig        *container.Config // runconfig for cmd, run, entrypoint etc.
@@ -65,12 +66,20 @@ type Builder struct {
 	cancelled        chan struct{}
 	cancelOnce       sync.Once
 	allowedBuildArgs map[string]bool // list of build-time args that are allowed for expansion/substitution and passing to commands in 'run'.
+	stages           []*buildStage   // stages of a multi-stage build, the last one is the stage being built
 
 	// TODO: remove once docker.Commit can receive a tag
 	id     string
 	Output io.Writer
 }
 
+// buildStage is a stage of a multi-stage build, started by a FROM instruction.
+type buildStage struct {
+	name  string // optional name, set with FROM image AS name
+	base  string // imageID of the image the stage is built on, empty for scratch
+	image string // imageID of the last image built by the stage
+}
+
 // NewBuilder creates a new Dockerfile builder from an optional dockerfile and a Config.
 // If dockerfile is nil, the Dockerfile specified by Config.DockerfileName,
 // will be read from the Context passed to Build().
@@ -93,6 +102,9 @@ func NewBuilder(config *types.ImageBuildOptions, backend builder.Backend, contex
 		id:               stringid.GenerateNonCryptoID(),
 		allowedBuildArgs: make(map[string]bool),
 	}
+	if backend != nil {
+		b.imageCache = backend.MakeImageCache(config.CacheFrom)
+	}
 	if dockerfile != nil {
 		b.dockerfile, err = parser.Parse(dockerfile)
 		if err != nil {
@@ -163,10 +175,33 @@ func (b *Builder) Build() (string, error) {
 		return "", fmt.Errorf("No image was generated. Is your Dockerfile empty?")
 	}
 
+	var base string
+	if len(b.stages) > 0 {
+		base = b.stages[len(b.stages)-1].base
+	}
+	if b.options.Squash && b.image != base {
+		fmt.Fprintf(b.Stdout, "Squashing the layers above %s\n", baseName(base))
+		image, err := b.docker.SquashImage(b.image, base)
+		if err != nil {
+			return "", err
+		}
+		b.image = image
+		shortImgID = stringid.TruncateID(b.image)
+		fmt.Fprintf(b.Stdout, " ---> %s\n", shortImgID)
+	}
+
 	fmt.Fprintf(b.Stdout, "Successfully built %s\n", shortImgID)
 	return b.image, nil
 }
 
+// baseName returns the name of a base image to print in the build output.
+func baseName(imageID string) string {
+	if imageID == "" {
+		return "scratch"
+	}
+	return stringid.TruncateID(imageID)
+}
+
 // Cancel cancels an ongoing Dockerfile build.
 func (b *Builder) Cancel() {
 	b.cancelOnce.Do(func() {
@@ -14,7 +14,9 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api"
@@ -159,32 +161,62 @@ func add(b *Builder, args []string, attributes map[string]bool, original string)
 		return err
 	}
 
-	return b.runContextCommand(args, true, true, "ADD")
+	return b.runContextCommand(b.context, args, true, true, "ADD")
 }
 
-// COPY foo /path
+// COPY [--from=<stage|image>] foo /path
 //
-// Same as 'ADD' but without the tar and remote url handling.
+// Same as 'ADD' but without the tar and remote url handling. With --from, the
+// files are copied from the root filesystem of a previous build stage, given
+// by name or index, or of an image instead of from the build context.
 //
 func dispatchCopy(b *Builder, args []string, attributes map[string]bool, original string) error {
 	if len(args) < 2 {
 		return derr.ErrorCodeAtLeastTwoArgs.WithArgs("COPY")
 	}
 
+	flFrom := b.flags.AddString("from", "")
+
 	if err := b.flags.Parse(); err != nil {
 		return err
 	}
 
-	return b.runContextCommand(args, false, false, "COPY")
+	context := b.context
+	if flFrom.IsUsed() {
+		if flFrom.Value == "" {
+			return fmt.Errorf("COPY --from requires a build stage or an image")
+		}
+		var err error
+		context, err = b.rootFSContext(flFrom.Value)
+		if err != nil {
+			return err
+		}
+		defer context.Close()
+	}
+
+	return b.runContextCommand(context, args, false, false, "COPY")
 }
 
-// FROM imagename
+// FROM imagename [AS name]
 //
-// This sets the image the dockerfile will build on top of.
+// This sets the image the dockerfile will build on top of. Each FROM starts
+// a new build stage, which can be named so that the next stages can use it
+// as their base image or copy files from it.
 //
 func from(b *Builder, args []string, attributes map[string]bool, original string) error {
-	if len(args) != 1 {
-		return derr.ErrorCodeExactlyOneArg.WithArgs("FROM")
+	var stageName string
+	switch len(args) {
+	case 1:
+	case 3:
+		if !strings.EqualFold(args[1], "as") {
+			return fmt.Errorf("FROM requires either one argument, or three: FROM <image> AS <name>")
+		}
+		stageName = strings.ToLower(args[2])
+		if err := b.validateStageName(stageName); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("FROM requires either one argument, or three: FROM <image> AS <name>")
 	}
 
 	if err := b.flags.Parse(); err != nil {
@@ -192,6 +224,7 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 	}
 
 	name := args[0]
+	b.startStage(stageName)
 
 	var (
 		image builder.Image
@@ -205,20 +238,25 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 		}
 		b.image = ""
 		b.noBaseImage = true
-	} else {
-		// TODO: don't use `name`, instead resolve it to a digest
-		if !b.options.PullParent {
-			image, err = b.docker.GetImage(name)
-			// TODO: shouldn't we error out if error is different from "not found" ?
+	} else if stage := b.namedStage(name); stage != nil {
+		if stage.image == "" {
+			return fmt.Errorf("Build stage %s did not produce an image", name)
 		}
-		if image == nil {
-			image, err = b.docker.Pull(name, b.options.AuthConfigs, b.Output)
-			if err != nil {
-				return err
-			}
+		image, err = b.docker.GetImage(stage.image)
+		if err != nil {
+			return err
+		}
+	} else {
+		image, err = b.getImage(name)
+		if err != nil {
+			return err
 		}
 	}
 
+	if image != nil {
+		b.stages[len(b.stages)-1].base = image.ID()
+	}
+
 	return b.processImageFrom(image)
 }
 
@@ -631,3 +669,103 @@ func arg(b *Builder, args []string, attributes map[string]bool, original string)
 
 	return b.commit("", b.runConfig.Cmd, fmt.Sprintf("ARG %s", arg))
 }
+
+// HEALTHCHECK foo
+//
+// Set the default healthcheck command to run in the container (which may be empty).
+// Argument handling is the same as RUN.
+//
+func healthcheck(b *Builder, args []string, attributes map[string]bool, original string) error {
+	if len(args) == 0 {
+		return derr.ErrorCodeAtLeastOneArg.WithArgs("HEALTHCHECK")
+	}
+	typ := strings.ToUpper(args[0])
+	args = args[1:]
+	if typ == "NONE" {
+		if len(args) != 0 {
+			return fmt.Errorf("HEALTHCHECK NONE takes no arguments")
+		}
+		b.runConfig.Healthcheck = &container.HealthConfig{
+			Test: []string{typ},
+		}
+	} else {
+		if b.runConfig.Healthcheck != nil {
+			oldCmd := b.runConfig.Healthcheck.Test
+			if len(oldCmd) > 0 && oldCmd[0] != "NONE" {
+				fmt.Fprintf(b.Stdout, "Note: overriding previous HEALTHCHECK: %v\n", oldCmd)
+			}
+		}
+
+		healthcheck := container.HealthConfig{}
+
+		flInterval := b.flags.AddString("interval", "")
+		flTimeout := b.flags.AddString("timeout", "")
+		flRetries := b.flags.AddString("retries", "")
+
+		if err := b.flags.Parse(); err != nil {
+			return err
+		}
+
+		switch typ {
+		case "CMD":
+			cmdSlice := handleJSONArgs(args, attributes)
+			if len(cmdSlice) == 0 {
+				return fmt.Errorf("Missing command after HEALTHCHECK CMD")
+			}
+
+			if !attributes["json"] {
+				typ = "CMD-SHELL"
+			}
+
+			healthcheck.Test = append([]string{typ}, cmdSlice...)
+		default:
+			return fmt.Errorf("Unknown type %#v in HEALTHCHECK (try CMD)", typ)
+		}
+
+		interval, err := parseOptInterval(flInterval)
+		if err != nil {
+			return err
+		}
+		healthcheck.Interval = interval
+
+		timeout, err := parseOptInterval(flTimeout)
+		if err != nil {
+			return err
+		}
+		healthcheck.Timeout = timeout
+
+		if flRetries.Value != "" {
+			retries, err := strconv.ParseInt(flRetries.Value, 10, 32)
+			if err != nil {
+				return err
+			}
+			if retries < 1 {
+				return fmt.Errorf("--retries must be at least 1 (not %d)", retries)
+			}
+			healthcheck.Retries = int(retries)
+		} else {
+			healthcheck.Retries = 0
+		}
+
+		b.runConfig.Healthcheck = &healthcheck
+	}
+
+	return b.commit("", b.runConfig.Cmd, fmt.Sprintf("HEALTHCHECK %+v", *b.runConfig.Healthcheck))
+}
+
+// parseOptInterval(flag) is the duration of flag.Value, or 0 if
+// empty. An error is reported if the value is given and is not positive.
+func parseOptInterval(f *Flag) (time.Duration, error) {
+	s := f.Value
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("Interval %#v must be positive", f.name)
+	}
+	return d, nil
+}
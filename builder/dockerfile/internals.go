@@ -13,8 +13,10 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,6 +40,9 @@ import (
 	"github.com/docker/engine-api/types/strslice"
 )
 
+// validStageName matches the names which can be given to build stages.
+var validStageName = regexp.MustCompile(`^[a-z][a-z0-9-_\.]*$`)
+
 func (b *Builder) commit(id string, autoCmd *strslice.StrSlice, comment string) error {
 	if b.disableCommit {
 		return nil
@@ -91,8 +96,8 @@ type copyInfo struct {
 	decompress bool
 }
 
-func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string) error {
-	if b.context == nil {
+func (b *Builder) runContextCommand(context builder.Context, args []string, allowRemote bool, allowLocalDecompression bool, cmdName string) error {
+	if context == nil {
 		return fmt.Errorf("No context given. Impossible to use %s", cmdName)
 	}
 
@@ -128,7 +133,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 			continue
 		}
 		// not a URL
-		subInfos, err := b.calcCopyInfo(cmdName, orig, allowLocalDecompression, true)
+		subInfos, err := b.calcCopyInfo(context, cmdName, orig, allowLocalDecompression, true)
 		if err != nil {
 			return err
 		}
@@ -302,7 +307,7 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 	return &builder.HashedFileInfo{FileInfo: builder.PathFileInfo{FileInfo: tmpFileSt, FilePath: tmpFileName}, FileHash: hash}, nil
 }
 
-func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
+func (b *Builder) calcCopyInfo(context builder.Context, cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
 
 	// Work in daemon-specific OS filepath semantics
 	origPath = filepath.FromSlash(origPath)
@@ -315,7 +320,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	// Deal with wildcards
 	if allowWildcards && containsWildcards(origPath) {
 		var copyInfos []copyInfo
-		if err := b.context.Walk("", func(path string, info builder.FileInfo, err error) error {
+		if err := context.Walk("", func(path string, info builder.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
@@ -329,7 +334,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 			// Note we set allowWildcards to false in case the name has
 			// a * in it
-			subInfos, err := b.calcCopyInfo(cmdName, path, allowLocalDecompression, false)
+			subInfos, err := b.calcCopyInfo(context, cmdName, path, allowLocalDecompression, false)
 			if err != nil {
 				return err
 			}
@@ -343,7 +348,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 	// Must be a dir or a file
 
-	statPath, fi, err := b.context.Stat(origPath)
+	statPath, fi, err := context.Stat(origPath)
 	if err != nil {
 		return nil, err
 	}
@@ -362,7 +367,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	}
 	// Must be a dir
 	var subfiles []string
-	err = b.context.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
+	err = context.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -394,6 +399,104 @@ func containsWildcards(name string) bool {
 	return false
 }
 
+// getImage looks up the image referenced by name, and pulls it if it is not
+// found or if the build always pulls the images.
+func (b *Builder) getImage(name string) (builder.Image, error) {
+	var image builder.Image
+	// TODO: don't use `name`, instead resolve it to a digest
+	if !b.options.PullParent {
+		image, _ = b.docker.GetImage(name)
+		// TODO: shouldn't we error out if error is different from "not found" ?
+	}
+	if image == nil {
+		return b.docker.Pull(name, b.options.AuthConfigs, b.Output)
+	}
+	return image, nil
+}
+
+// validateStageName checks that name can be used to refer to a new build
+// stage, and that no other stage has the same name.
+func (b *Builder) validateStageName(name string) error {
+	if !validStageName.MatchString(name) {
+		return fmt.Errorf("Invalid name for build stage: %q, name can't start with a number or contain symbols", name)
+	}
+	for _, stage := range b.stages {
+		if stage.name == name {
+			return fmt.Errorf("Duplicate name for build stage: %q", name)
+		}
+	}
+	return nil
+}
+
+// startStage records the image built by the current build stage, if any, and
+// starts a new one with a fresh configuration.
+func (b *Builder) startStage(name string) {
+	if len(b.stages) > 0 {
+		b.stages[len(b.stages)-1].image = b.image
+
+		b.runConfig = new(container.Config)
+		b.image = ""
+		b.noBaseImage = false
+		b.maintainer = ""
+		b.cmdSet = false
+		b.cacheBusted = false
+	}
+	b.stages = append(b.stages, &buildStage{name: name})
+}
+
+// previousStages returns the build stages before the current one.
+func (b *Builder) previousStages() []*buildStage {
+	if len(b.stages) == 0 {
+		return nil
+	}
+	return b.stages[:len(b.stages)-1]
+}
+
+// namedStage returns the previous build stage with the given name, or nil if
+// there is none.
+func (b *Builder) namedStage(name string) *buildStage {
+	for _, stage := range b.previousStages() {
+		if stage.name != "" && stage.name == strings.ToLower(name) {
+			return stage
+		}
+	}
+	return nil
+}
+
+// rootFSContext returns a build Context over the root filesystem of a
+// previous build stage, referred to by name or index, or of an image.
+func (b *Builder) rootFSContext(ref string) (builder.Context, error) {
+	var imageID string
+	stage := b.namedStage(ref)
+	if stage == nil {
+		if i, err := strconv.Atoi(ref); err == nil {
+			stages := b.previousStages()
+			if i < 0 || i >= len(stages) {
+				return nil, fmt.Errorf("Invalid build stage index %d, only %d previous stages", i, len(stages))
+			}
+			stage = stages[i]
+		}
+	}
+	if stage != nil {
+		if stage.image == "" {
+			return nil, fmt.Errorf("Build stage %s did not produce an image", ref)
+		}
+		imageID = stage.image
+	} else {
+		img, err := b.getImage(ref)
+		if err != nil {
+			return nil, err
+		}
+		imageID = img.ID()
+	}
+
+	root, release, err := b.docker.MountImage(imageID)
+	if err != nil {
+		return nil, err
+	}
+	return builder.MakeRootFSContext(root, imageID, release), nil
+}
+
 func (b *Builder) processImageFrom(img builder.Image) error {
 	if img != nil {
 		b.image = img.ID()
@@ -458,18 +561,17 @@ func (b *Builder) processImageFrom(img builder.Image) error {
 	return nil
 }
 
-// probeCache checks if `b.docker` implements builder.ImageCache and image-caching
+// probeCache checks if the builder has an image cache and image-caching
 // is enabled (`b.UseCache`).
-// If so attempts to look up the current `b.image` and `b.runConfig` pair with `b.docker`.
+// If so attempts to look up the current `b.image` and `b.runConfig` pair with `b.imageCache`.
 // If an image is found, probeCache returns `(true, nil)`.
 // If no image is found, it returns `(false, nil)`.
 // If there is any error, it returns `(false, err)`.
 func (b *Builder) probeCache() (bool, error) {
-	c, ok := b.docker.(builder.ImageCache)
-	if !ok || b.options.NoCache || b.cacheBusted {
+	if b.imageCache == nil || b.options.NoCache || b.cacheBusted {
 		return false, nil
 	}
-	cache, err := c.GetCachedImage(b.image, b.runConfig)
+	cache, err := b.imageCache.GetCachedImage(b.image, b.runConfig)
 	if err != nil {
 		return false, err
 	}
@@ -556,7 +658,7 @@ func (b *Builder) run(cID string) (err error) {
 		}
 	}()
 
-	if err := b.docker.ContainerStart(cID, nil); err != nil {
+	if err := b.docker.ContainerStart(cID, nil, ""); err != nil {
 		return err
 	}
 
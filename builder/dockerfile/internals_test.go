@@ -0,0 +1,107 @@
+package dockerfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/builder"
+	"github.com/docker/engine-api/types/container"
+)
+
+// mountBackend is a builder.Backend which mounts every image on the same
+// directory.
+type mountBackend struct {
+	builder.Backend
+	root    string
+	mounted []string
+}
+
+func (m *mountBackend) MountImage(name string) (string, func() error, error) {
+	m.mounted = append(m.mounted, name)
+	return m.root, func() error { return nil }, nil
+}
+
+func TestBuildStages(t *testing.T) {
+	b := &Builder{runConfig: new(container.Config)}
+
+	b.startStage("build")
+	b.image = "sha256:build"
+	b.cacheBusted = true
+	b.runConfig.WorkingDir = "/go/src/app"
+
+	if err := b.validateStageName("build"); err == nil {
+		t.Fatal("Expected an error for a duplicate stage name")
+	}
+	for _, name := range []string{"0", "-build", "bui/ld"} {
+		if err := b.validateStageName(name); err == nil {
+			t.Fatalf("Expected an error for invalid stage name %q", name)
+		}
+	}
+
+	b.startStage("")
+	if b.image != "" || b.cacheBusted || b.runConfig.WorkingDir != "" {
+		t.Fatalf("Expected a new stage to start from scratch, got image %q, cacheBusted %v and config %+v", b.image, b.cacheBusted, b.runConfig)
+	}
+	if stage := b.namedStage("BUILD"); stage == nil || stage.image != "sha256:build" {
+		t.Fatalf("Expected to find the build stage, got %+v", stage)
+	}
+	if err := b.validateStageName("build2"); err != nil {
+		t.Fatal(err)
+	}
+
+	b.startStage("final")
+	if stage := b.namedStage("final"); stage != nil {
+		t.Fatalf("Expected the current stage not to be found, got %+v", stage)
+	}
+}
+
+func TestRootFSContext(t *testing.T) {
+	root, err := ioutil.TempDir("", "builder-rootfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := ioutil.WriteFile(filepath.Join(root, "app"), []byte("app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &mountBackend{root: root}
+	b := &Builder{runConfig: new(container.Config), docker: backend}
+	b.startStage("build")
+	b.image = "sha256:build"
+	b.startStage("")
+	b.image = "sha256:second"
+	b.startStage("")
+
+	for _, ref := range []string{"build", "0", "1"} {
+		ctx, err := b.rootFSContext(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, fi, err := ctx.Stat("/app")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Path() != filepath.Join(root, "app") {
+			t.Fatalf("Expected app to be at %s, got %s", filepath.Join(root, "app"), fi.Path())
+		}
+		if err := ctx.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []string{"sha256:build", "sha256:build", "sha256:second"}
+	if len(backend.mounted) != len(expected) {
+		t.Fatalf("Expected %v to be mounted, got %v", expected, backend.mounted)
+	}
+	for i := range expected {
+		if backend.mounted[i] != expected[i] {
+			t.Fatalf("Expected %v to be mounted, got %v", expected, backend.mounted)
+		}
+	}
+
+	if _, err := b.rootFSContext("2"); err == nil {
+		t.Fatal("Expected an error for the index of the current stage")
+	}
+}
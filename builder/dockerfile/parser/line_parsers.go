@@ -329,3 +329,41 @@ func parseMaybeJSONToList(rest string) (*Node, map[string]bool, error) {
 
 	return parseStringsWhitespaceDelimited(rest)
 }
+
+// parseHealthConfig returns a rootnode representing a health check.
+//
+// HEALTHCHECK NONE                -> (healthcheck "NONE")
+// HEALTHCHECK CMD ["curl", "-f"]  -> (healthcheck "CMD" "curl" "-f")
+//
+func parseHealthConfig(rest string) (*Node, map[string]bool, error) {
+	if rest == "" {
+		return nil, nil, nil
+	}
+
+	// First word is "CMD" or "NONE"
+	var typ string
+	var cmd string
+	i := strings.IndexFunc(rest, unicode.IsSpace)
+	if i < 0 {
+		typ = rest
+	} else {
+		typ = rest[:i]
+		cmd = strings.TrimLeftFunc(rest[i+1:], unicode.IsSpace)
+	}
+	typ = strings.ToUpper(typ)
+
+	if typ == "CMD" {
+		cmdNode, attrs, err := parseMaybeJSON(cmd)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &Node{Value: typ, Next: cmdNode}, attrs, nil
+	}
+
+	node := &Node{Value: typ}
+	if cmd != "" {
+		node.Next = &Node{Value: cmd}
+	}
+	return node, nil, nil
+}
@@ -0,0 +1,101 @@
+package builder
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/symlink"
+)
+
+// rootFSContext is a read-only Context over the mounted root filesystem of
+// an image. The content of an image never changes, so the hash of a file is
+// made of the image ID and the path of the file.
+type rootFSContext struct {
+	root    string
+	imageID string
+	release func() error
+}
+
+// MakeRootFSContext returns a build Context from the root filesystem of the
+// image imageID, mounted at root. release is called when the Context is
+// closed, to unmount the root filesystem.
+func MakeRootFSContext(root, imageID string, release func() error) Context {
+	return &rootFSContext{root: root, imageID: imageID, release: release}
+}
+
+func (c *rootFSContext) Close() error {
+	if c.release == nil {
+		return nil
+	}
+	return c.release()
+}
+
+func (c *rootFSContext) Open(path string) (io.ReadCloser, error) {
+	cleanpath, fullpath, err := c.normalize(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := os.Open(fullpath)
+	if err != nil {
+		return nil, convertPathError(err, cleanpath)
+	}
+	return r, nil
+}
+
+func (c *rootFSContext) Stat(path string) (string, FileInfo, error) {
+	cleanpath, fullpath, err := c.normalize(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	st, err := os.Lstat(fullpath)
+	if err != nil {
+		return "", nil, convertPathError(err, cleanpath)
+	}
+
+	rel, err := filepath.Rel(c.root, fullpath)
+	if err != nil {
+		return "", nil, convertPathError(err, cleanpath)
+	}
+
+	fi := &HashedFileInfo{PathFileInfo{st, fullpath, filepath.Base(cleanpath)}, c.sum(rel)}
+	return rel, fi, nil
+}
+
+func (c *rootFSContext) Walk(root string, walkFn WalkFunc) error {
+	root = filepath.Join(c.root, filepath.Join(string(filepath.Separator), root))
+	return filepath.Walk(root, func(fullpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(c.root, fullpath)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		fi := &HashedFileInfo{PathFileInfo{FileInfo: info, FilePath: fullpath}, c.sum(rel)}
+		return walkFn(rel, fi, nil)
+	})
+}
+
+func (c *rootFSContext) sum(rel string) string {
+	return c.imageID + ":" + filepath.ToSlash(rel)
+}
+
+func (c *rootFSContext) normalize(path string) (cleanpath, fullpath string, err error) {
+	cleanpath = filepath.Clean(string(os.PathSeparator) + path)[1:]
+	fullpath, err = symlink.FollowSymlinkInScope(filepath.Join(c.root, path), c.root)
+	if err != nil {
+		return "", "", fmt.Errorf("Forbidden path outside the image root filesystem: %s (%s)", path, fullpath)
+	}
+	_, err = os.Lstat(fullpath)
+	if err != nil {
+		return "", "", convertPathError(err, path)
+	}
+	return
+}
@@ -28,6 +28,7 @@ type Command struct {
 var dockerCommands = []Command{
 	{"attach", "Attach to a running container"},
 	{"build", "Build an image from a Dockerfile"},
+	{"checkpoint", "Manage checkpoints of containers"},
 	{"commit", "Create a new image from a container's changes"},
 	{"cp", "Copy files/folders between a container and the local filesystem"},
 	{"create", "Create a new container"},
@@ -47,6 +48,7 @@ var dockerCommands = []Command{
 	{"logs", "Fetch the logs of a container"},
 	{"network", "Manage Docker networks"},
 	{"pause", "Pause all processes within a container"},
+	{"plugin", "Manage plugins"},
 	{"port", "List port mappings or a specific mapping for the CONTAINER"},
 	{"ps", "List containers"},
 	{"pull", "Pull an image or a repository from a registry"},
@@ -61,6 +63,7 @@ var dockerCommands = []Command{
 	{"start", "Start one or more stopped containers"},
 	{"stats", "Display a live stream of container(s) resource usage statistics"},
 	{"stop", "Stop a running container"},
+	{"system", "Manage Docker"},
 	{"tag", "Tag an image into a repository"},
 	{"top", "Display the running processes of a container"},
 	{"unpause", "Unpause all processes within a container"},
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/docker/daemon/logger/loggerutils/cache"
 	"github.com/docker/docker/daemon/network"
 	derr "github.com/docker/docker/errors"
 	"github.com/docker/docker/image"
@@ -61,7 +63,10 @@ type CommonContainer struct {
 	HostConfig             *containertypes.HostConfig `json:"-"` // do not serialize the host config in the json, otherwise we'll make the container unportable
 	Command                *execdriver.Command        `json:"-"`
 	monitor                *containerMonitor
-	ExecCommands           *exec.Store `json:"-"`
+	ExecCommands           *exec.Store   `json:"-"`
+	ExecHistory            *exec.History `json:"-"`
+	// oomEvents counts the out of memory events of the container.
+	oomEvents uint32
 	// logDriver for closing
 	LogDriver logger.Logger  `json:"-"`
 	LogCopier *logger.Copier `json:"-"`
@@ -75,6 +80,7 @@ func NewBaseContainer(id, root string) *Container {
 			ID:           id,
 			State:        NewState(),
 			ExecCommands: exec.NewStore(),
+			ExecHistory:  exec.NewHistory(exec.DefaultHistorySize),
 			Root:         root,
 			MountPoints:  make(map[string]*volume.MountPoint),
 			StreamConfig: runconfig.NewStreamConfig(),
@@ -280,6 +286,12 @@ func (container *Container) ConfigPath() (string, error) {
 	return container.GetRootResourcePath(configFileName)
 }
 
+// CheckpointDir returns the directory the checkpoints of the container are
+// stored in.
+func (container *Container) CheckpointDir() (string, error) {
+	return container.GetRootResourcePath("checkpoints")
+}
+
 func validateID(id string) error {
 	if id == "" {
 		return derr.ErrorCodeEmptyID
@@ -306,7 +318,9 @@ func (container *Container) GetLogConfig(defaultConfig containertypes.LogConfig)
 	return defaultConfig
 }
 
-// StartLogger starts a new logger driver for the container.
+// StartLogger starts a new logger driver for the container. The log messages
+// of a driver which cannot read them back are also written to a local cache,
+// when it is enabled.
 func (container *Container) StartLogger(cfg containertypes.LogConfig) (logger.Logger, error) {
 	c, err := logger.GetLogDriver(cfg.Type)
 	if err != nil {
@@ -332,7 +346,25 @@ func (container *Container) StartLogger(cfg containertypes.LogConfig) (logger.Lo
 			return nil, err
 		}
 	}
-	return c(ctx)
+	l, err := c(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := l.(logger.LogReader); ok || !cache.ShouldUseCache(cfg.Config) {
+		return l, nil
+	}
+	ctx.LogPath, err = container.GetRootResourcePath(fmt.Sprintf("%s-cache.log", container.ID))
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	cl, err := cache.WithLocalCache(l, ctx)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	return cl, nil
 }
 
 // GetProcessLabel returns the process label for the container.
@@ -359,6 +391,12 @@ func (container *Container) GetExecIDs() []string {
 	return container.ExecCommands.List()
 }
 
+// OOMEvents returns the number of out of memory events of the container
+// since the daemon started.
+func (container *Container) OOMEvents() uint32 {
+	return atomic.LoadUint32(&container.oomEvents)
+}
+
 // Attach connects to the container's TTY, delegating to standard
 // streams or websockets depending on the configuration.
 func (container *Container) Attach(stdin io.ReadCloser, stdout io.Writer, stderr io.Writer, keys []byte) chan error {
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -32,6 +33,8 @@ type supervisor interface {
 	StartLogging(*Container) error
 	// Run starts a container
 	Run(c *Container, pipes *execdriver.Pipes, startCallback execdriver.DriverCallback) (execdriver.ExitStatus, error)
+	// Restore re-attaches to a container which kept running while the daemon was down
+	Restore(c *Container, pipes *execdriver.Pipes, startCallback execdriver.DriverCallback) (execdriver.ExitStatus, error)
 	// IsShuttingDown tells whether the supervisor is shutting down or not
 	IsShuttingDown() bool
 }
@@ -75,6 +78,10 @@ type containerMonitor struct {
 
 	// lastStartTime is the time which the monitor last exec'd the container's process
 	lastStartTime time.Time
+
+	// restoring is set until the monitor re-attached to the process of a
+	// container which kept running while the daemon was down
+	restoring bool
 }
 
 // StartMonitor initializes a containerMonitor for this container with the provided supervisor and restart policy
@@ -92,6 +99,23 @@ func (container *Container) StartMonitor(s supervisor, policy container.RestartP
 	return container.monitor.wait()
 }
 
+// RestoreMonitor initializes a containerMonitor for this container, which kept
+// running while the daemon was down, with the provided supervisor and restart
+// policy and re-attaches to the container's process.
+func (container *Container) RestoreMonitor(s supervisor, policy container.RestartPolicy) error {
+	container.monitor = &containerMonitor{
+		supervisor:    s,
+		container:     container,
+		restartPolicy: policy,
+		timeIncrement: defaultTimeIncrement,
+		stopChan:      make(chan struct{}),
+		startSignal:   make(chan struct{}),
+		restoring:     true,
+	}
+
+	return container.monitor.wait()
+}
+
 // wait starts the container and wait until
 // we either receive an error from the initial start of the container's
 // process or until the process is running in the container
@@ -162,8 +186,13 @@ func (m *containerMonitor) start() error {
 		m.container.HasBeenManuallyStopped = false
 	}
 
-	// reset the restart count
-	m.container.RestartCount = -1
+	// reset the restart count, a restored container keeps counting from
+	// before the restart of the daemon
+	if m.restoring {
+		m.container.RestartCount--
+	} else {
+		m.container.RestartCount = -1
+	}
 
 	for {
 		m.container.RestartCount++
@@ -176,11 +205,18 @@ func (m *containerMonitor) start() error {
 
 		pipes := execdriver.NewPipes(m.container.Stdin(), m.container.Stdout(), m.container.Stderr(), m.container.Config.OpenStdin)
 
-		m.logEvent("start")
-
-		m.lastStartTime = time.Now()
+		if m.restoring {
+			m.lastStartTime = time.Now()
+			if exitStatus, err = m.restore(pipes); err != nil {
+				return err
+			}
+		} else {
+			m.logEvent("start")
+			m.lastStartTime = time.Now()
+			exitStatus, err = m.supervisor.Run(m.container, pipes, m.callback)
+		}
 
-		if exitStatus, err = m.supervisor.Run(m.container, pipes, m.callback); err != nil {
+		if err != nil {
 			// if we receive an internal error from the initial start of a container then lets
 			// return it instead of entering the restart loop
 			// set to 127 for container cmd not found/does not exist)
@@ -240,6 +276,20 @@ func (m *containerMonitor) start() error {
 	}
 }
 
+// restore re-attaches to the process of a container which kept running while
+// the daemon was down. The container is stopped if it exited in the meantime
+// or its process cannot be re-attached to.
+func (m *containerMonitor) restore(pipes *execdriver.Pipes) (execdriver.ExitStatus, error) {
+	exitStatus, err := m.supervisor.Restore(m.container, pipes, m.callback)
+	m.restoring = false
+	if err != nil {
+		m.container.SetStopped(&exitStatus)
+		m.logEvent("die")
+		m.resetContainer(false)
+	}
+	return exitStatus, err
+}
+
 // resetMonitor resets the stateful fields on the containerMonitor based on the
 // previous runs success or failure.  Regardless of success, if the container had
 // an execution time of more than 10s then reset the timer back to the default
@@ -305,6 +355,7 @@ func (m *containerMonitor) shouldRestart(exitCode int) bool {
 func (m *containerMonitor) callback(processConfig *execdriver.ProcessConfig, pid int, chOOM <-chan struct{}) error {
 	go func() {
 		for range chOOM {
+			atomic.AddUint32(&m.container.oomEvents, 1)
 			m.logEvent("oom")
 		}
 	}()
@@ -318,7 +369,12 @@ func (m *containerMonitor) callback(processConfig *execdriver.ProcessConfig, pid
 		}
 	}
 
+	startedAt, paused := m.container.StartedAt, m.container.Paused
 	m.container.SetRunning(pid)
+	if m.restoring {
+		// the process kept running while the daemon was down
+		m.container.StartedAt, m.container.Paused = startedAt, paused
+	}
 
 	// signal that the process has started
 	// close channel only if not closed
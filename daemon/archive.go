@@ -11,6 +11,8 @@ import (
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/chrootarchive"
 	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
 	"github.com/docker/engine-api/types"
 )
 
@@ -328,3 +330,77 @@ func (daemon *Daemon) containerCopy(container *container.Container, resource str
 	daemon.LogContainerEvent(container, "copy")
 	return reader, nil
 }
+
+// VolumeArchive mounts the volume identified by the given name and returns a
+// tar archive of its content, compressed with the given compression. The
+// volume is referenced, so that it cannot be removed, and mounted until the
+// archive is closed.
+func (daemon *Daemon) VolumeArchive(name string, compression archive.Compression) (io.ReadCloser, error) {
+	v, ref, err := daemon.referenceVolume(name)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := v.Mount()
+	if err != nil {
+		daemon.volumes.Dereference(v, ref)
+		return nil, err
+	}
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	data, err := archive.TarWithOptions(path, &archive.TarOptions{
+		Compression: compression,
+		UIDMaps:     uidMaps,
+		GIDMaps:     gidMaps,
+	})
+	if err != nil {
+		v.Unmount()
+		daemon.volumes.Dereference(v, ref)
+		return nil, err
+	}
+
+	return ioutils.NewReadCloserWrapper(data, func() error {
+		err := data.Close()
+		v.Unmount()
+		daemon.volumes.Dereference(v, ref)
+		return err
+	}), nil
+}
+
+// VolumeExtract mounts the volume identified by the given name and extracts
+// the given tar archive, which may be compressed, to the root of the volume.
+// The volume is referenced during the extraction, so that it cannot be removed.
+func (daemon *Daemon) VolumeExtract(name string, content io.Reader) error {
+	v, ref, err := daemon.referenceVolume(name)
+	if err != nil {
+		return err
+	}
+	defer daemon.volumes.Dereference(v, ref)
+
+	path, err := v.Mount()
+	if err != nil {
+		return err
+	}
+	defer v.Unmount()
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	return chrootarchive.Untar(content, path, &archive.TarOptions{
+		UIDMaps: uidMaps,
+		GIDMaps: gidMaps,
+	})
+}
+
+// referenceVolume looks up the volume identified by the given name and adds a
+// unique reference to it, which must be released with Dereference.
+func (daemon *Daemon) referenceVolume(name string) (volume.Volume, string, error) {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return nil, "", err
+	}
+	ref := "archive-" + stringid.GenerateNonCryptoID()
+	v, err = daemon.volumes.GetWithRef(v.Name(), v.DriverName(), ref)
+	if err != nil {
+		return nil, "", err
+	}
+	return v, ref, nil
+}
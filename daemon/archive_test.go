@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/docker/docker/volume"
+	volumedrivers "github.com/docker/docker/volume/drivers"
+)
+
+func TestMain(m *testing.M) {
+	if reexec.Init() {
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func TestVolumeArchive(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-daemon-volume-archive-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	daemon, err := initDaemonWithVolumeStore(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer volumedrivers.Unregister(volume.DefaultDriverName)
+
+	src, err := daemon.volumes.Create("src", volume.DefaultDriverName, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(src.Path(), "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src.Path(), "dir", "file"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := daemon.volumes.Create("dst", volume.DefaultDriverName, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := daemon.VolumeArchive("unknown", archive.Uncompressed); err == nil {
+		t.Fatal("Expected an error exporting a volume which does not exist")
+	}
+
+	content, err := daemon.VolumeArchive("src", archive.Gzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := daemon.VolumeRm("src"); err == nil {
+		t.Fatal("Expected an error removing a volume which is being exported")
+	}
+	if err := daemon.VolumeExtract("dst", content); err != nil {
+		t.Fatal(err)
+	}
+	if refs := daemon.volumes.Refs(dst); len(refs) != 0 {
+		t.Fatalf("Expected the imported volume to be dereferenced, got %v", refs)
+	}
+	if err := content.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if refs := daemon.volumes.Refs(src); len(refs) != 0 {
+		t.Fatalf("Expected the exported volume to be dereferenced, got %v", refs)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dst.Path(), "dir", "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("Expected the content of the file to be imported, got %q", data)
+	}
+}
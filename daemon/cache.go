@@ -0,0 +1,189 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// ImageCache is a build cache which, in addition to the images built
+// locally, matches the build instructions against the history of a set of
+// source images. Images pulled from a registry have no parent links, so the
+// local cache cannot find them.
+type ImageCache struct {
+	daemon  *Daemon
+	sources []*image.Image
+}
+
+// NewImageCache returns a build cache using the images referenced by
+// sourceRefs as cache sources. The references which cannot be found are
+// ignored.
+func (daemon *Daemon) NewImageCache(sourceRefs []string) *ImageCache {
+	cache := &ImageCache{daemon: daemon}
+	for _, ref := range sourceRefs {
+		img, err := daemon.GetImage(ref)
+		if err != nil {
+			logrus.Warnf("Could not look up %s for cache resolution, skipping: %v", ref, err)
+			continue
+		}
+		cache.sources = append(cache.sources, img)
+	}
+	return cache
+}
+
+// GetCachedImage returns the ID of an image whose parent is parentID and
+// which was built with the configuration cfg. An image built locally is only
+// used if it descends from one of the sources. Otherwise, the step of a source
+// image matching cfg is restored as a new image, on top of parentID. A cache
+// miss returns an empty ID and a nil error.
+func (ic *ImageCache) GetCachedImage(parentID string, cfg *containertypes.Config) (string, error) {
+	local, err := ic.daemon.ImageGetCached(image.ID(parentID), cfg)
+	if err != nil {
+		return "", err
+	}
+	if local != nil {
+		for _, source := range ic.sources {
+			if source.ID() == local.ID() || ic.isParent(source.ID(), local.ID()) {
+				return local.ID().String(), nil
+			}
+		}
+	}
+
+	var parent *image.Image
+	if parentID != "" {
+		parent, err = ic.daemon.imageStore.Get(image.ID(parentID))
+		if err != nil {
+			return "", fmt.Errorf("unable to find image %q", parentID)
+		}
+	}
+	step := 0
+	if parent != nil {
+		step = len(parent.History)
+	}
+
+	for _, source := range ic.sources {
+		if !isValidCacheParent(source, parent) || !isValidCacheStep(cfg, source.History[step]) {
+			continue
+		}
+
+		if step == len(source.History)-1 {
+			// The last step is the source image itself
+			if parent != nil {
+				if err := ic.daemon.imageStore.SetParent(source.ID(), parent.ID()); err != nil {
+					return "", err
+				}
+			}
+			return source.ID().String(), nil
+		}
+
+		id, err := ic.restoreCachedImage(parent, source, cfg)
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+	return "", nil
+}
+
+// isParent returns whether parentID is an ancestor of the image id.
+func (ic *ImageCache) isParent(id, parentID image.ID) bool {
+	next, err := ic.daemon.imageStore.GetParent(id)
+	if err != nil || next == "" {
+		return false
+	}
+	if next == parentID {
+		return true
+	}
+	return ic.isParent(next, parentID)
+}
+
+// restoreCachedImage creates the image of a step of source, on top of parent.
+// The layer of the step, if any, is already in the layer store along with the
+// ones of source.
+func (ic *ImageCache) restoreCachedImage(parent, source *image.Image, cfg *containertypes.Config) (image.ID, error) {
+	var history []image.History
+	rootFS := image.NewRootFS()
+	if parent != nil {
+		history = append(history, parent.History...)
+		*rootFS = *parent.RootFS
+		rootFS.DiffIDs = append([]layer.DiffID(nil), parent.RootFS.DiffIDs...)
+	}
+	step := len(history)
+	h := source.History[step]
+	history = append(history, h)
+	if !h.EmptyLayer {
+		if len(rootFS.DiffIDs) >= len(source.RootFS.DiffIDs) {
+			return "", fmt.Errorf("invalid history in image %s: missing layer for step %d", source.ID(), step)
+		}
+		rootFS.Append(source.RootFS.DiffIDs[len(rootFS.DiffIDs)])
+	}
+
+	config, err := json.Marshal(&image.Image{
+		V1Image: image.V1Image{
+			DockerVersion:   dockerversion.Version,
+			Config:          cfg,
+			Architecture:    source.Architecture,
+			OS:              source.OS,
+			ContainerConfig: *cfg,
+			Author:          h.Author,
+			Created:         h.Created,
+		},
+		RootFS:  rootFS,
+		History: history,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	id, err := ic.daemon.imageStore.Create(config)
+	if err != nil {
+		return "", err
+	}
+	if parent != nil {
+		if err := ic.daemon.imageStore.SetParent(id, parent.ID()); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+// isValidCacheParent returns whether the history and layers of parent are the
+// first ones of source, with at least one more step in source.
+func isValidCacheParent(source, parent *image.Image) bool {
+	if len(source.History) == 0 {
+		return false
+	}
+	if parent == nil {
+		return true
+	}
+	if len(parent.History) >= len(source.History) {
+		return false
+	}
+	if len(parent.RootFS.DiffIDs) > len(source.RootFS.DiffIDs) {
+		return false
+	}
+	for i, h := range parent.History {
+		if !reflect.DeepEqual(h, source.History[i]) {
+			return false
+		}
+	}
+	for i, diffID := range parent.RootFS.DiffIDs {
+		if diffID != source.RootFS.DiffIDs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidCacheStep returns whether the step h of a source image was created
+// by the command of cfg.
+func isValidCacheStep(cfg *containertypes.Config, h image.History) bool {
+	return strings.Join(cfg.Cmd.Slice(), " ") == h.CreatedBy
+}
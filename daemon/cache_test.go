@@ -0,0 +1,149 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	containertypes "github.com/docker/engine-api/types/container"
+	"github.com/docker/engine-api/types/strslice"
+)
+
+type mockLayerGetReleaser struct{}
+
+func (ls *mockLayerGetReleaser) Get(layer.ChainID) (layer.Layer, error) {
+	return nil, nil
+}
+
+func (ls *mockLayerGetReleaser) Release(layer.Layer) ([]layer.Metadata, error) {
+	return nil, nil
+}
+
+func createTestImage(t *testing.T, is image.Store, history []image.History, diffIDs ...layer.DiffID) *image.Image {
+	rootFS := image.NewRootFS()
+	for _, diffID := range diffIDs {
+		rootFS.Append(diffID)
+	}
+	config, err := json.Marshal(&image.Image{
+		V1Image: image.V1Image{Created: history[len(history)-1].Created},
+		RootFS:  rootFS,
+		History: history,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := is.Create(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := is.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestImageCacheFrom(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "image-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	fs, err := image.NewFSStoreBackend(tmpdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := image.NewImageStore(fs, &mockLayerGetReleaser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	daemon := &Daemon{imageStore: is}
+
+	created := time.Unix(1458000000, 0).UTC()
+	baseLayer := layer.DiffID("sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae")
+	runLayer := layer.DiffID("sha256:fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9")
+	history := []image.History{
+		{Created: created, CreatedBy: "/bin/sh -c #(nop) ADD file:abc in /"},
+		{Created: created, CreatedBy: "/bin/sh -c #(nop) ENV foo=bar", EmptyLayer: true},
+		{Created: created, CreatedBy: "/bin/sh -c make"},
+	}
+	// Images pulled from a registry, without parent links
+	base := createTestImage(t, is, history[:1], baseLayer)
+	source := createTestImage(t, is, history, baseLayer, runLayer)
+
+	cache := &ImageCache{daemon: daemon, sources: []*image.Image{source}}
+
+	cfg := &containertypes.Config{Cmd: strslice.New("/bin/sh", "-c", "apt-get update")}
+	id, err := cache.GetCachedImage(base.ID().String(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "" {
+		t.Fatalf("Expected a cache miss for another command, got %s", id)
+	}
+
+	cfg = &containertypes.Config{Cmd: strslice.New("/bin/sh", "-c", "#(nop) ENV foo=bar")}
+	envID, err := cache.GetCachedImage(base.ID().String(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := is.Get(image.ID(envID))
+	if err != nil {
+		t.Fatalf("Expected the ENV step to be restored: %v", err)
+	}
+	if len(env.History) != 2 || len(env.RootFS.DiffIDs) != 1 || env.Parent != base.ID() {
+		t.Fatalf("Expected the ENV step on top of the base image, got %+v", env)
+	}
+
+	cfg = &containertypes.Config{Cmd: strslice.New("/bin/sh", "-c", "make")}
+	id, err = cache.GetCachedImage(envID, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != source.ID().String() {
+		t.Fatalf("Expected the last step to be the source image %s, got %s", source.ID(), id)
+	}
+
+	// The restored image is now found locally, as an ancestor of the source
+	cfg = &containertypes.Config{Cmd: strslice.New("/bin/sh", "-c", "#(nop) ENV foo=bar")}
+	id, err = cache.GetCachedImage(base.ID().String(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != envID {
+		t.Fatalf("Expected the restored image %s to be used, got %s", envID, id)
+	}
+}
+
+func TestIsValidCacheParent(t *testing.T) {
+	created := time.Unix(1458000000, 0).UTC()
+	source := &image.Image{
+		RootFS: &image.RootFS{DiffIDs: []layer.DiffID{"sha256:a", "sha256:b"}},
+		History: []image.History{
+			{Created: created, CreatedBy: "a"},
+			{Created: created, CreatedBy: "b"},
+		},
+	}
+
+	if !isValidCacheParent(source, nil) {
+		t.Fatal("Expected no parent to be valid")
+	}
+	parent := &image.Image{
+		RootFS:  &image.RootFS{DiffIDs: []layer.DiffID{"sha256:a"}},
+		History: []image.History{{Created: created, CreatedBy: "a"}},
+	}
+	if !isValidCacheParent(source, parent) {
+		t.Fatal("Expected the first step of the source to be a valid parent")
+	}
+	parent.RootFS.DiffIDs[0] = "sha256:c"
+	if isValidCacheParent(source, parent) {
+		t.Fatal("Expected a parent with another layer to be invalid")
+	}
+	if isValidCacheParent(source, source) {
+		t.Fatal("Expected the source itself not to be a valid parent")
+	}
+}
@@ -0,0 +1,148 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/execdriver"
+	derr "github.com/docker/docker/errors"
+	"github.com/docker/docker/utils"
+	"github.com/docker/engine-api/types"
+)
+
+var validCheckpointNamePattern = regexp.MustCompile(`^` + utils.RestrictedNameChars + `+$`)
+
+// CheckpointCreate saves the state of the processes of a running container
+// to disk. The container is stopped after the checkpoint unless
+// config.Exit is false.
+func (daemon *Daemon) CheckpointCreate(name string, config types.CheckpointCreateOptions) error {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+	if err := verifyCheckpointSettings(container); err != nil {
+		return err
+	}
+
+	dir, err := checkpointDir(container, config.CheckpointID)
+	if err != nil {
+		return err
+	}
+
+	container.Lock()
+	defer container.Unlock()
+
+	if !container.Running {
+		return derr.ErrorCodeNotRunning.WithArgs(container.ID)
+	}
+	if container.Paused {
+		return fmt.Errorf("Container %s is paused. Unpause the container before checkpoint", container.ID)
+	}
+	// The check is done with the container locked so that concurrent
+	// checkpoints with the same name cannot both pass it.
+	if _, err := os.Stat(dir); err == nil {
+		return derr.ErrorCodeCheckpointExists.WithArgs(config.CheckpointID)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	opts := &execdriver.CheckpointOpts{
+		Directory:    dir,
+		LeaveRunning: !config.Exit,
+	}
+	if err := daemon.execDriver.Checkpoint(container.Command, opts); err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("Cannot checkpoint container %s: %v", container.ID, err)
+	}
+	if config.Exit {
+		// The processes of the container are killed once dumped, it must
+		// not be restarted by its restart policy. A failed checkpoint
+		// leaves them running, along with the restart policy.
+		container.ExitOnNext()
+	}
+
+	daemon.LogContainerEventWithAttributes(container, "checkpoint", map[string]string{
+		"checkpoint": config.CheckpointID,
+	})
+	return nil
+}
+
+// CheckpointList returns the checkpoints of a container.
+func (daemon *Daemon) CheckpointList(name string) ([]types.Checkpoint, error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := container.CheckpointDir()
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []types.Checkpoint{}, nil
+		}
+		return nil, err
+	}
+
+	checkpoints := []types.Checkpoint{}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		checkpoints = append(checkpoints, types.Checkpoint{Name: d.Name()})
+	}
+	return checkpoints, nil
+}
+
+// CheckpointDelete removes a checkpoint of a container.
+func (daemon *Daemon) CheckpointDelete(name, checkpointID string) error {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+
+	// A start restores the processes of the container from a checkpoint
+	// with the container locked, and so does a checkpoint create, the
+	// checkpoint is not removed underneath either of them.
+	container.Lock()
+	defer container.Unlock()
+
+	dir, err := existingCheckpointDir(container, checkpointID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// checkpointDir returns the directory of the checkpoint checkpointID of the
+// container.
+func checkpointDir(container *container.Container, checkpointID string) (string, error) {
+	if !validCheckpointNamePattern.MatchString(checkpointID) {
+		return "", derr.ErrorCodeInvalidCheckpointName.WithArgs(checkpointID, utils.RestrictedNameChars)
+	}
+	root, err := container.CheckpointDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, checkpointID), nil
+}
+
+// existingCheckpointDir returns the directory of the checkpoint checkpointID
+// of the container, which must exist.
+func existingCheckpointDir(container *container.Container, checkpointID string) (string, error) {
+	dir, err := checkpointDir(container, checkpointID)
+	if err != nil {
+		return "", err
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return "", derr.ErrorCodeNoSuchCheckpoint.WithArgs(checkpointID)
+	}
+	return dir, nil
+}
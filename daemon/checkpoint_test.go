@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/registrar"
+	"github.com/docker/docker/pkg/truncindex"
+)
+
+func TestCheckpointListDelete(t *testing.T) {
+	root, err := ioutil.TempDir("", "docker-checkpoint-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:    "5a4ff6a163ad4533d22d69a2b8960bf7fafdcba06e72d2febdba229008b0bf57",
+			Name:  "tender_bardeen",
+			Root:  root,
+			State: container.NewState(),
+		},
+	}
+	store := container.NewMemoryStore()
+	store.Add(c.ID, c)
+	daemon := &Daemon{
+		containers: store,
+		idIndex:    truncindex.NewTruncIndex([]string{c.ID}),
+		nameIndex:  registrar.NewRegistrar(),
+	}
+
+	checkpoints, err := daemon.CheckpointList(c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 0 {
+		t.Fatalf("Expected no checkpoints, got %v", checkpoints)
+	}
+
+	for _, name := range []string{"warm", "warm.2"} {
+		if err := os.MkdirAll(filepath.Join(root, "checkpoints", name), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkpoints, err = daemon.CheckpointList(c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 2 || checkpoints[0].Name != "warm" || checkpoints[1].Name != "warm.2" {
+		t.Fatalf("Expected the warm and warm.2 checkpoints, got %v", checkpoints)
+	}
+
+	for _, name := range []string{"", "..", "../..", "-warm", "warm/../.."} {
+		if err := daemon.CheckpointDelete(c.ID, name); err == nil {
+			t.Fatalf("Expected an error deleting the checkpoint %q", name)
+		}
+	}
+	if err := daemon.CheckpointDelete(c.ID, "cold"); err == nil {
+		t.Fatal("Expected an error deleting a checkpoint which does not exist")
+	}
+	if err := daemon.CheckpointDelete(c.ID, "warm"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "checkpoints", "warm")); !os.IsNotExist(err) {
+		t.Fatalf("Expected the checkpoint to be removed, got %v", err)
+	}
+	if _, err := existingCheckpointDir(c, "warm.2"); err != nil {
+		t.Fatal(err)
+	}
+}
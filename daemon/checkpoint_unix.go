@@ -0,0 +1,22 @@
+// +build !windows
+
+package daemon
+
+import (
+	"github.com/docker/docker/container"
+	derr "github.com/docker/docker/errors"
+)
+
+// verifyCheckpointSettings checks that the container can be restored from a
+// checkpoint. The network namespace of a container is set up by the daemon
+// before it starts, with the veth pair connecting it to the bridge for
+// instance, while CRIU restores the namespace from the checkpoint without
+// that connection. So only the containers sharing the network namespace of
+// the host, or without a network, can be restored.
+func verifyCheckpointSettings(container *container.Container) error {
+	networkMode := container.HostConfig.NetworkMode
+	if !networkMode.IsHost() && !networkMode.IsNone() {
+		return derr.ErrorCodeCheckpointNetworkMode.WithArgs(container.ID)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package daemon
+
+import (
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/execdriver"
+)
+
+// verifyCheckpointSettings checks that the container can be restored from a
+// checkpoint, which is not supported on Windows.
+func verifyCheckpointSettings(container *container.Container) error {
+	return execdriver.ErrCheckpointNotSupported
+}
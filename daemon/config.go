@@ -74,6 +74,26 @@ type CommonConfig struct {
 	// reachable by other hosts.
 	ClusterAdvertise string `json:"cluster-advertise,omitempty"`
 
+	// EventsMaxSize is the maximum size of the on-disk events journal,
+	// in a human readable form such as "64m".
+	EventsMaxSize string `json:"events-max-size,omitempty"`
+
+	// EventsMaxAge is the maximum age of the events kept in the on-disk
+	// events journal, as a duration such as "168h". No limit if empty.
+	EventsMaxAge string `json:"events-max-age,omitempty"`
+
+	// ExecHistoryTTL is how long the finished execs of a container can be
+	// inspected, as a duration such as "1h". The history is disabled if 0.
+	ExecHistoryTTL string `json:"exec-history-ttl,omitempty"`
+
+	// MetricsAddress is the TCP address the metrics of the daemon are
+	// served on. The metrics are not served if empty.
+	MetricsAddress string `json:"metrics-addr,omitempty"`
+
+	// LiveRestore keeps the containers running when the daemon exits and
+	// re-attaches to them when it starts again.
+	LiveRestore bool `json:"live-restore,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -114,6 +134,10 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.ClusterAdvertise, []string{"-cluster-advertise"}, "", usageFn("Address or interface name to advertise"))
 	cmd.StringVar(&config.ClusterStore, []string{"-cluster-store"}, "", usageFn("Set the cluster store"))
 	cmd.Var(opts.NewNamedMapOpts("cluster-store-opts", config.ClusterOpts, nil), []string{"-cluster-store-opt"}, usageFn("Set cluster store options"))
+	cmd.StringVar(&config.EventsMaxSize, []string{"-events-max-size"}, "64m", usageFn("Maximum size of the events journal"))
+	cmd.StringVar(&config.EventsMaxAge, []string{"-events-max-age"}, "", usageFn("Maximum age of the events in the events journal"))
+	cmd.StringVar(&config.ExecHistoryTTL, []string{"-exec-history-ttl"}, "1h", usageFn("How long finished execs can be inspected"))
+	cmd.StringVar(&config.MetricsAddress, []string{"-metrics-addr"}, "", usageFn("Set the address to serve the metrics on"))
 }
 
 // IsValueSet returns true if a configuration value
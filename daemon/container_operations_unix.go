@@ -271,6 +271,7 @@ func (daemon *Daemon) populateCommand(c *container.Container, env []string) erro
 		GIDMapping:         gidMap,
 		GroupAdd:           c.HostConfig.GroupAdd,
 		Ipc:                ipc,
+		LiveRestore:        daemon.configStore.LiveRestore,
 		OomScoreAdj:        c.HostConfig.OomScoreAdj,
 		Pid:                pid,
 		ReadonlyRootfs:     c.HostConfig.ReadonlyRootfs,
@@ -401,8 +402,9 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container, n libn
 	}
 
 	// Link feature is supported only for the default bridge network.
-	// return if this call to build join options is not for default bridge network
-	if n.Name() != "bridge" {
+	// return if this call to build join options is not for default bridge network,
+	// or for no network at all when restoring a sandbox
+	if n == nil || n.Name() != "bridge" {
 		return sboxOptions, nil
 	}
 
@@ -1,6 +1,8 @@
 package daemon
 
 import (
+	"time"
+
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
 	derr "github.com/docker/docker/errors"
@@ -17,6 +19,8 @@ import (
 
 // ContainerCreate creates a container.
 func (daemon *Daemon) ContainerCreate(params types.ContainerCreateConfig) (types.ContainerCreateResponse, error) {
+	defer containerActions.ObserveSince(time.Now(), "create")
+
 	if params.Config == nil {
 		return types.ContainerCreateResponse{}, derr.ErrorCodeEmptyConfig
 	}
@@ -164,14 +168,14 @@ func (daemon *Daemon) setRWLayer(container *container.Container) error {
 	return nil
 }
 
-// VolumeCreate creates a volume with the specified name, driver, and opts
-// This is called directly from the remote API
-func (daemon *Daemon) VolumeCreate(name, driverName string, opts map[string]string) (*types.Volume, error) {
+// VolumeCreate creates a volume with the specified name, driver, opts and
+// labels. This is called directly from the remote API
+func (daemon *Daemon) VolumeCreate(name, driverName string, opts, labels map[string]string) (*types.Volume, error) {
 	if name == "" {
 		name = stringid.GenerateNonCryptoID()
 	}
 
-	v, err := daemon.volumes.Create(name, driverName, opts)
+	v, err := daemon.volumes.Create(name, driverName, opts, labels)
 	if err != nil {
 		if volumestore.IsNameConflict(err) {
 			return nil, derr.ErrorVolumeNameTaken.WithArgs(name)
@@ -61,6 +61,7 @@ import (
 	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/pkg/truncindex"
+	"github.com/docker/docker/plugin"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
@@ -69,6 +70,7 @@ import (
 	"github.com/docker/docker/volume/local"
 	"github.com/docker/docker/volume/store"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	"github.com/docker/libnetwork"
 	lntypes "github.com/docker/libnetwork/types"
 	"github.com/docker/libtrust"
@@ -107,6 +109,7 @@ type Daemon struct {
 	repository                string
 	containers                container.Store
 	execCommands              *exec.Store
+	execHistoryTTL            time.Duration
 	referenceStore            reference.Store
 	downloadManager           *xfer.LayerDownloadManager
 	uploadManager             *xfer.LayerUploadManager
@@ -119,7 +122,10 @@ type Daemon struct {
 	defaultLogConfig          containertypes.LogConfig
 	RegistryService           *registry.Service
 	EventsService             *events.Events
+	eventsJournal             *events.Journal
+	pluginManager             *plugin.Manager
 	netController             libnetwork.NetworkController
+	networkMetadata           *networkMetadataStore
 	volumes                   *store.VolumeStore
 	discoveryWatcher          discoveryReloader
 	root                      string
@@ -229,30 +235,34 @@ func (daemon *Daemon) Register(container *container.Container) error {
 	daemon.containers.Add(container.ID, container)
 	daemon.idIndex.Add(container.ID)
 
-	if container.IsRunning() {
-		logrus.Debugf("killing old running container %s", container.ID)
-		// Set exit code to 128 + SIGKILL (9) to properly represent unsuccessful exit
-		container.SetStoppedLocking(&execdriver.ExitStatus{ExitCode: 137})
-		// use the current driver and ensure that the container is dead x.x
-		cmd := &execdriver.Command{
-			CommonCommand: execdriver.CommonCommand{
-				ID: container.ID,
-			},
-		}
-		daemon.execDriver.Terminate(cmd)
-
-		container.UnmountIpcMounts(mount.Unmount)
+	return nil
+}
 
-		daemon.Unmount(container)
-		if err := container.ToDiskLocking(); err != nil {
-			logrus.Errorf("Error saving stopped state to disk: %v", err)
-		}
+// terminateOldContainer kills the process of a container left running by a
+// previous instance of the daemon and marks the container as stopped.
+func (daemon *Daemon) terminateOldContainer(container *container.Container) {
+	logrus.Debugf("killing old running container %s", container.ID)
+	// Set exit code to 128 + SIGKILL (9) to properly represent unsuccessful exit
+	container.SetStoppedLocking(&execdriver.ExitStatus{ExitCode: 137})
+	// use the current driver and ensure that the container is dead x.x
+	cmd := &execdriver.Command{
+		CommonCommand: execdriver.CommonCommand{
+			ID: container.ID,
+		},
 	}
+	daemon.execDriver.Terminate(cmd)
 
-	return nil
+	container.UnmountIpcMounts(mount.Unmount)
+
+	daemon.Unmount(container)
+	if err := container.ToDiskLocking(); err != nil {
+		logrus.Errorf("Error saving stopped state to disk: %v", err)
+	}
 }
 
-func (daemon *Daemon) restore() error {
+// loadContainers loads the containers of the repository which were created
+// with the current graph driver, by container ID.
+func (daemon *Daemon) loadContainers() (map[string]*container.Container, error) {
 	var (
 		debug         = utils.IsDebugEnabled()
 		currentDriver = daemon.GraphDriverName()
@@ -264,7 +274,7 @@ func (daemon *Daemon) restore() error {
 	}
 	dir, err := ioutil.ReadDir(daemon.repository)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, v := range dir {
@@ -293,21 +303,52 @@ func (daemon *Daemon) restore() error {
 			logrus.Debugf("Cannot load container %s because it was created with another graph driver.", container.ID)
 		}
 	}
+	return containers, nil
+}
+
+// liveRestoreContainers returns the containers which were running when the
+// daemon exited, and are re-attached to with live restore. Their mounts and
+// network sandboxes are still in use.
+func (daemon *Daemon) liveRestoreContainers(containers map[string]*container.Container) map[*container.Container]bool {
+	restoreContainers := make(map[*container.Container]bool)
+	if daemon.configStore.LiveRestore {
+		for _, c := range containers {
+			if c.IsRunning() {
+				restoreContainers[c] = true
+			}
+		}
+	}
+	return restoreContainers
+}
+
+// restore registers the loaded containers, re-attaches to the ones in
+// restoreContainers and restarts the ones which should be.
+func (daemon *Daemon) restore(containers map[string]*container.Container, restoreContainers map[*container.Container]bool) error {
+	var (
+		debug = utils.IsDebugEnabled()
+		err   error
+	)
 
 	var migrateLegacyLinks bool
 	restartContainers := make(map[*container.Container]chan struct{})
 	for _, c := range containers {
 		if err := daemon.registerName(c); err != nil {
 			logrus.Errorf("Failed to register container %s: %s", c.ID, err)
+			delete(restoreContainers, c)
 			continue
 		}
 		if err := daemon.Register(c); err != nil {
 			logrus.Errorf("Failed to register container %s: %s", c.ID, err)
+			delete(restoreContainers, c)
 			continue
 		}
 
+		if c.IsRunning() && !restoreContainers[c] {
+			daemon.terminateOldContainer(c)
+		}
+
 		// get list of containers we need to restart
-		if daemon.configStore.AutoRestart && c.ShouldRestart() {
+		if !restoreContainers[c] && daemon.configStore.AutoRestart && c.ShouldRestart() {
 			restartContainers[c] = make(chan struct{})
 		}
 
@@ -341,6 +382,27 @@ func (daemon *Daemon) restore() error {
 	}
 
 	group := sync.WaitGroup{}
+	for c := range restoreContainers {
+		group.Add(1)
+
+		go func(c *container.Container) {
+			defer group.Done()
+
+			logrus.Debugf("Restoring container %s", c.ID)
+			if err := daemon.containerRestore(c); err != nil {
+				logrus.Errorf("Failed to restore container %s: %s", c.ID, err)
+			}
+		}(c)
+	}
+	group.Wait()
+
+	// restart the restored containers which exited while the daemon was down
+	for c := range restoreContainers {
+		if !c.IsRunning() && daemon.configStore.AutoRestart && c.ShouldRestart() {
+			restartContainers[c] = make(chan struct{})
+		}
+	}
+
 	for c, notifier := range restartContainers {
 		group.Add(1)
 
@@ -361,7 +423,7 @@ func (daemon *Daemon) restore() error {
 					}
 				}
 			}
-			if err := daemon.containerStart(c); err != nil {
+			if err := daemon.containerStart(c, ""); err != nil {
 				logrus.Errorf("Failed to start container %s: %s", c.ID, err)
 			}
 			close(chNotify)
@@ -547,10 +609,31 @@ func (daemon *Daemon) GetByName(name string) (*container.Container, error) {
 	return e, nil
 }
 
-// SubscribeToEvents returns the currently record of events, a channel to stream new events from, and a function to cancel the stream of events.
-func (daemon *Daemon) SubscribeToEvents(since, sinceNano int64, filter filters.Args) ([]eventtypes.Message, chan interface{}) {
+// SubscribeToEvents returns the recorded events between since and until, a channel to stream new events from, and a function to cancel the stream of events.
+func (daemon *Daemon) SubscribeToEvents(since, until time.Time, filter filters.Args) ([]eventtypes.Message, chan interface{}) {
 	ef := events.NewFilter(filter)
-	return daemon.EventsService.SubscribeTopic(since, sinceNano, ef)
+	return daemon.EventsService.SubscribeTopic(since, until, ef)
+}
+
+// eventsJournalOptions returns the retention settings of the events journal
+// from the daemon configuration.
+func eventsJournalOptions(config *Config) (events.JournalOptions, error) {
+	var opts events.JournalOptions
+	if config.EventsMaxSize != "" {
+		size, err := units.RAMInBytes(config.EventsMaxSize)
+		if err != nil {
+			return opts, fmt.Errorf("invalid events-max-size %q: %v", config.EventsMaxSize, err)
+		}
+		opts.MaxSize = size
+	}
+	if config.EventsMaxAge != "" {
+		age, err := time.ParseDuration(config.EventsMaxAge)
+		if err != nil {
+			return opts, fmt.Errorf("invalid events-max-age %q: %v", config.EventsMaxAge, err)
+		}
+		opts.MaxAge = age
+	}
+	return opts, nil
 }
 
 // UnsubscribeFromEvents stops the event subscription for a client by closing the
@@ -670,6 +753,14 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 		}
 	}()
 
+	// The plugins are started before the logging and storage drivers, which
+	// may be provided by one of them.
+	d.pluginManager, err = plugin.NewManager(filepath.Join(config.Root, "plugins"), filepath.Join(config.ExecRoot, "plugins"), registryService)
+	if err != nil {
+		return nil, err
+	}
+	d.pluginManager.Init()
+
 	// Verify logging driver type
 	if config.LogConfig.Type != "none" {
 		if _, err := logger.GetLogDriver(config.LogConfig.Type); err != nil {
@@ -721,7 +812,12 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	}
 
 	// Configure the volumes driver
-	volStore, err := configureVolumes(config, rootUID, rootGID)
+	volStore, localVolumes, err := configureVolumes(config, rootUID, rootGID)
+	if err != nil {
+		return nil, err
+	}
+
+	networkMetadata, err := newNetworkMetadataStore(filepath.Join(config.Root, "network", "metadata.json"))
 	if err != nil {
 		return nil, err
 	}
@@ -742,7 +838,20 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 		return nil, err
 	}
 
-	eventsService := events.New()
+	journalOpts, err := eventsJournalOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	eventsJournal, err := events.NewJournal(filepath.Join(config.Root, "events"), journalOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't open events journal: %v", err)
+	}
+	eventsService := events.NewWithJournal(eventsJournal)
+
+	execHistoryTTL, err := parseExecHistoryTTL(config)
+	if err != nil {
+		return nil, err
+	}
 
 	referenceStore, err := reference.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
 	if err != nil {
@@ -765,11 +874,6 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 		return nil, err
 	}
 
-	d.netController, err = d.initNetworkController(config)
-	if err != nil {
-		return nil, fmt.Errorf("Error initializing network controller: %v", err)
-	}
-
 	sysInfo := sysinfo.New(false)
 	// Check if Devices cgroup is mounted, it is hard requirement for container security,
 	// on Linux/FreeBSD.
@@ -786,6 +890,7 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	d.repository = daemonRepo
 	d.containers = container.NewMemoryStore()
 	d.execCommands = exec.NewStore()
+	d.execHistoryTTL = execHistoryTTL
 	d.referenceStore = referenceStore
 	d.distributionMetadataStore = distributionMetadataStore
 	d.trustKey = trustKey
@@ -799,7 +904,9 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	}
 	d.RegistryService = registryService
 	d.EventsService = eventsService
+	d.eventsJournal = eventsJournal
 	d.volumes = volStore
+	d.networkMetadata = networkMetadata
 	d.root = config.Root
 	d.uidMaps = uidMaps
 	d.gidMaps = gidMaps
@@ -808,14 +915,38 @@ func NewDaemon(config *Config, registryService *registry.Service) (daemon *Daemo
 	d.nameIndex = registrar.NewRegistrar()
 	d.linkIndex = newLinkIndex()
 
-	if err := d.cleanupMounts(); err != nil {
+	containers, err := d.loadContainers()
+	if err != nil {
 		return nil, err
 	}
+	restoreContainers := d.liveRestoreContainers(containers)
+
+	// The mounts of the restored containers are still in use
+	if err := d.cleanupMounts(restoreContainers); err != nil {
+		return nil, err
+	}
+	if !config.LiveRestore {
+		localVolumes.UnmountUnused()
+	}
+
+	// The network sandboxes of the restored containers must be known when
+	// the network controller starts so that it does not clean them up.
+	d.netController, err = d.initNetworkController(config, d.activeSandboxes(restoreContainers))
+	if err != nil {
+		return nil, fmt.Errorf("Error initializing network controller: %v", err)
+	}
+
 	go d.execCommandGC()
 
-	if err := d.restore(); err != nil {
+	if err := d.restore(containers, restoreContainers); err != nil {
 		return nil, err
 	}
+	// The restored containers took their uses of the local volumes again,
+	// the volumes left mounted by the containers which did not survive the
+	// restart can be unmounted
+	if config.LiveRestore {
+		localVolumes.UnmountUnused()
+	}
 
 	return d, nil
 }
@@ -862,6 +993,40 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 // Shutdown stops the daemon.
 func (daemon *Daemon) Shutdown() error {
 	daemon.shutdown = true
+
+	if daemon.eventsJournal != nil {
+		defer func() {
+			if err := daemon.eventsJournal.Close(); err != nil {
+				logrus.Errorf("Error closing events journal: %v", err)
+			}
+		}()
+	}
+
+	// The volume store is closed even if the containers are left running,
+	// once they are stopped otherwise.
+	if daemon.volumes != nil {
+		defer func() {
+			if err := daemon.volumes.Shutdown(); err != nil {
+				logrus.Errorf("Error during volume store shutdown: %v", err)
+			}
+		}()
+	}
+
+	if daemon.configStore != nil && daemon.configStore.LiveRestore && daemon.containers != nil {
+		// The containers keep running, along with their networks and
+		// mounts, until the daemon starts again and restores them.
+		running := false
+		daemon.containers.ApplyAll(func(c *container.Container) {
+			if c.IsRunning() {
+				running = true
+			}
+		})
+		if running {
+			logrus.Info("Live restore is enabled, leaving the running containers untouched")
+			return nil
+		}
+	}
+
 	if daemon.containers != nil {
 		logrus.Debug("starting clean shutdown of all containers...")
 		daemon.containers.ApplyAll(func(c *container.Container) {
@@ -888,7 +1053,12 @@ func (daemon *Daemon) Shutdown() error {
 		}
 	}
 
-	if err := daemon.cleanupMounts(); err != nil {
+	// The plugins are stopped once the containers and layers using them are.
+	if daemon.pluginManager != nil {
+		daemon.pluginManager.Shutdown()
+	}
+
+	if err := daemon.cleanupMounts(nil); err != nil {
 		return err
 	}
 
@@ -927,15 +1097,43 @@ func (daemon *Daemon) Unmount(container *container.Container) {
 
 // Run uses the execution driver to run a given container
 func (daemon *Daemon) Run(c *container.Container, pipes *execdriver.Pipes, startCallback execdriver.DriverCallback) (execdriver.ExitStatus, error) {
+	start, stopHealthchecks := daemon.withHealthMonitor(c, startCallback)
 	hooks := execdriver.Hooks{
-		Start: startCallback,
+		Start: start,
 	}
 	hooks.PreStart = append(hooks.PreStart, func(processConfig *execdriver.ProcessConfig, pid int, chOOM <-chan struct{}) error {
 		return daemon.setNetworkNamespaceKey(c.ID, pid)
 	})
+	defer stopHealthchecks()
+	// A container is only restored from a checkpoint on its first run, it
+	// starts afresh when restarted by its restart policy.
+	defer func() {
+		c.Command.CheckpointDir = ""
+	}()
 	return daemon.execDriver.Run(c.Command, pipes, hooks)
 }
 
+// Restore uses the execution driver to re-attach to a container which kept
+// running while the daemon was down
+func (daemon *Daemon) Restore(c *container.Container, pipes *execdriver.Pipes, startCallback execdriver.DriverCallback) (execdriver.ExitStatus, error) {
+	start, stopHealthchecks := daemon.withHealthMonitor(c, startCallback)
+	hooks := execdriver.Hooks{
+		Start: start,
+	}
+	defer stopHealthchecks()
+	exitStatus, err := daemon.execDriver.Restore(c.Command, pipes, hooks)
+	if err != nil && err != execdriver.ErrNotRunning {
+		// The process of the container may still be running, it is killed
+		// as the container is not tracked anymore once it is stopped.
+		if err := daemon.execDriver.Terminate(c.Command); err == nil {
+			exitStatus.ExitCode = 137
+		} else {
+			logrus.Debugf("Failed to terminate container %s: %v", c.ID, err)
+		}
+	}
+	return exitStatus, err
+}
+
 func (daemon *Daemon) kill(c *container.Container, sig int) error {
 	return daemon.execDriver.Kill(c.Command, sig)
 }
@@ -1275,6 +1473,43 @@ func (daemon *Daemon) GetImage(refOrID string) (*image.Image, error) {
 	return daemon.imageStore.Get(imgID)
 }
 
+// MountImage mounts the root filesystem of the image referred to by refOrID
+// on a new read-write layer and returns its path, along with the function to
+// call to unmount and release the layer once done with it.
+func (daemon *Daemon) MountImage(refOrID string) (string, func() error, error) {
+	img, err := daemon.GetImage(refOrID)
+	if err != nil {
+		return "", nil, err
+	}
+	return daemon.mountLayerChain(img.RootFS.ChainID())
+}
+
+// mountLayerChain mounts a new read-write layer on top of the layer chainID,
+// or an empty one if chainID is empty, and returns its path along with the
+// function to call to unmount and release it.
+func (daemon *Daemon) mountLayerChain(chainID layer.ChainID) (string, func() error, error) {
+	rwLayer, err := daemon.layerStore.CreateRWLayer(stringid.GenerateRandomID(), chainID, "", nil)
+	if err != nil {
+		return "", nil, err
+	}
+	path, err := rwLayer.Mount("")
+	if err != nil {
+		if _, err := daemon.layerStore.ReleaseRWLayer(rwLayer); err != nil {
+			logrus.Errorf("Error releasing layer %s: %v", rwLayer.Name(), err)
+		}
+		return "", nil, err
+	}
+	release := func() error {
+		if err := rwLayer.Unmount(); err != nil {
+			return err
+		}
+		metadata, err := daemon.layerStore.ReleaseRWLayer(rwLayer)
+		layer.LogReleaseMetadata(metadata)
+		return err
+	}
+	return path, release, nil
+}
+
 // GraphDriverName returns the name of the graph driver used by the layer.Store
 func (daemon *Daemon) GraphDriverName() string {
 	return daemon.layerStore.DriverName()
@@ -1286,6 +1521,11 @@ func (daemon *Daemon) ExecutionDriver() execdriver.Driver {
 	return daemon.execDriver
 }
 
+// PluginManager returns the manager of the plugins installed in the daemon.
+func (daemon *Daemon) PluginManager() *plugin.Manager {
+	return daemon.pluginManager
+}
+
 // GetUIDGIDMaps returns the current daemon's user namespace settings
 // for the full uid and gid maps which will be applied to containers
 // started in this instance.
@@ -1449,14 +1689,18 @@ func (daemon *Daemon) verifyNetworkingConfig(nwConfig *networktypes.NetworkingCo
 	return derr.ErrorCodeMultipleNetworkConnect.WithArgs(fmt.Sprintf("%v", l))
 }
 
-func configureVolumes(config *Config, rootUID, rootGID int) (*store.VolumeStore, error) {
+func configureVolumes(config *Config, rootUID, rootGID int) (*store.VolumeStore, *local.Root, error) {
 	volumesDriver, err := local.New(config.Root, rootUID, rootGID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	volumedrivers.Register(volumesDriver, volumesDriver.Name())
-	return store.New(), nil
+	volStore, err := store.New(config.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return volStore, volumesDriver, nil
 }
 
 // AuthenticateToRegistry checks the validity of credentials in authConfig
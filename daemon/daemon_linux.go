@@ -9,11 +9,13 @@ import (
 	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
 	"github.com/docker/docker/pkg/mount"
 )
 
-// cleanupMounts umounts shm/mqueue mounts for old containers
-func (daemon *Daemon) cleanupMounts() error {
+// cleanupMounts umounts shm/mqueue mounts for old containers, except the ones
+// of the containers restored with live restore, which are still in use.
+func (daemon *Daemon) cleanupMounts(restored map[*container.Container]bool) error {
 	logrus.Debugf("Cleaning up old shm/mqueue mounts: start.")
 	f, err := os.Open("/proc/self/mountinfo")
 	if err != nil {
@@ -21,10 +23,17 @@ func (daemon *Daemon) cleanupMounts() error {
 	}
 	defer f.Close()
 
-	return daemon.cleanupMountsFromReader(f, mount.Unmount)
+	inUse := make(map[string]bool, len(restored))
+	for c := range restored {
+		inUse[c.ID] = true
+	}
+	return daemon.cleanupMountsFromReader(f, mount.Unmount, inUse)
 }
 
-func (daemon *Daemon) cleanupMountsFromReader(reader io.Reader, unmount func(target string) error) error {
+// cleanupMountsFromReader unmounts the shm/mqueue mounts of the containers
+// listed in reader, in the format of /proc/self/mountinfo, unless their
+// container ID is in inUse.
+func (daemon *Daemon) cleanupMountsFromReader(reader io.Reader, unmount func(target string) error, inUse map[string]bool) error {
 	if daemon.repository == "" {
 		return nil
 	}
@@ -37,7 +46,7 @@ func (daemon *Daemon) cleanupMountsFromReader(reader io.Reader, unmount func(tar
 			logrus.Debugf("Mount base: %v, repository %s", fields[4], daemon.repository)
 			mnt := fields[4]
 			mountBase := filepath.Base(mnt)
-			if mountBase == "mqueue" || mountBase == "shm" {
+			if (mountBase == "mqueue" || mountBase == "shm") && !inUse[filepath.Base(filepath.Dir(mnt))] {
 				logrus.Debugf("Unmounting %v", mnt)
 				if err := unmount(mnt); err != nil {
 					logrus.Error(err)
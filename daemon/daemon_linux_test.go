@@ -50,7 +50,7 @@ func TestCleanupMounts(t *testing.T) {
 		return nil
 	}
 
-	d.cleanupMountsFromReader(strings.NewReader(fixture), unmount)
+	d.cleanupMountsFromReader(strings.NewReader(fixture), unmount, nil)
 
 	if !unmounted {
 		t.Fatalf("Expected to unmount the mqueue")
@@ -67,8 +67,29 @@ func TestNotCleanupMounts(t *testing.T) {
 		return nil
 	}
 	mountInfo := `234 232 0:59 / /dev/shm rw,nosuid,nodev,noexec,relatime - tmpfs shm rw,size=65536k`
-	d.cleanupMountsFromReader(strings.NewReader(mountInfo), unmount)
+	d.cleanupMountsFromReader(strings.NewReader(mountInfo), unmount, nil)
 	if unmounted {
 		t.Fatalf("Expected not to clean up /dev/shm")
 	}
 }
+
+func TestNotCleanupMountsInUse(t *testing.T) {
+	d := &Daemon{
+		repository: "/var/lib/docker/containers",
+	}
+	var unmounted []string
+	unmount := func(target string) error {
+		unmounted = append(unmounted, target)
+		return nil
+	}
+	mountInfo := `133 230 0:55 / /var/lib/docker/containers/47903e2e67014246eba27607809d5f5c2437c3bf84c2986393448f84093cc40b/mqueue rw,nosuid,nodev,noexec,relatime - mqueue mqueue rw
+134 230 0:56 / /var/lib/docker/containers/47903e2e67014246eba27607809d5f5c2437c3bf84c2986393448f84093cc40b/shm rw,nosuid,nodev,noexec,relatime - tmpfs shm rw,size=65536k
+135 230 0:57 / /var/lib/docker/containers/dfac036ce135a8914e292cb2f6fea114f7339983c186366aa26d0051e93162cb/shm rw,nosuid,nodev,noexec,relatime - tmpfs shm rw,size=65536k`
+	inUse := map[string]bool{"47903e2e67014246eba27607809d5f5c2437c3bf84c2986393448f84093cc40b": true}
+	d.cleanupMountsFromReader(strings.NewReader(mountInfo), unmount, inUse)
+
+	expected := "/var/lib/docker/containers/dfac036ce135a8914e292cb2f6fea114f7339983c186366aa26d0051e93162cb/shm"
+	if len(unmounted) != 1 || unmounted[0] != expected {
+		t.Fatalf("Expected to unmount only %s, got %v", expected, unmounted)
+	}
+}
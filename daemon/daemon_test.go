@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/pkg/discovery"
 	_ "github.com/docker/docker/pkg/discovery/memory"
 	"github.com/docker/docker/pkg/registrar"
@@ -118,10 +120,14 @@ func TestGetContainer(t *testing.T) {
 }
 
 func initDaemonWithVolumeStore(tmp string) (*Daemon, error) {
+	var err error
 	daemon := &Daemon{
 		repository: tmp,
 		root:       tmp,
-		volumes:    store.New(),
+	}
+	daemon.volumes, err = store.New(tmp)
+	if err != nil {
+		return nil, err
 	}
 
 	volumesDriver, err := local.New(tmp, 0, 0)
@@ -487,3 +493,97 @@ func TestDaemonDiscoveryReloadFromEmptyDiscovery(t *testing.T) {
 		t.Fatal(e)
 	}
 }
+
+type fakeRestoreDriver struct {
+	execdriver.Driver
+	err        error
+	terminated []string
+}
+
+func (d *fakeRestoreDriver) Restore(c *execdriver.Command, pipes *execdriver.Pipes, hooks execdriver.Hooks) (execdriver.ExitStatus, error) {
+	return execdriver.ExitStatus{ExitCode: -1}, d.err
+}
+
+func (d *fakeRestoreDriver) Terminate(c *execdriver.Command) error {
+	d.terminated = append(d.terminated, c.ID)
+	return nil
+}
+
+func TestRestoreTerminatesContainerNotStartedForLiveRestore(t *testing.T) {
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:      "container_id",
+			State:   container.NewState(),
+			Command: &execdriver.Command{CommonCommand: execdriver.CommonCommand{ID: "container_id"}},
+		},
+	}
+	driver := &fakeRestoreDriver{err: fmt.Errorf("Container %s was not started for live restore", c.ID)}
+	daemon := &Daemon{execDriver: driver}
+
+	exitStatus, err := daemon.Restore(c, execdriver.NewPipes(nil, nil, nil, false), nil)
+	if err != driver.err {
+		t.Fatalf("Expected the error of the driver, got %v", err)
+	}
+	if len(driver.terminated) != 1 || driver.terminated[0] != c.ID {
+		t.Fatalf("Expected the container to be terminated, got %v", driver.terminated)
+	}
+	if exitStatus.ExitCode != 137 {
+		t.Fatalf("Expected exit code 137, got %d", exitStatus.ExitCode)
+	}
+}
+
+func TestRestoreDoesNotTerminateExitedContainer(t *testing.T) {
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:      "container_id",
+			State:   container.NewState(),
+			Command: &execdriver.Command{CommonCommand: execdriver.CommonCommand{ID: "container_id"}},
+		},
+	}
+	driver := &fakeRestoreDriver{err: execdriver.ErrNotRunning}
+	daemon := &Daemon{execDriver: driver}
+
+	if _, err := daemon.Restore(c, execdriver.NewPipes(nil, nil, nil, false), nil); err != execdriver.ErrNotRunning {
+		t.Fatalf("Expected %v, got %v", execdriver.ErrNotRunning, err)
+	}
+	if len(driver.terminated) != 0 {
+		t.Fatalf("Expected the container not to be terminated, got %v", driver.terminated)
+	}
+}
+
+func TestShutdownClosesVolumeStoreWithLiveRestore(t *testing.T) {
+	root, err := ioutil.TempDir("", "docker-shutdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	volumes, err := store.New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:    "container_id",
+			State: container.NewState(),
+		},
+	}
+	c.State.SetRunning(1234)
+	containers := container.NewMemoryStore()
+	containers.Add(c.ID, c)
+	daemon := &Daemon{
+		configStore: &Config{CommonConfig: CommonConfig{LiveRestore: true}},
+		containers:  containers,
+		volumes:     volumes,
+	}
+
+	if err := daemon.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	// The database is locked until it is closed
+	volumes, err = store.New(root)
+	if err != nil {
+		t.Fatalf("Expected the volume store to be closed, got %v", err)
+	}
+	volumes.Shutdown()
+}
@@ -522,11 +522,32 @@ func (daemon *Daemon) networkOptions(dconfig *Config) ([]nwconfig.Option, error)
 	return options, nil
 }
 
-func (daemon *Daemon) initNetworkController(config *Config) (libnetwork.NetworkController, error) {
+// activeSandboxes returns the network sandboxes of the containers to restore,
+// along with the sandbox options to restore them with.
+func (daemon *Daemon) activeSandboxes(containers map[*container.Container]bool) map[string]interface{} {
+	sandboxes := make(map[string]interface{})
+	for c := range containers {
+		if c.NetworkSettings == nil || c.NetworkSettings.SandboxID == "" {
+			continue
+		}
+		options, err := daemon.buildSandboxOptions(c, nil)
+		if err != nil {
+			logrus.Warnf("Failed to build sandbox options to restore container %s: %v", c.ID, err)
+			continue
+		}
+		sandboxes[c.NetworkSettings.SandboxID] = options
+	}
+	return sandboxes
+}
+
+func (daemon *Daemon) initNetworkController(config *Config, activeSandboxes map[string]interface{}) (libnetwork.NetworkController, error) {
 	netOptions, err := daemon.networkOptions(config)
 	if err != nil {
 		return nil, err
 	}
+	if len(activeSandboxes) > 0 {
+		netOptions = append(netOptions, nwconfig.OptionActiveSandboxes(activeSandboxes))
+	}
 
 	controller, err := libnetwork.New(netOptions...)
 	if err != nil {
@@ -544,8 +565,12 @@ func (daemon *Daemon) initNetworkController(config *Config) (libnetwork.NetworkC
 	}
 
 	if !config.DisableBridge {
-		// Initialize default driver "bridge"
-		if err := initBridgeDriver(controller, config); err != nil {
+		if n, err := controller.NetworkByName("bridge"); err == nil && len(activeSandboxes) > 0 {
+			// The default bridge network is in use by restored
+			// containers, it is kept as it is.
+			logrus.Infof("Default bridge network %s is in use by restored containers, its configuration is not updated", n.ID())
+		} else if err := initBridgeDriver(controller, config); err != nil {
+			// Initialize default driver "bridge"
 			return nil, err
 		}
 	}
@@ -119,7 +119,13 @@ func isBridgeNetworkDisabled(config *Config) bool {
 	return false
 }
 
-func (daemon *Daemon) initNetworkController(config *Config) (libnetwork.NetworkController, error) {
+// activeSandboxes returns the network sandboxes of the containers to restore.
+// Restoring containers is not supported on Windows.
+func (daemon *Daemon) activeSandboxes(containers map[*container.Container]bool) map[string]interface{} {
+	return nil
+}
+
+func (daemon *Daemon) initNetworkController(config *Config, activeSandboxes map[string]interface{}) (libnetwork.NetworkController, error) {
 	// Set the name of the virtual switch if not specified by -b on daemon start
 	if config.bridgeConfig.VirtualSwitchName == "" {
 		config.bridgeConfig.VirtualSwitchName = defaultVirtualSwitch
@@ -133,7 +139,7 @@ func (daemon *Daemon) registerLinks(container *container.Container, hostConfig *
 	return nil
 }
 
-func (daemon *Daemon) cleanupMounts() error {
+func (daemon *Daemon) cleanupMounts(restored map[*container.Container]bool) error {
 	return nil
 }
 
@@ -197,6 +197,15 @@ func (d Docker) GetCachedImage(imgID string, cfg *container.Config) (string, err
 	return cache.ID().String(), nil
 }
 
+// MakeImageCache returns the build cache of the daemon, which also matches
+// the build steps against the images of cacheFrom when there are any.
+func (d Docker) MakeImageCache(cacheFrom []string) builder.ImageCache {
+	if len(cacheFrom) == 0 {
+		return d
+	}
+	return d.Daemon.NewImageCache(cacheFrom)
+}
+
 // Following is specific to builder contexts
 
 // DetectContextFromRemoteURL returns a context and in certain cases the name of the dockerfile to be used
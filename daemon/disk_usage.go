@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+)
+
+// SystemDiskUsage returns information about the disk space used by the
+// images, containers and volumes of the daemon.
+func (daemon *Daemon) SystemDiskUsage() (*types.DiskUsage, error) {
+	containers, err := daemon.Containers(&ContainersConfig{All: true, Size: true})
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := daemon.Images("", "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk the layer chain of every image to find the size of each layer
+	// and how many of the listed images use it.
+	var (
+		layerSizes = make(map[layer.ChainID]int64)
+		layerRefs  = make(map[layer.ChainID]int)
+		chains     = make(map[string][]layer.ChainID)
+	)
+	for id, img := range daemon.imageStore.Map() {
+		chain, err := daemon.layerChain(img, layerSizes)
+		if err != nil {
+			return nil, err
+		}
+		chains[id.String()] = chain
+	}
+
+	containersByImage := make(map[string]int64)
+	for _, c := range containers {
+		containersByImage[c.ImageID]++
+	}
+
+	for _, img := range images {
+		for _, chainID := range chains[img.ID] {
+			layerRefs[chainID]++
+		}
+	}
+
+	var layersSize int64
+	for _, size := range layerSizes {
+		layersSize += size
+	}
+
+	for _, img := range images {
+		var shared int64
+		for _, chainID := range chains[img.ID] {
+			if layerRefs[chainID] > 1 {
+				shared += layerSizes[chainID]
+			}
+		}
+		img.SharedSize = shared
+		img.Containers = containersByImage[img.ID]
+	}
+
+	volumes, err := daemon.volumesDiskUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DiskUsage{
+		LayersSize: layersSize,
+		Images:     images,
+		Containers: containers,
+		Volumes:    volumes,
+	}, nil
+}
+
+// layerChain returns the chain IDs of the layers of img, from the top layer
+// down to the base layer, and records the size of each of them in sizes.
+func (daemon *Daemon) layerChain(img *image.Image, sizes map[layer.ChainID]int64) ([]layer.ChainID, error) {
+	chainID := img.RootFS.ChainID()
+	if chainID == "" {
+		return nil, nil
+	}
+	l, err := daemon.layerStore.Get(chainID)
+	if err != nil {
+		return nil, err
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+
+	var chain []layer.ChainID
+	for cur := l; cur != nil; cur = cur.Parent() {
+		chain = append(chain, cur.ChainID())
+		if _, ok := sizes[cur.ChainID()]; ok {
+			continue
+		}
+		size, err := cur.DiffSize()
+		if err != nil {
+			return nil, err
+		}
+		sizes[cur.ChainID()] = size
+	}
+	return chain, nil
+}
+
+// volumesDiskUsage returns all the volumes along with the number of
+// containers using them and, for the local driver, their size on disk.
+func (daemon *Daemon) volumesDiskUsage() ([]*types.Volume, error) {
+	volumes, _, err := daemon.volumes.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*types.Volume
+	for _, v := range volumes {
+		apiV := volumeToAPIType(v)
+		apiV.UsageData = &types.VolumeUsageData{
+			Size:     volumeSize(v),
+			RefCount: int64(len(daemon.volumes.Refs(v))),
+		}
+		out = append(out, apiV)
+	}
+	return out, nil
+}
+
+// volumeSize returns the size on disk of a volume of the local driver, or -1
+// if the size cannot be determined.
+func volumeSize(v volume.Volume) int64 {
+	if v.DriverName() != volume.DefaultDriverName {
+		return -1
+	}
+	size, err := directory.Size(v.Path())
+	if err != nil {
+		logrus.Warnf("could not determine size of volume %s: %v", v.Name(), err)
+		return -1
+	}
+	return size
+}
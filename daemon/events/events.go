@@ -4,6 +4,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/metrics"
 	"github.com/docker/docker/pkg/pubsub"
 	eventtypes "github.com/docker/engine-api/types/events"
 )
@@ -13,11 +15,14 @@ const (
 	bufferSize  = 1024
 )
 
+var eventsCounter = metrics.NewCounter("engine_daemon_events_total", "Number of events logged, by type.", "type")
+
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []eventtypes.Message
-	pub    *pubsub.Publisher
+	mu      sync.Mutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *Journal
 }
 
 // New returns new *Events instance
@@ -28,6 +33,15 @@ func New() *Events {
 	}
 }
 
+// NewWithJournal returns new *Events instance which records every event
+// in the given journal, so that past events can be replayed to subscribers
+// beyond the in-memory buffer and across daemon restarts.
+func NewWithJournal(j *Journal) *Events {
+	e := New()
+	e.journal = j
+	return e
+}
+
 // Subscribe adds new listener to events, returns slice of 64 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion), and a function to call
@@ -45,25 +59,55 @@ func (e *Events) Subscribe() ([]eventtypes.Message, chan interface{}, func()) {
 	return current, l, cancel
 }
 
-// SubscribeTopic adds new listener to events, returns slice of 64 stored
-// last events, a channel in which you can expect new events (in form
-// of interface{}, so you need type assertion).
-func (e *Events) SubscribeTopic(since, sinceNano int64, ef *Filter) ([]eventtypes.Message, chan interface{}) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	var buffered []eventtypes.Message
+// SubscribeTopic adds new listener to events, returns the stored events
+// which happened between since and until, and a channel in which you can
+// expect new events (in form of interface{}, so you need type assertion).
+// No stored events are returned if since is zero. A zero until means there
+// is no upper bound. Events are read from the journal when there is one,
+// otherwise only the last 64 events are available.
+func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtypes.Message, chan interface{}) {
 	topic := func(m interface{}) bool {
 		return ef.Include(m.(eventtypes.Message))
 	}
+	include := func(m eventtypes.Message) bool {
+		return ef.filter.Len() == 0 || topic(m)
+	}
+
+	var (
+		buffered []eventtypes.Message
+		cursor   journalCursor
+		replay   = !since.IsZero() && e.journal != nil
+	)
+	if replay {
+		// Read most of the journal without blocking new events from being
+		// logged, the rest is caught up on below while holding the lock.
+		evs, cur, err := e.journal.readFrom(journalCursor{}, since, until, include)
+		if err != nil {
+			logrus.Errorf("Error reading events from journal: %v", err)
+		}
+		buffered, cursor = evs, cur
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	if since != -1 {
+	if replay {
+		evs, _, err := e.journal.readFrom(cursor, since, until, include)
+		if err != nil {
+			logrus.Errorf("Error reading events from journal: %v", err)
+		}
+		buffered = append(buffered, evs...)
+	} else if !since.IsZero() {
 		for i := len(e.events) - 1; i >= 0; i-- {
 			ev := e.events[i]
-			if ev.Time < since || ((ev.Time == since) && (ev.TimeNano < sinceNano)) {
+			t := time.Unix(0, ev.TimeNano)
+			if t.Before(since) {
 				break
 			}
-			if ef.filter.Len() == 0 || topic(ev) {
+			if !until.IsZero() && t.After(until) {
+				continue
+			}
+			if include(ev) {
 				buffered = append([]eventtypes.Message{ev}, buffered...)
 			}
 		}
@@ -96,6 +140,7 @@ func (e *Events) Log(action, eventType string, actor eventtypes.Actor) {
 		Time:     now.Unix(),
 		TimeNano: now.UnixNano(),
 	}
+	eventsCounter.Inc(eventType)
 
 	// fill deprecated fields for container and images
 	switch eventType {
@@ -116,6 +161,11 @@ func (e *Events) Log(action, eventType string, actor eventtypes.Actor) {
 	} else {
 		e.events = append(e.events, jm)
 	}
+	if e.journal != nil {
+		if err := e.journal.Write(jm); err != nil {
+			logrus.Errorf("Error writing event to journal: %v", err)
+		}
+	}
 	e.mu.Unlock()
 	e.pub.Publish(jm)
 }
@@ -0,0 +1,333 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	eventtypes "github.com/docker/engine-api/types/events"
+)
+
+const (
+	journalPrefix = "events-"
+	journalSuffix = ".log"
+
+	// defaultSegmentSize is the size at which the active journal file is
+	// rotated when no total size limit is configured.
+	defaultSegmentSize = 8 * 1024 * 1024
+	// minSegmentSize keeps rotation from happening on every event when a
+	// very small size limit is configured.
+	minSegmentSize = 4096
+	// journalSegments is the number of files a size-limited journal is
+	// split into, so that pruning only drops a fraction of the history.
+	journalSegments = 4
+)
+
+// JournalOptions configures the retention of an event journal.
+type JournalOptions struct {
+	// MaxSize is the maximum number of bytes kept on disk. Zero means no limit.
+	MaxSize int64
+	// MaxAge is the maximum age of the events kept on disk. Zero means no limit.
+	MaxAge time.Duration
+}
+
+// journalCursor is a position in the journal, used to resume reading
+// where a previous read stopped.
+type journalCursor struct {
+	seq    int
+	offset int64
+}
+
+// Journal is an append-only log of event messages stored on disk. The log is
+// split into numbered segment files which are rotated when they grow too big,
+// and pruned according to the configured retention.
+type Journal struct {
+	mu          sync.Mutex
+	root        string
+	opts        JournalOptions
+	segmentSize int64
+	seq         int
+	f           *os.File
+	size        int64
+}
+
+// NewJournal opens the event journal stored in root, creating the directory
+// if needed. New events are appended to the most recent segment.
+func NewJournal(root string, opts JournalOptions) (*Journal, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+
+	segmentSize := int64(defaultSegmentSize)
+	if opts.MaxSize > 0 {
+		segmentSize = opts.MaxSize / journalSegments
+		if segmentSize < minSegmentSize {
+			segmentSize = minSegmentSize
+		}
+	}
+
+	j := &Journal{
+		root:        root,
+		opts:        opts,
+		segmentSize: segmentSize,
+	}
+
+	seqs, err := j.segments()
+	if err != nil {
+		return nil, err
+	}
+	if len(seqs) > 0 {
+		j.seq = seqs[len(seqs)-1]
+	}
+	if err := j.openSegment(); err != nil {
+		return nil, err
+	}
+	j.prune()
+	return j, nil
+}
+
+func (j *Journal) segmentPath(seq int) string {
+	return filepath.Join(j.root, fmt.Sprintf("%s%d%s", journalPrefix, seq, journalSuffix))
+}
+
+// segments returns the sequence numbers of the segment files on disk,
+// oldest first.
+func (j *Journal) segments() ([]int, error) {
+	fis, err := ioutil.ReadDir(j.root)
+	if err != nil {
+		return nil, err
+	}
+	var seqs []int
+	for _, fi := range fis {
+		name := fi.Name()
+		if !strings.HasPrefix(name, journalPrefix) || !strings.HasSuffix(name, journalSuffix) {
+			continue
+		}
+		seq, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, journalPrefix), journalSuffix))
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	return seqs, nil
+}
+
+func (j *Journal) openSegment() error {
+	f, err := os.OpenFile(j.segmentPath(j.seq), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	j.f = f
+	j.size = fi.Size()
+	return nil
+}
+
+// Write appends an event to the journal, rotating the active segment if it
+// has grown past its size limit.
+func (j *Journal) Write(m eventtypes.Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.f == nil {
+		return fmt.Errorf("event journal %s is closed", j.root)
+	}
+	if j.size > 0 && j.size+int64(len(b)) > j.segmentSize {
+		if err := j.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := j.f.Write(b)
+	j.size += int64(n)
+	return err
+}
+
+// rotate closes the active segment and starts a new one.
+// Called with j.mu held.
+func (j *Journal) rotate() error {
+	if err := j.f.Close(); err != nil {
+		return err
+	}
+	j.f = nil
+	j.seq++
+	if err := j.openSegment(); err != nil {
+		return err
+	}
+	j.prune()
+	return nil
+}
+
+// prune removes the oldest segments which are past the configured retention.
+// The active segment is never removed.
+// Called with j.mu held.
+func (j *Journal) prune() {
+	if j.opts.MaxSize <= 0 && j.opts.MaxAge <= 0 {
+		return
+	}
+	seqs, err := j.segments()
+	if err != nil {
+		logrus.Warnf("Error listing event journal segments: %v", err)
+		return
+	}
+
+	var (
+		total int64
+		sizes = make(map[int]int64, len(seqs))
+		ages  = make(map[int]time.Time, len(seqs))
+	)
+	for _, seq := range seqs {
+		if seq == j.seq {
+			// The active segment may grow up to segmentSize, leave room
+			// for it so that the limit holds until the next rotation.
+			total += j.segmentSize
+			continue
+		}
+		fi, err := os.Stat(j.segmentPath(seq))
+		if err != nil {
+			continue
+		}
+		sizes[seq] = fi.Size()
+		ages[seq] = fi.ModTime()
+		total += fi.Size()
+	}
+
+	for _, seq := range seqs {
+		if seq == j.seq {
+			break
+		}
+		tooBig := j.opts.MaxSize > 0 && total > j.opts.MaxSize
+		tooOld := j.opts.MaxAge > 0 && time.Since(ages[seq]) > j.opts.MaxAge
+		if !tooBig && !tooOld {
+			break
+		}
+		if err := os.Remove(j.segmentPath(seq)); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("Error removing event journal segment: %v", err)
+			return
+		}
+		total -= sizes[seq]
+	}
+}
+
+// Read returns the events stored in the journal which happened between since
+// and until (a zero until means no upper bound) and for which include returns
+// true, oldest first.
+func (j *Journal) Read(since, until time.Time, include func(eventtypes.Message) bool) ([]eventtypes.Message, error) {
+	evs, _, err := j.readFrom(journalCursor{}, since, until, include)
+	return evs, err
+}
+
+// readFrom reads the journal starting at the given cursor and returns the
+// matching events along with a cursor pointing after the last event read.
+func (j *Journal) readFrom(from journalCursor, since, until time.Time, include func(eventtypes.Message) bool) ([]eventtypes.Message, journalCursor, error) {
+	seqs, err := j.segments()
+	if err != nil {
+		return nil, from, err
+	}
+
+	if j.opts.MaxAge > 0 {
+		if oldest := time.Now().Add(-j.opts.MaxAge); since.Before(oldest) {
+			since = oldest
+		}
+	}
+
+	var (
+		evs []eventtypes.Message
+		cur = from
+	)
+	for _, seq := range seqs {
+		if seq < from.seq {
+			continue
+		}
+		var offset int64
+		if seq == from.seq {
+			offset = from.offset
+		}
+		segEvs, end, err := j.readSegment(seq, offset, since, until, include)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// pruned while we were reading
+				continue
+			}
+			return nil, cur, err
+		}
+		evs = append(evs, segEvs...)
+		cur = journalCursor{seq: seq, offset: end}
+	}
+	return evs, cur, nil
+}
+
+func (j *Journal) readSegment(seq int, offset int64, since, until time.Time, include func(eventtypes.Message) bool) ([]eventtypes.Message, int64, error) {
+	f, err := os.Open(j.segmentPath(seq))
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, 0); err != nil {
+		return nil, offset, err
+	}
+
+	var evs []eventtypes.Message
+	rd := bufio.NewReader(f)
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err == io.EOF {
+			// Only complete lines are consumed; a partial line is either
+			// being written right now or was cut short by a crash.
+			return evs, offset, nil
+		}
+		if err != nil {
+			return nil, offset, err
+		}
+		offset += int64(len(line))
+
+		var m eventtypes.Message
+		if err := json.Unmarshal(line, &m); err != nil {
+			logrus.Debugf("Skipping corrupted entry in event journal %s: %v", f.Name(), err)
+			continue
+		}
+		t := time.Unix(0, m.TimeNano)
+		if t.Before(since) {
+			continue
+		}
+		if !until.IsZero() && t.After(until) {
+			continue
+		}
+		if include == nil || include(m) {
+			evs = append(evs, m)
+		}
+	}
+}
+
+// Close closes the active segment. Further writes fail.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
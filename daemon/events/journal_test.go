@@ -0,0 +1,213 @@
+package events
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	eventtypes "github.com/docker/engine-api/types/events"
+	"github.com/docker/engine-api/types/filters"
+)
+
+func newTestJournal(t *testing.T, opts JournalOptions) (*Journal, string) {
+	root, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := NewJournal(root, opts)
+	if err != nil {
+		os.RemoveAll(root)
+		t.Fatal(err)
+	}
+	return j, root
+}
+
+func journalMessage(id string, t time.Time) eventtypes.Message {
+	return eventtypes.Message{
+		ID:       id,
+		Status:   "test",
+		Type:     eventtypes.ContainerEventType,
+		Action:   "test",
+		Actor:    eventtypes.Actor{ID: id},
+		Time:     t.Unix(),
+		TimeNano: t.UnixNano(),
+	}
+}
+
+func TestJournalReadAcrossRestart(t *testing.T) {
+	j, root := newTestJournal(t, JournalOptions{})
+	defer os.RemoveAll(root)
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		if err := j.Write(journalMessage(fmt.Sprintf("cont%d", i), now.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := NewJournal(root, JournalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if err := j.Write(journalMessage("cont10", now.Add(10*time.Second))); err != nil {
+		t.Fatal(err)
+	}
+
+	evs, err := j.Read(now, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 11 {
+		t.Fatalf("Expected 11 events, got %d", len(evs))
+	}
+	for i, ev := range evs {
+		if expected := fmt.Sprintf("cont%d", i); ev.ID != expected {
+			t.Fatalf("Expected event %d to be %s, got %s", i, expected, ev.ID)
+		}
+	}
+
+	evs, err = j.Read(now.Add(2*time.Second), now.Add(4*time.Second), func(m eventtypes.Message) bool {
+		return m.ID != "cont3"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 2 || evs[0].ID != "cont2" || evs[1].ID != "cont4" {
+		t.Fatalf("Unexpected events between since and until: %v", evs)
+	}
+}
+
+func TestJournalRotateAndPruneBySize(t *testing.T) {
+	j, root := newTestJournal(t, JournalOptions{MaxSize: 4 * minSegmentSize})
+	defer os.RemoveAll(root)
+	defer j.Close()
+
+	now := time.Now()
+	for i := 0; i < 1000; i++ {
+		if err := j.Write(journalMessage(fmt.Sprintf("cont%d", i), now)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seqs, err := j.segments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seqs) < 2 {
+		t.Fatalf("Expected the journal to be rotated, got %d segments", len(seqs))
+	}
+
+	var total int64
+	for _, seq := range seqs {
+		fi, err := os.Stat(j.segmentPath(seq))
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += fi.Size()
+	}
+	if total > 4*minSegmentSize {
+		t.Fatalf("Expected journal to be pruned to %d bytes, got %d", 4*minSegmentSize, total)
+	}
+
+	evs, err := j.Read(now, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) == 0 || evs[len(evs)-1].ID != "cont999" {
+		t.Fatalf("Expected the latest events to be kept, got %d events", len(evs))
+	}
+}
+
+func TestJournalPruneByAge(t *testing.T) {
+	j, root := newTestJournal(t, JournalOptions{})
+	defer os.RemoveAll(root)
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := j.Write(journalMessage("old", old)); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+	if err := os.Chtimes(j.segmentPath(0), old, old); err != nil {
+		t.Fatal(err)
+	}
+	// Start a new segment, as the active one is never pruned.
+	if err := ioutil.WriteFile(filepath.Join(root, "events-1.log"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := NewJournal(root, JournalOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	if err := j.Write(journalMessage("new", time.Now())); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(j.segmentPath(0)); !os.IsNotExist(err) {
+		t.Fatalf("Expected old segment to be pruned, got %v", err)
+	}
+	evs, err := j.Read(time.Unix(0, 0), time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 1 || evs[0].ID != "new" {
+		t.Fatalf("Expected only the new event, got %v", evs)
+	}
+}
+
+func TestJournalSkipsCorruptedEntries(t *testing.T) {
+	j, root := newTestJournal(t, JournalOptions{})
+	defer os.RemoveAll(root)
+	defer j.Close()
+
+	now := time.Now()
+	if err := j.Write(journalMessage("cont1", now)); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(j.segmentPath(0), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("{garbage\n"))
+	f.Close()
+	if err := j.Write(journalMessage("cont2", now)); err != nil {
+		t.Fatal(err)
+	}
+
+	evs, err := j.Read(now, time.Time{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 2 || evs[0].ID != "cont1" || evs[1].ID != "cont2" {
+		t.Fatalf("Expected corrupted entry to be skipped, got %v", evs)
+	}
+}
+
+func TestEventsSubscribeTopicFromJournal(t *testing.T) {
+	j, root := newTestJournal(t, JournalOptions{})
+	defer os.RemoveAll(root)
+	defer j.Close()
+
+	e := NewWithJournal(j)
+	since := time.Now()
+	for i := 0; i < eventsLimit*2; i++ {
+		e.Log("test", eventtypes.ContainerEventType, eventtypes.Actor{ID: fmt.Sprintf("cont%d", i)})
+	}
+
+	buffered, l := e.SubscribeTopic(since, time.Time{}, NewFilter(filters.NewArgs()))
+	defer e.Evict(l)
+	if len(buffered) != eventsLimit*2 {
+		t.Fatalf("Expected %d events from the journal, got %d", eventsLimit*2, len(buffered))
+	}
+	if buffered[0].ID != "cont0" {
+		t.Fatalf("Expected first event to be cont0, got %s", buffered[0].ID)
+	}
+}
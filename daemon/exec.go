@@ -1,8 +1,14 @@
 package daemon
 
 import (
+	"fmt"
 	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -12,7 +18,10 @@ import (
 	derr "github.com/docker/docker/errors"
 	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/promise"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/pkg/term"
+	"github.com/docker/docker/utils"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/strslice"
 )
@@ -92,6 +101,14 @@ func (d *Daemon) ContainerExecCreate(config *types.ExecConfig) (string, error) {
 		return "", err
 	}
 
+	workingDir := config.WorkingDir
+	if workingDir != "" {
+		workingDir = filepath.FromSlash(workingDir) // Ensure in platform semantics
+		if !system.IsAbs(workingDir) {
+			return "", fmt.Errorf("The working directory '%s' is invalid. It needs to be an absolute path.", config.WorkingDir)
+		}
+	}
+
 	cmd := strslice.New(config.Cmd...)
 	entrypoint, args := d.getEntrypointAndArgs(strslice.New(), cmd)
 
@@ -110,6 +127,8 @@ func (d *Daemon) ContainerExecCreate(config *types.ExecConfig) (string, error) {
 			Arguments:  args,
 		},
 	}
+	processConfig.Env = execEnv(container, config.Env)
+	processConfig.Dir = workingDir
 	setPlatformSpecificExecProcessConfig(config, container, processConfig)
 
 	execConfig := exec.NewConfig()
@@ -127,6 +146,17 @@ func (d *Daemon) ContainerExecCreate(config *types.ExecConfig) (string, error) {
 	return execConfig.ID, nil
 }
 
+// execEnv returns the environment of an exec in the container, with the
+// variables of env set over the environment of the container. A variable
+// without a value in env is removed.
+func execEnv(container *container.Container, env []string) []string {
+	var defaults []string
+	if container.Command != nil {
+		defaults = append(defaults, container.Command.ProcessConfig.Env...)
+	}
+	return utils.ReplaceOrAppendEnvValues(defaults, env)
+}
+
 // ContainerExecStart starts a previously set up exec instance. The
 // std streams are set up.
 func (d *Daemon) ContainerExecStart(name string, stdin io.ReadCloser, stdout io.Writer, stderr io.Writer) error {
@@ -218,6 +248,7 @@ func (d *Daemon) Exec(c *container.Container, execConfig *exec.Config, pipes *ex
 	hooks := execdriver.Hooks{
 		Start: startCallback,
 	}
+	oomEvents := c.OOMEvents()
 	exitStatus, err := d.execDriver.Exec(c.Command, execConfig.ProcessConfig, pipes, hooks)
 
 	// On err, make sure we don't leave ExitCode at zero
@@ -225,12 +256,108 @@ func (d *Daemon) Exec(c *container.Container, execConfig *exec.Config, pipes *ex
 		exitStatus = 128
 	}
 
+	execConfig.Lock()
 	execConfig.ExitCode = &exitStatus
 	execConfig.Running = false
+	execConfig.FinishedAt = time.Now().UTC()
+	// The process is assumed to be killed by the OOM killer if it was killed
+	// while the container was out of memory.
+	execConfig.OOMKilled = exitStatus == 128+int(syscall.SIGKILL) && c.OOMEvents() != oomEvents
+	execConfig.Unlock()
 
 	return exitStatus, err
 }
 
+// ContainerExecList returns the exec processes of the container name, both
+// running and finished, which have not been cleaned up yet.
+func (d *Daemon) ContainerExecList(name string) ([]*types.ExecSummary, error) {
+	container, err := d.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []*exec.Config
+	for _, ec := range d.execCommands.Commands() {
+		if ec.ContainerID == container.ID {
+			configs = append(configs, ec)
+		}
+	}
+	// The execs removed from the daemon's store are still in the history.
+	for _, ec := range container.ExecHistory.List() {
+		if d.execCommands.Get(ec.ID) == nil {
+			configs = append(configs, ec)
+		}
+	}
+	sort.Sort(execsByCreated(configs))
+
+	execs := make([]*types.ExecSummary, 0, len(configs))
+	for _, ec := range configs {
+		execs = append(execs, execToAPIType(ec))
+	}
+	return execs, nil
+}
+
+// execToAPIType returns the summary of the exec ec for the remote API.
+func execToAPIType(ec *exec.Config) *types.ExecSummary {
+	ec.Lock()
+	defer ec.Unlock()
+
+	summary := &types.ExecSummary{
+		ID:         ec.ID,
+		Command:    strings.TrimSpace(ec.ProcessConfig.Entrypoint + " " + strings.Join(ec.ProcessConfig.Arguments, " ")),
+		Running:    ec.Running,
+		OOMKilled:  ec.OOMKilled,
+		Pid:        ec.Pid,
+		Tty:        ec.ProcessConfig.Tty,
+		CreatedAt:  ec.CreatedAt.Format(time.RFC3339Nano),
+		StartedAt:  ec.StartedAt.Format(time.RFC3339Nano),
+		FinishedAt: ec.FinishedAt.Format(time.RFC3339Nano),
+	}
+	if ec.ExitCode != nil {
+		exitCode := *ec.ExitCode
+		summary.ExitCode = &exitCode
+	}
+	setPlatformSpecificExecSummary(ec.ProcessConfig, summary)
+	return summary
+}
+
+// execsByCreated is a sortable list of exec configs, oldest first.
+type execsByCreated []*exec.Config
+
+func (r execsByCreated) Len() int           { return len(r) }
+func (r execsByCreated) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r execsByCreated) Less(i, j int) bool { return r[i].CreatedAt.Before(r[j].CreatedAt) }
+
+// ContainerExecKill sends the signal sig to the process of the running exec
+// name. SIGKILL is sent if sig is 0.
+func (d *Daemon) ContainerExecKill(name string, sig uint64) error {
+	ec, err := d.getExecConfig(name)
+	if err != nil {
+		return err
+	}
+
+	if sig == 0 {
+		sig = uint64(syscall.SIGKILL)
+	}
+	if !signal.ValidSignalForPlatform(syscall.Signal(sig)) {
+		return fmt.Errorf("The %s daemon does not support signal %d", runtime.GOOS, sig)
+	}
+
+	ec.Lock()
+	defer ec.Unlock()
+	if !ec.Running || ec.Pid == 0 {
+		return derr.ErrorCodeExecNotRunning.WithArgs(ec.ID)
+	}
+	logrus.Debugf("Sending %d to exec %s", sig, ec.ID)
+	if err := killExecProcess(ec.ProcessConfig, int(sig)); err != nil {
+		if err == execdriver.ErrProcessExited {
+			return derr.ErrorCodeExecNotRunning.WithArgs(ec.ID)
+		}
+		return err
+	}
+	return nil
+}
+
 // execCommandGC runs a ticker to clean up the daemon references
 // of exec configs that are no longer part of the container.
 func (d *Daemon) execCommandGC() {
@@ -252,9 +379,44 @@ func (d *Daemon) execCommandGC() {
 		if cleaned > 0 {
 			logrus.Debugf("clean %d unused exec commands", cleaned)
 		}
+
+		pruned := 0
+		for _, c := range d.containers.List() {
+			pruned += c.ExecHistory.Prune(time.Now().UTC().Add(-d.execHistoryTTL))
+		}
+		if pruned > 0 {
+			logrus.Debugf("clean %d exec commands from history", pruned)
+		}
 	}
 }
 
+// getExecFromHistory looks up a finished exec instance by id in the exec
+// history of the containers.
+func (d *Daemon) getExecFromHistory(id string) *exec.Config {
+	for _, c := range d.containers.List() {
+		if ec := c.ExecHistory.Get(id); ec != nil {
+			return ec
+		}
+	}
+	return nil
+}
+
+// parseExecHistoryTTL returns how long the finished execs are kept in the
+// history of the containers from the daemon configuration.
+func parseExecHistoryTTL(config *Config) (time.Duration, error) {
+	if config.ExecHistoryTTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(config.ExecHistoryTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid exec-history-ttl %q: %v", config.ExecHistoryTTL, err)
+	}
+	if ttl < 0 {
+		return 0, fmt.Errorf("invalid exec-history-ttl %q: must not be negative", config.ExecHistoryTTL)
+	}
+	return ttl, nil
+}
+
 // containerExecIds returns a list of all the current exec ids that are in use
 // and running inside a container.
 func (d *Daemon) containerExecIds() map[string]struct{} {
@@ -280,6 +442,10 @@ func (d *Daemon) containerExec(container *container.Container, ec *exec.Config)
 				c.Close()
 			}
 		}
+		ec.Lock()
+		ec.Pid = pid
+		ec.StartedAt = time.Now().UTC()
+		ec.Unlock()
 		ec.Close()
 		return nil
 	}
@@ -310,5 +476,17 @@ func (d *Daemon) monitorExec(container *container.Container, execConfig *exec.Co
 	// remove the exec command from the container's store only and not the
 	// daemon's store so that the exec command can be inspected.
 	container.ExecCommands.Delete(execConfig.ID)
+	// keep it in the container's history so that it can still be inspected
+	// once removed from the daemon's store. Health check probes are left out,
+	// so that they do not push the execs of the user out of the history.
+	if d.execHistoryTTL > 0 && !execConfig.Probe {
+		container.ExecHistory.Add(execConfig)
+	}
+
+	attributes := map[string]string{
+		"execID":   execConfig.ID,
+		"exitCode": strconv.Itoa(exitCode),
+	}
+	d.LogContainerEventWithAttributes(container, "exec_die", attributes)
 	return err
 }
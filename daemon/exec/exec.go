@@ -26,6 +26,15 @@ type Config struct {
 	CanRemove     bool
 	ContainerID   string
 	DetachKeys    []byte
+	Pid           int
+	OOMKilled     bool
+	CreatedAt     time.Time
+	StartedAt     time.Time
+	FinishedAt    time.Time
+
+	// Probe is set for the execs of health check probes, which are not
+	// kept in the history of the container.
+	Probe bool
 
 	// waitStart will be closed immediately after the exec is really started.
 	waitStart chan struct{}
@@ -36,6 +45,7 @@ func NewConfig() *Config {
 	return &Config{
 		ID:           stringid.GenerateNonCryptoID(),
 		StreamConfig: runconfig.NewStreamConfig(),
+		CreatedAt:    time.Now().UTC(),
 		waitStart:    make(chan struct{}),
 	}
 }
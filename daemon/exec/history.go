@@ -0,0 +1,85 @@
+package exec
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is the number of finished execs kept in the history of
+// a container.
+const DefaultHistorySize = 64
+
+// History keeps the configurations of the last finished execs of a
+// container, so that their exit status can be inspected after they are
+// removed from the Store.
+type History struct {
+	sync.Mutex
+	size    int
+	configs []*Config
+}
+
+// NewHistory initializes a new exec history keeping up to size execs.
+func NewHistory(size int) *History {
+	return &History{size: size}
+}
+
+// Add adds a finished exec configuration to the history, and removes the
+// oldest one if the history is full.
+func (h *History) Add(config *Config) {
+	h.Lock()
+	defer h.Unlock()
+
+	for i, c := range h.configs {
+		if c.ID == config.ID {
+			h.configs = append(h.configs[:i], h.configs[i+1:]...)
+			break
+		}
+	}
+	h.configs = append(h.configs, config)
+	if len(h.configs) > h.size {
+		h.configs[0] = nil
+		h.configs = h.configs[1:]
+	}
+}
+
+// Get returns an exec configuration of the history by its id.
+func (h *History) Get(id string) *Config {
+	h.Lock()
+	defer h.Unlock()
+
+	for _, c := range h.configs {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// List returns the exec configurations of the history, oldest first.
+func (h *History) List() []*Config {
+	h.Lock()
+	configs := make([]*Config, len(h.configs))
+	copy(configs, h.configs)
+	h.Unlock()
+	return configs
+}
+
+// Prune removes the exec configurations of the execs which finished before
+// t. It returns the number of removed configurations.
+func (h *History) Prune(t time.Time) int {
+	h.Lock()
+	defer h.Unlock()
+
+	var kept []*Config
+	for _, c := range h.configs {
+		c.Lock()
+		finishedAt := c.FinishedAt
+		c.Unlock()
+		if !finishedAt.Before(t) {
+			kept = append(kept, c)
+		}
+	}
+	pruned := len(h.configs) - len(kept)
+	h.configs = kept
+	return pruned
+}
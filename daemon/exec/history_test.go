@@ -0,0 +1,40 @@
+package exec
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	h := NewHistory(2)
+	now := time.Now().UTC()
+
+	var configs []*Config
+	for i := 0; i < 3; i++ {
+		c := NewConfig()
+		c.FinishedAt = now.Add(time.Duration(i) * time.Minute)
+		h.Add(c)
+		configs = append(configs, c)
+	}
+
+	if h.Get(configs[0].ID) != nil {
+		t.Fatal("Expected the oldest exec to be removed from the full history")
+	}
+	list := h.List()
+	if len(list) != 2 || list[0] != configs[1] || list[1] != configs[2] {
+		t.Fatalf("Expected the last 2 execs in the history, got %v", list)
+	}
+
+	// Adding an exec again moves it to the end of the history.
+	h.Add(configs[1])
+	if list := h.List(); len(list) != 2 || list[0] != configs[2] || list[1] != configs[1] {
+		t.Fatalf("Expected the exec to be moved to the end of the history, got %v", list)
+	}
+
+	if pruned := h.Prune(now.Add(90 * time.Second)); pruned != 1 {
+		t.Fatalf("Expected 1 exec to be pruned, got %d", pruned)
+	}
+	if h.Get(configs[1].ID) != nil || h.Get(configs[2].ID) != configs[2] {
+		t.Fatalf("Expected only the last exec to be kept, got %v", h.List())
+	}
+}
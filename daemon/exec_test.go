@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/docker/pkg/registrar"
+	"github.com/docker/docker/pkg/truncindex"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+func newTestExecConfig(containerID string, created time.Time, entrypoint string, args ...string) *exec.Config {
+	ec := exec.NewConfig()
+	ec.ContainerID = containerID
+	ec.CreatedAt = created
+	ec.ProcessConfig = &execdriver.ProcessConfig{
+		CommonProcessConfig: execdriver.CommonProcessConfig{
+			Entrypoint: entrypoint,
+			Arguments:  args,
+		},
+	}
+	return ec
+}
+
+func TestContainerExecList(t *testing.T) {
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:           "5a4ff6a163ad4533d22d69a2b8960bf7fafdcba06e72d2febdba229008b0bf57",
+			Name:         "tender_bardeen",
+			State:        container.NewState(),
+			ExecCommands: exec.NewStore(),
+			ExecHistory:  exec.NewHistory(exec.DefaultHistorySize),
+		},
+	}
+	c.State.SetRunning(1234)
+	store := container.NewMemoryStore()
+	store.Add(c.ID, c)
+	daemon := &Daemon{
+		containers:   store,
+		execCommands: exec.NewStore(),
+		idIndex:      truncindex.NewTruncIndex([]string{c.ID}),
+		nameIndex:    registrar.NewRegistrar(),
+	}
+
+	now := time.Now().UTC()
+	finished := newTestExecConfig(c.ID, now.Add(-time.Minute), "ls", "-l")
+	exitCode := 2
+	finished.ExitCode = &exitCode
+	running := newTestExecConfig(c.ID, now, "sh")
+	running.Running = true
+	running.Pid = 5678
+	daemon.registerExecCommand(c, running)
+	daemon.registerExecCommand(c, finished)
+	daemon.execCommands.Add("other", newTestExecConfig("other", now, "top"))
+
+	execs, err := daemon.ContainerExecList("5a4ff6a163ad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(execs) != 2 {
+		t.Fatalf("Expected 2 execs, got %d", len(execs))
+	}
+	if execs[0].ID != finished.ID || execs[0].Command != "ls -l" || execs[0].Running || execs[0].ExitCode == nil || *execs[0].ExitCode != 2 {
+		t.Fatalf("Unexpected finished exec %+v", execs[0])
+	}
+	if execs[1].ID != running.ID || execs[1].Command != "sh" || !execs[1].Running || execs[1].ExitCode != nil || execs[1].Pid != 5678 {
+		t.Fatalf("Unexpected running exec %+v", execs[1])
+	}
+
+	if err := daemon.ContainerExecKill(finished.ID, 0); err == nil {
+		t.Fatal("Expected an error killing an exec which is not running")
+	}
+	if err := daemon.ContainerExecKill("unknown", 0); err == nil {
+		t.Fatal("Expected an error killing an exec which does not exist")
+	}
+
+	// A finished exec removed from the daemon's store is inspected and
+	// listed from the history, even once the container is stopped.
+	daemon.unregisterExecCommand(c, finished)
+	c.ExecHistory.Add(finished)
+	c.State.SetStopped(&execdriver.ExitStatus{ExitCode: 0})
+	ec, err := daemon.ContainerExecInspect(finished.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ec != finished {
+		t.Fatalf("Expected the exec from the history, got %+v", ec)
+	}
+	if _, err := daemon.ContainerExecInspect(running.ID); err == nil {
+		t.Fatal("Expected an error inspecting an exec of a stopped container which is not in the history")
+	}
+	execs, err = daemon.ContainerExecList(c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(execs) != 2 || execs[0].ID != finished.ID {
+		t.Fatalf("Expected the exec from the history to be listed, got %v", execs)
+	}
+}
+
+type fakeExecDriver struct {
+	execdriver.Driver
+}
+
+func (d *fakeExecDriver) Exec(c *execdriver.Command, processConfig *execdriver.ProcessConfig, pipes *execdriver.Pipes, hooks execdriver.Hooks) (int, error) {
+	return 0, nil
+}
+
+func TestExecHistoryWithoutProbes(t *testing.T) {
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:           "5a4ff6a163ad4533d22d69a2b8960bf7fafdcba06e72d2febdba229008b0bf57",
+			Name:         "tender_bardeen",
+			Config:       &containertypes.Config{},
+			State:        container.NewState(),
+			ExecCommands: exec.NewStore(),
+			ExecHistory:  exec.NewHistory(exec.DefaultHistorySize),
+		},
+	}
+	daemon := &Daemon{
+		EventsService:  events.New(),
+		execCommands:   exec.NewStore(),
+		execDriver:     &fakeExecDriver{},
+		execHistoryTTL: time.Hour,
+	}
+
+	user := newTestExecConfig(c.ID, time.Now().UTC(), "ls")
+	daemon.registerExecCommand(c, user)
+	if err := daemon.monitorExec(c, user, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2*exec.DefaultHistorySize; i++ {
+		probe := newTestExecConfig(c.ID, time.Now().UTC(), "true")
+		probe.Probe = true
+		daemon.registerExecCommand(c, probe)
+		if err := daemon.monitorExec(c, probe, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ec := c.ExecHistory.Get(user.ID); ec != user {
+		t.Fatalf("Expected the exec of the user to be kept in the history, got %+v", ec)
+	}
+	if execs := c.ExecHistory.List(); len(execs) != 1 {
+		t.Fatalf("Expected only the exec of the user in the history, got %d execs", len(execs))
+	}
+}
+
+func TestParseExecHistoryTTL(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"":    0,
+		"0":   0,
+		"30m": 30 * time.Minute,
+	} {
+		ttl, err := parseExecHistoryTTL(&Config{CommonConfig: CommonConfig{ExecHistoryTTL: value}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ttl != expected {
+			t.Fatalf("Expected %v for %q, got %v", expected, value, ttl)
+		}
+	}
+	for _, value := range []string{"forever", "-1h"} {
+		if _, err := parseExecHistoryTTL(&Config{CommonConfig: CommonConfig{ExecHistoryTTL: value}}); err == nil {
+			t.Fatalf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestExecEnv(t *testing.T) {
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			Command: &execdriver.Command{},
+		},
+	}
+	c.Command.ProcessConfig.Env = []string{"PATH=/usr/bin:/bin", "HOSTNAME=5a4ff6a163ad", "FOO=container"}
+
+	env := execEnv(c, []string{"FOO=exec", "HOSTNAME", "BAR=exec"})
+	expected := []string{"PATH=/usr/bin:/bin", "FOO=exec", "BAR=exec"}
+	if len(env) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, env)
+	}
+	for i := range expected {
+		if env[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, env)
+		}
+	}
+	if c.Command.ProcessConfig.Env[2] != "FOO=container" {
+		t.Fatalf("Expected the environment of the container to be unchanged, got %v", c.Command.ProcessConfig.Env)
+	}
+}
@@ -3,6 +3,8 @@
 package daemon
 
 import (
+	"syscall"
+
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/engine-api/types"
@@ -19,3 +21,16 @@ func setPlatformSpecificExecProcessConfig(config *types.ExecConfig, container *c
 	pc.User = user
 	pc.Privileged = config.Privileged
 }
+
+// setPlatformSpecificExecSummary sets platform-specific fields of the exec
+// summary from the ProcessConfig structure.
+func setPlatformSpecificExecSummary(pc *execdriver.ProcessConfig, summary *types.ExecSummary) {
+	summary.User = pc.User
+	summary.Privileged = pc.Privileged
+}
+
+// killExecProcess sends the signal sig to the process of an exec, through
+// the execution driver.
+func killExecProcess(pc *execdriver.ProcessConfig, sig int) error {
+	return pc.Signal(syscall.Signal(sig))
+}
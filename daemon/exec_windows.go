@@ -3,6 +3,7 @@ package daemon
 import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/execdriver"
+	derr "github.com/docker/docker/errors"
 	"github.com/docker/engine-api/types"
 )
 
@@ -10,3 +11,13 @@ import (
 // ProcessConfig structure. This is a no-op on Windows
 func setPlatformSpecificExecProcessConfig(config *types.ExecConfig, container *container.Container, pc *execdriver.ProcessConfig) {
 }
+
+// setPlatformSpecificExecSummary sets platform-specific fields of the exec
+// summary from the ProcessConfig structure. This is a no-op on Windows
+func setPlatformSpecificExecSummary(pc *execdriver.ProcessConfig, summary *types.ExecSummary) {
+}
+
+// killExecProcess is not supported on Windows and returns an error.
+func killExecProcess(pc *execdriver.ProcessConfig, sig int) error {
+	return derr.ErrorCodeNoExecKill
+}
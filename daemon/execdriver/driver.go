@@ -3,6 +3,7 @@ package execdriver
 import (
 	"errors"
 	"io"
+	"os"
 	"os/exec"
 	"time"
 
@@ -19,6 +20,9 @@ var (
 	ErrWaitTimeoutReached      = errors.New("Wait timeout reached")
 	ErrDriverAlreadyRegistered = errors.New("A driver already registered this docker init function")
 	ErrDriverNotFound          = errors.New("The requested docker init has not been found")
+	ErrRestoreNotSupported     = errors.New("The execution driver does not support restoring containers")
+	ErrCheckpointNotSupported  = errors.New("The execution driver does not support checkpointing containers")
+	ErrProcessExited           = errors.New("The process has exited")
 )
 
 // DriverCallback defines a callback function which is used in "Run" and "Exec".
@@ -59,6 +63,11 @@ type Driver interface {
 	// the exit code. It's the last stage on Docker side for running a container.
 	Run(c *Command, pipes *Pipes, hooks Hooks) (ExitStatus, error)
 
+	// Restore re-attaches to a container whose process kept running while
+	// the daemon was down, blocks until the process exits and returns the
+	// exit code.
+	Restore(c *Command, pipes *Pipes, hooks Hooks) (ExitStatus, error)
+
 	// Exec executes the process in an existing container, blocks until the
 	// process exits and returns the exit code.
 	Exec(c *Command, processConfig *ProcessConfig, pipes *Pipes, hooks Hooks) (int, error)
@@ -66,6 +75,11 @@ type Driver interface {
 	// Kill sends signals to process in container.
 	Kill(c *Command, sig int) error
 
+	// Checkpoint saves the state of the processes of a running container
+	// to disk. A container is restored from a checkpoint by running it with
+	// the checkpoint directory set in its command.
+	Checkpoint(c *Command, opts *CheckpointOpts) error
+
 	// Pause pauses a container.
 	Pause(c *Command) error
 
@@ -98,6 +112,15 @@ type Driver interface {
 	SupportsHooks() bool
 }
 
+// CheckpointOpts contains the options to checkpoint a container.
+type CheckpointOpts struct {
+	// Directory is where the checkpoint images are written.
+	Directory string
+	// LeaveRunning keeps the container running after the checkpoint,
+	// otherwise its processes are killed.
+	LeaveRunning bool
+}
+
 // CommonResources contains the resource configs for a driver that are
 // common across platforms.
 type CommonResources struct {
@@ -124,6 +147,11 @@ type CommonProcessConfig struct {
 	Entrypoint string   `json:"entrypoint"`
 	Arguments  []string `json:"arguments"`
 	Terminal   Terminal `json:"-"` // standard or tty terminal
+
+	// Signal sends a signal to the process. It is set by the execution
+	// driver before calling the Start hook, and returns ErrProcessExited
+	// once the process has exited, as its pid may be reused then.
+	Signal func(os.Signal) error `json:"-"`
 }
 
 // CommonCommand is the common platform agnostic part of the Command structure
@@ -139,5 +167,6 @@ type CommonCommand struct {
 	Resources     *Resources    `json:"resources"`
 	Rootfs        string        `json:"rootfs"` // root fs of the container
 	WorkingDir    string        `json:"working_dir"`
-	TmpDir        string        `json:"tmpdir"` // Directory used to store docker tmpdirs.
+	TmpDir        string        `json:"tmpdir"`         // Directory used to store docker tmpdirs.
+	CheckpointDir string        `json:"checkpoint_dir"` // Directory of the checkpoint to restore the container from.
 }
@@ -0,0 +1,71 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// Checkpoint implements the exec driver Driver interface,
+// it calls libcontainer API to dump the processes of a container with criu.
+func (d *Driver) Checkpoint(c *execdriver.Command, opts *execdriver.CheckpointOpts) error {
+	d.Lock()
+	active := d.activeContainers[c.ID]
+	d.Unlock()
+	if active == nil {
+		return fmt.Errorf("active container for %s does not exist", c.ID)
+	}
+	criuOpts := newCriuOpts(opts.Directory)
+	criuOpts.LeaveRunning = opts.LeaveRunning
+	return active.Checkpoint(criuOpts)
+}
+
+// newCriuOpts returns the criu options to checkpoint a container to, or to
+// restore it from, the directory dir. The logs of criu are kept along with
+// the images of the checkpoint.
+func newCriuOpts(dir string) *libcontainer.CriuOpts {
+	return &libcontainer.CriuOpts{
+		ImagesDirectory:         dir,
+		WorkDirectory:           dir,
+		TcpEstablished:          true,
+		ExternalUnixConnections: true,
+		FileLocks:               true,
+	}
+}
+
+// restoreCheckpoint restores the processes of the container cont from the
+// checkpoint in dir, in place of starting its init process p. The
+// prestart hooks of the container are not run by criu, they are run once the
+// processes are restored.
+func restoreCheckpoint(cont libcontainer.Container, p *libcontainer.Process, dir string) error {
+	if err := cont.Restore(p, newCriuOpts(dir)); err != nil {
+		return err
+	}
+	config := cont.Config()
+	if config.Hooks == nil {
+		return nil
+	}
+	pid, err := p.Pid()
+	if err != nil {
+		return err
+	}
+	s := configs.HookState{
+		Version: config.Version,
+		ID:      cont.ID(),
+		Pid:     pid,
+		Root:    config.Rootfs,
+	}
+	for _, hook := range config.Hooks.Prestart {
+		if err := hook.Run(s); err != nil {
+			p.Signal(os.Kill)
+			p.Wait()
+			return err
+		}
+	}
+	return nil
+}
@@ -45,6 +45,7 @@ type Driver struct {
 	activeContainers map[string]libcontainer.Container
 	machineMemory    int64
 	factory          libcontainer.Factory
+	systemdCgroups   bool
 	sync.Mutex
 }
 
@@ -77,6 +78,7 @@ func NewDriver(root string, options []string) (*Driver, error) {
 	// this makes sure there are no breaking changes to people
 	// who upgrade from versions without native.cgroupdriver opt
 	cgm := libcontainer.Cgroupfs
+	systemdCgroups := false
 
 	// parse the options
 	for _, option := range options {
@@ -92,12 +94,14 @@ func NewDriver(root string, options []string) (*Driver, error) {
 			case "systemd":
 				if systemd.UseSystemd() {
 					cgm = libcontainer.SystemdCgroups
+					systemdCgroups = true
 				} else {
 					// warn them that they chose the wrong driver
 					logrus.Warn("You cannot use systemd as native.cgroupdriver, using cgroupfs instead")
 				}
 			case "cgroupfs":
 				cgm = libcontainer.Cgroupfs
+				systemdCgroups = false
 			default:
 				return nil, fmt.Errorf("Unknown native.cgroupdriver given %q. try cgroupfs or systemd", val)
 			}
@@ -120,6 +124,7 @@ func NewDriver(root string, options []string) (*Driver, error) {
 		activeContainers: make(map[string]libcontainer.Container),
 		machineMemory:    meminfo.MemTotal,
 		factory:          f,
+		systemdCgroups:   systemdCgroups,
 	}, nil
 }
 
@@ -145,6 +150,12 @@ func (d *Driver) Run(c *execdriver.Command, pipes *execdriver.Pipes, hooks execd
 		return execdriver.ExitStatus{ExitCode: -1}, err
 	}
 
+	// A container which is kept running across restarts of the daemon is
+	// started by a shim.
+	if c.LiveRestore {
+		return d.startShim(c, container, pipes, hooks)
+	}
+
 	p := &libcontainer.Process{
 		Args: append([]string{c.ProcessConfig.Entrypoint}, c.ProcessConfig.Arguments...),
 		Env:  c.ProcessConfig.Env,
@@ -170,7 +181,12 @@ func (d *Driver) Run(c *execdriver.Command, pipes *execdriver.Pipes, hooks execd
 		d.cleanContainer(c.ID)
 	}()
 
-	if err := cont.Start(p); err != nil {
+	if c.CheckpointDir != "" {
+		err = restoreCheckpoint(cont, p, c.CheckpointDir)
+	} else {
+		err = cont.Start(p)
+	}
+	if err != nil {
 		return execdriver.ExitStatus{ExitCode: -1}, err
 	}
 
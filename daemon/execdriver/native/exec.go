@@ -7,7 +7,9 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"syscall"
+	"unsafe"
 
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/opencontainers/runc/libcontainer"
@@ -33,10 +35,15 @@ func (d *Driver) Exec(c *execdriver.Command, processConfig *execdriver.ProcessCo
 		user = "0"
 	}
 
+	cwd := processConfig.Dir
+	if cwd == "" {
+		cwd = c.WorkingDir
+	}
+
 	p := &libcontainer.Process{
 		Args: append([]string{processConfig.Entrypoint}, processConfig.Arguments...),
-		Env:  c.ProcessConfig.Env,
-		Cwd:  c.WorkingDir,
+		Env:  processConfig.Env,
+		Cwd:  cwd,
 		User: user,
 	}
 
@@ -60,14 +67,16 @@ func (d *Driver) Exec(c *execdriver.Command, processConfig *execdriver.ProcessCo
 		return -1, err
 	}
 
-	if hooks.Start != nil {
-		pid, err := p.Pid()
-		if err != nil {
-			p.Signal(os.Kill)
-			p.Wait()
-			return -1, err
-		}
+	pid, err := p.Pid()
+	if err != nil {
+		p.Signal(os.Kill)
+		p.Wait()
+		return -1, err
+	}
+	ep := &execProcess{process: p}
+	processConfig.Signal = ep.signal
 
+	if hooks.Start != nil {
 		// A closed channel for OOM is returned here as it will be
 		// non-blocking and return the correct result when read.
 		chOOM := make(chan struct{})
@@ -75,6 +84,7 @@ func (d *Driver) Exec(c *execdriver.Command, processConfig *execdriver.ProcessCo
 		hooks.Start(&c.ProcessConfig, pid, chOOM)
 	}
 
+	ep.wait(pid)
 	ps, err := p.Wait()
 	if err != nil {
 		exitErr, ok := err.(*exec.ExitError)
@@ -85,3 +95,42 @@ func (d *Driver) Exec(c *execdriver.Command, processConfig *execdriver.ProcessCo
 	}
 	return utils.ExitStatus(ps.Sys().(syscall.WaitStatus)), nil
 }
+
+// execProcess signals the process of an exec, until it has exited.
+type execProcess struct {
+	sync.Mutex
+	process *libcontainer.Process
+	exited  bool
+}
+
+// signal sends the signal sig to the process, or returns
+// execdriver.ErrProcessExited if it has exited.
+func (ep *execProcess) signal(sig os.Signal) error {
+	ep.Lock()
+	defer ep.Unlock()
+	if ep.exited {
+		return execdriver.ErrProcessExited
+	}
+	return ep.process.Signal(sig)
+}
+
+// wait waits for the process pid to exit and marks it as exited. The process
+// is left unreaped, so that its pid is not reused while it can be signaled.
+func (ep *execProcess) wait(pid int) {
+	var info [128]byte
+	for {
+		_, _, errno := syscall.Syscall6(syscall.SYS_WAITID, pWaitPid, uintptr(pid), uintptr(unsafe.Pointer(&info[0])), syscall.WEXITED|wNoWait, 0, 0)
+		if errno != syscall.EINTR {
+			break
+		}
+	}
+	ep.Lock()
+	ep.exited = true
+	ep.Unlock()
+}
+
+// Arguments of waitid(2), which are missing from the syscall package.
+const (
+	pWaitPid = 1
+	wNoWait  = 0x1000000
+)
@@ -0,0 +1,33 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/daemon/execdriver"
+)
+
+func TestExecProcessWaitLeavesProcessUnreaped(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ep := &execProcess{}
+	ep.wait(cmd.Process.Pid)
+	if err := ep.signal(os.Kill); err != execdriver.ErrProcessExited {
+		t.Fatalf("Expected %v signaling an exited process, got %v", execdriver.ErrProcessExited, err)
+	}
+
+	// The process is still a zombie, so it can be reaped
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if status := cmd.ProcessState.Sys().(syscall.WaitStatus); status.ExitStatus() != 0 {
+		t.Fatalf("Expected exit status 0, got %d", status.ExitStatus())
+	}
+}
@@ -0,0 +1,352 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// Restore implements the exec driver Driver interface,
+// it re-attaches to a container started by a previous instance of the daemon.
+func (d *Driver) Restore(c *execdriver.Command, pipes *execdriver.Pipes, hooks execdriver.Hooks) (execdriver.ExitStatus, error) {
+	dir := filepath.Join(d.root, c.ID)
+	exit, running, err := openExitFifo(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return execdriver.ExitStatus{ExitCode: -1}, fmt.Errorf("Container %s was not started for live restore", c.ID)
+		}
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	cont, err := d.factory.Load(c.ID)
+	if err != nil {
+		exit.Close()
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	if !running {
+		// The container exited while the daemon was down
+		exitCode, err := shimExitStatus(dir, exit)
+		if derr := cont.Destroy(); derr != nil {
+			logrus.Warnf("Failed to destroy container %s: %v", c.ID, derr)
+		}
+		d.cleanContainer(c.ID)
+		if err != nil {
+			return execdriver.ExitStatus{ExitCode: -1}, err
+		}
+		return execdriver.ExitStatus{ExitCode: exitCode}, execdriver.ErrNotRunning
+	}
+	state, err := cont.State()
+	if err != nil {
+		exit.Close()
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+
+	fifos, err := attachFifos(dir, &c.ProcessConfig, pipes)
+	if err != nil {
+		exit.Close()
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	return d.waitShim(c, cont, state.InitProcessPid, exit, fifos, hooks)
+}
+
+// startShim starts the container c, configured by container, with a shim
+// which stays the parent of its init process across restarts of the daemon.
+// The output of the container goes through named pipes in its state
+// directory, the daemon copies it to pipes while it runs.
+func (d *Driver) startShim(c *execdriver.Command, container *configs.Config, pipes *execdriver.Pipes, hooks execdriver.Hooks) (execdriver.ExitStatus, error) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	conn, child := os.NewFile(uintptr(fds[0]), "shim"), os.NewFile(uintptr(fds[1]), "shim")
+	defer conn.Close()
+
+	cmd := reexec.Command(shimName)
+	// The shim is not killed with the daemon
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	cmd.ExtraFiles = []*os.File{child}
+	err = cmd.Start()
+	child.Close()
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	go cmd.Wait()
+
+	dec, enc := json.NewDecoder(conn), json.NewEncoder(conn)
+	config := &shimConfig{
+		Root:           d.root,
+		SystemdCgroups: d.systemdCgroups,
+		ID:             c.ID,
+		Config:         container,
+		Prestart:       container.Hooks != nil && len(container.Hooks.Prestart) > 0,
+		Args:           append([]string{c.ProcessConfig.Entrypoint}, c.ProcessConfig.Arguments...),
+		Env:            c.ProcessConfig.Env,
+		Cwd:            c.WorkingDir,
+		User:           c.ProcessConfig.User,
+		Tty:            c.ProcessConfig.Tty,
+		Stdin:          pipes.Stdin != nil,
+		CheckpointDir:  c.CheckpointDir,
+	}
+	if err := enc.Encode(config); err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	pid, err := waitShimStart(container, c.ID, dec, enc)
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+
+	dir := filepath.Join(d.root, c.ID)
+	exit, _, err := openExitFifo(dir)
+	if err != nil {
+		syscall.Kill(pid, syscall.SIGKILL)
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	cont, err := d.factory.Load(c.ID)
+	if err != nil {
+		syscall.Kill(pid, syscall.SIGKILL)
+		shimExitStatus(dir, exit)
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	fifos, err := attachFifos(dir, &c.ProcessConfig, pipes)
+	if err != nil {
+		syscall.Kill(pid, syscall.SIGKILL)
+		shimExitStatus(dir, exit)
+		cont.Destroy()
+		d.cleanContainer(c.ID)
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+	// The shim copies the standard input once it is acknowledged that the
+	// daemon opened its end of the stdin fifo.
+	enc.Encode(&shimMessage{})
+	return d.waitShim(c, cont, pid, exit, fifos, hooks)
+}
+
+// waitShimStart runs the prestart hooks of container on behalf of the shim,
+// and returns the pid of the init process once it is started.
+func waitShimStart(container *configs.Config, id string, dec *json.Decoder, enc *json.Encoder) (int, error) {
+	for {
+		var m shimMessage
+		if err := dec.Decode(&m); err != nil {
+			return -1, fmt.Errorf("Failed to start the shim of container %s: %v", id, err)
+		}
+		switch m.Type {
+		case shimPrestart:
+			s := configs.HookState{
+				Version: container.Version,
+				ID:      id,
+				Pid:     m.Pid,
+				Root:    container.Rootfs,
+			}
+			var reply shimMessage
+			for _, hook := range container.Hooks.Prestart {
+				if err := hook.Run(s); err != nil {
+					reply.Error = err.Error()
+					break
+				}
+			}
+			if err := enc.Encode(&reply); err != nil {
+				return -1, err
+			}
+		case shimStarted:
+			return m.Pid, nil
+		default:
+			return -1, errors.New(m.Error)
+		}
+	}
+}
+
+// waitShim waits for the container c to exit, and destroys it. Its init
+// process with the given pid was started by a shim, which holds the exit fifo
+// open until it exits.
+func (d *Driver) waitShim(c *execdriver.Command, cont libcontainer.Container, pid int, exit *os.File, fifos *stdioFifos, hooks execdriver.Hooks) (execdriver.ExitStatus, error) {
+	d.Lock()
+	d.activeContainers[c.ID] = cont
+	d.Unlock()
+	defer d.cleanContainer(c.ID)
+
+	// 'oom' is used to emit 'oom' events to the eventstream, 'oomKilled' is used
+	// to set the 'OOMKilled' flag in state
+	oom := notifyOnOOM(cont)
+	oomKilled := notifyOnOOM(cont)
+	var hookErr error
+	if hooks.Start != nil {
+		if hookErr = hooks.Start(&c.ProcessConfig, pid, oom); hookErr != nil {
+			syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+
+	exitCode, err := shimExitStatus(filepath.Join(d.root, c.ID), exit)
+	if derr := cont.Destroy(); derr != nil {
+		logrus.Warnf("Failed to destroy container %s: %v", c.ID, derr)
+	}
+	fifos.wait()
+	if hookErr != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, hookErr
+	}
+	if err != nil {
+		return execdriver.ExitStatus{ExitCode: -1}, err
+	}
+
+	_, oomKill := <-oomKilled
+	return execdriver.ExitStatus{ExitCode: exitCode, OOMKilled: oomKill}, nil
+}
+
+// openExitFifo opens the read end of the exit fifo of the shim of the
+// container whose state directory is dir, and returns whether the shim is
+// still running, in which case the fifo has a writer.
+func openExitFifo(dir string) (*os.File, bool, error) {
+	// Opening the read end of a named pipe blocks until it has a writer,
+	// which is not the case anymore if the shim exited.
+	path := filepath.Join(dir, exitFifo)
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, false, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	var buf [1]byte
+	_, err = syscall.Read(fd, buf[:])
+	running := err == syscall.EAGAIN
+	if err := syscall.SetNonblock(fd, false); err != nil {
+		syscall.Close(fd)
+		return nil, false, err
+	}
+	return os.NewFile(uintptr(fd), path), running, nil
+}
+
+// shimExitStatus waits for the shim to exit, and returns the exit status of
+// the container it recorded.
+func shimExitStatus(dir string, exit *os.File) (int, error) {
+	defer exit.Close()
+	io.Copy(ioutil.Discard, exit)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, exitStatusFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return -1, fmt.Errorf("The shim of container %s exited without its exit status", filepath.Base(dir))
+		}
+		return -1, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// stdioFifos are the daemon ends of the named pipes, in the state directory
+// of a container, used for the standard streams of a container started by a
+// shim.
+type stdioFifos struct {
+	wg sync.WaitGroup
+}
+
+// attachFifos copies what is written to the stdout and stderr fifos of the
+// container in dir to the stdout and stderr of pipes, until the processes
+// holding them are gone, and the stdin of pipes to the stdin fifo. The
+// terminal of the container is resized through the control fifo.
+func attachFifos(dir string, processConfig *execdriver.ProcessConfig, pipes *execdriver.Pipes) (_ *stdioFifos, err error) {
+	f := &stdioFifos{}
+	term := &shimConsole{}
+	defer func() {
+		if err != nil {
+			term.Close()
+		}
+	}()
+	for _, s := range []struct {
+		name string
+		w    io.Writer
+	}{
+		{stdoutFifo, pipes.Stdout},
+		{stderrFifo, pipes.Stderr},
+	} {
+		r, err := os.OpenFile(filepath.Join(dir, s.name), os.O_RDONLY|syscall.O_NONBLOCK, 0)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// A terminal has no stderr
+				continue
+			}
+			return nil, err
+		}
+		term.Closers = append(term.Closers, r)
+		if err := syscall.SetNonblock(int(r.Fd()), false); err != nil {
+			return nil, err
+		}
+		if s.w == nil {
+			continue
+		}
+		f.wg.Add(1)
+		go func(w io.Writer) {
+			defer f.wg.Done()
+			io.Copy(w, r)
+		}(s.w)
+	}
+
+	if processConfig.Tty {
+		if term.control, err = openFifoWriter(filepath.Join(dir, controlFifo)); err != nil {
+			return nil, err
+		}
+		term.Closers = append(term.Closers, term.control)
+	}
+	if pipes.Stdin != nil {
+		w, err := openFifoWriter(filepath.Join(dir, stdinFifo))
+		if err != nil {
+			// The stdin of a container without a terminal is closed
+			// when the daemon exits, its fifo has no reader anymore.
+			if perr, ok := err.(*os.PathError); !ok || perr.Err != syscall.ENXIO {
+				return nil, err
+			}
+		} else {
+			go func() {
+				io.Copy(w, pipes.Stdin)
+				w.Close()
+			}()
+		}
+	}
+	processConfig.Terminal = term
+	return f, nil
+}
+
+// openFifoWriter opens the write end of the named pipe at path, which fails
+// with ENXIO if it has no reader.
+func openFifoWriter(path string) (*os.File, error) {
+	w, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.SetNonblock(int(w.Fd()), false); err != nil {
+		w.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// wait waits for the output of the container to be copied.
+func (f *stdioFifos) wait() {
+	f.wg.Wait()
+}
+
+// shimConsole implements the exec driver Terminal interface for a container
+// started by a shim, which holds its terminal if it has one.
+type shimConsole struct {
+	execdriver.StdConsole
+	control *os.File
+}
+
+// Resize implements Resize method of Terminal interface
+func (t *shimConsole) Resize(h, w int) error {
+	if t.control == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(t.control, "%d %d\n", h, w)
+	return err
+}
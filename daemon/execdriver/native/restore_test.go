@@ -0,0 +1,79 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestShimExitStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shim-exit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, _, err := openExitFifo(dir); !os.IsNotExist(err) {
+		t.Fatalf("Expected the exit fifo not to exist, got %v", err)
+	}
+
+	// The shim holds the exit fifo open until it exits
+	path := filepath.Join(dir, exitFifo)
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	shim, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exit, running, err := openExitFifo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !running {
+		t.Fatal("Expected the shim to be running")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, exitStatusFile), []byte("137"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	shim.Close()
+	exitCode, err := shimExitStatus(dir, exit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exitCode != 137 {
+		t.Fatalf("Expected exit code 137, got %d", exitCode)
+	}
+
+	exit, running, err = openExitFifo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if running {
+		t.Fatal("Expected the shim not to be running")
+	}
+	exit.Close()
+}
+
+func TestShimExitStatusMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shim-exit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := syscall.Mkfifo(filepath.Join(dir, exitFifo), 0600); err != nil {
+		t.Fatal(err)
+	}
+	exit, _, err := openExitFifo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := shimExitStatus(dir, exit); err == nil {
+		t.Fatal("Expected an error for a shim which exited without an exit status")
+	}
+}
@@ -0,0 +1,362 @@
+// +build linux,cgo
+
+package native
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/docker/docker/pkg/term"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/configs"
+	"github.com/opencontainers/runc/libcontainer/utils"
+)
+
+// shimName is the name the daemon is re-executed with to run the shim of a
+// container which is kept running across restarts of the daemon. The shim
+// starts the container and stays the parent of its init process, so that it
+// collects its exit status while the daemon is down.
+const shimName = "docker-native-shim"
+
+// The named pipes and files of the shim, in the state directory of the
+// container. The shim holds the exit fifo open until it exits, after writing
+// the exit status of the container in the exit status file.
+const (
+	stdinFifo      = "stdin"
+	stdoutFifo     = "stdout"
+	stderrFifo     = "stderr"
+	controlFifo    = "control"
+	exitFifo       = "exit"
+	exitStatusFile = "exitStatus"
+)
+
+// The types of the messages sent by the shim to the daemon while the
+// container starts.
+const (
+	shimPrestart = "prestart"
+	shimStarted  = "started"
+	shimError    = "error"
+)
+
+// shimConfig is sent by the daemon to the shim to start a container.
+type shimConfig struct {
+	Root           string
+	SystemdCgroups bool
+	ID             string
+	Config         *configs.Config
+	// Prestart is set if the daemon has prestart hooks to run, which
+	// the shim asks it to once the namespaces of the container exist.
+	Prestart      bool
+	Args          []string
+	Env           []string
+	Cwd           string
+	User          string
+	Tty           bool
+	Stdin         bool
+	CheckpointDir string
+}
+
+// shimMessage is exchanged by the daemon and the shim while the container
+// starts.
+type shimMessage struct {
+	Type  string `json:",omitempty"`
+	Pid   int    `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+func init() {
+	reexec.Register(shimName, shimMain)
+}
+
+// shimMain runs the shim of a container. The daemon talks to it on fd 3
+// until the container is started.
+func shimMain() {
+	conn := os.NewFile(3, "shim")
+	dec, enc := json.NewDecoder(conn), json.NewEncoder(conn)
+	var config shimConfig
+	if err := dec.Decode(&config); err != nil {
+		fatal(err)
+	}
+	status, err := runShim(&config, conn, dec, enc)
+	if err != nil {
+		enc.Encode(&shimMessage{Type: shimError, Error: err.Error()})
+		os.Exit(1)
+	}
+	dir := filepath.Join(config.Root, config.ID)
+	if err := ioutil.WriteFile(filepath.Join(dir, exitStatusFile), []byte(fmt.Sprint(status)), 0600); err != nil {
+		fatal(err)
+	}
+	os.Exit(0)
+}
+
+// runShim starts the container of config and returns the exit status of its
+// init process. The daemon is told the pid of the process once it is
+// started, and the daemon is not needed anymore once it acknowledges it.
+func runShim(config *shimConfig, conn io.Closer, dec *json.Decoder, enc *json.Encoder) (int, error) {
+	cgm := libcontainer.Cgroupfs
+	if config.SystemdCgroups {
+		cgm = libcontainer.SystemdCgroups
+	}
+	factory, err := libcontainer.New(config.Root, cgm, libcontainer.InitPath(reexec.Self(), DriverName))
+	if err != nil {
+		return -1, err
+	}
+	container := config.Config
+	if config.Prestart {
+		container.Hooks = &configs.Hooks{
+			Prestart: []configs.Hook{
+				configs.NewFunctionHook(func(s configs.HookState) error {
+					if err := enc.Encode(&shimMessage{Type: shimPrestart, Pid: s.Pid}); err != nil {
+						return err
+					}
+					var reply shimMessage
+					if err := dec.Decode(&reply); err != nil {
+						return err
+					}
+					if reply.Error != "" {
+						return errors.New(reply.Error)
+					}
+					return nil
+				}),
+			},
+		}
+	}
+	cont, err := factory.Create(config.ID, container)
+	if err != nil {
+		return -1, err
+	}
+
+	p := &libcontainer.Process{
+		Args: config.Args,
+		Env:  config.Env,
+		Cwd:  config.Cwd,
+		User: config.User,
+	}
+	stdio, err := newShimStdio(filepath.Join(config.Root, config.ID), container, p, config.Tty, config.Stdin)
+	if err != nil {
+		cont.Destroy()
+		return -1, err
+	}
+	if config.CheckpointDir != "" {
+		err = restoreCheckpoint(cont, p, config.CheckpointDir)
+	} else {
+		err = cont.Start(p)
+	}
+	stdio.closeProcessEnds()
+	if err != nil {
+		cont.Destroy()
+		return -1, err
+	}
+	pid, err := p.Pid()
+	if err != nil {
+		p.Signal(os.Kill)
+		p.Wait()
+		cont.Destroy()
+		return -1, err
+	}
+
+	// The standard input is copied once the daemon opened its end of the
+	// named pipe, or when it is gone.
+	if err := enc.Encode(&shimMessage{Type: shimStarted, Pid: pid}); err == nil {
+		var ack shimMessage
+		dec.Decode(&ack)
+	}
+	conn.Close()
+	stdio.copy()
+
+	waitF := p.Wait
+	if nss := container.Namespaces; !nss.Contains(configs.NEWPID) {
+		waitF = waitInPIDHost(p, cont)
+	}
+	ps, err := waitF()
+	if err != nil {
+		execErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return -1, err
+		}
+		ps = execErr.ProcessState
+	}
+	stdio.wait()
+	return utils.ExitStatus(ps.Sys().(syscall.WaitStatus)), nil
+}
+
+// shimStdio are the ends the shim holds of the named pipes of a container.
+type shimStdio struct {
+	// exit is held open until the shim exits
+	exit *os.File
+	// processEnds are the ends given to the process of the container,
+	// which the shim closes once it is started
+	processEnds []*os.File
+	// stdin is the read end of the stdin fifo, copied to stdinWriter
+	stdin       *os.File
+	stdinWriter io.WriteCloser
+	tty         bool
+	console     libcontainer.Console
+	stdout      *os.File
+	control     *os.File
+	wg          sync.WaitGroup
+}
+
+// newShimStdio creates the named pipes of a container in dir and connects
+// the process p to them. The output of the process is written to named pipes
+// opened for both reading and writing: the writes never fail once the daemon
+// is gone, they block when a pipe is full until the daemon reads from it
+// again. A terminal is kept by the shim, its output goes to the stdout pipe
+// and it is resized through the control pipe.
+func newShimStdio(dir string, container *configs.Config, p *libcontainer.Process, tty, stdin bool) (_ *shimStdio, err error) {
+	rootuid, err := container.HostUID()
+	if err != nil {
+		return nil, err
+	}
+	rootgid, err := container.HostGID()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &shimStdio{tty: tty}
+	defer func() {
+		if err != nil {
+			s.close()
+		}
+	}()
+	open := func(name string, flag int) (*os.File, error) {
+		path := filepath.Join(dir, name)
+		if err := syscall.Mkfifo(path, 0600); err != nil && !os.IsExist(err) {
+			return nil, fmt.Errorf("Failed to create %s fifo: %v", name, err)
+		}
+		if err := os.Chown(path, rootuid, rootgid); err != nil {
+			return nil, err
+		}
+		return os.OpenFile(path, flag, 0)
+	}
+
+	if s.exit, err = open(exitFifo, os.O_RDWR); err != nil {
+		return nil, err
+	}
+
+	if tty {
+		if s.console, err = p.NewConsole(rootuid); err != nil {
+			return nil, err
+		}
+		if s.stdout, err = open(stdoutFifo, os.O_RDWR); err != nil {
+			return nil, err
+		}
+		if s.control, err = open(controlFifo, os.O_RDWR); err != nil {
+			return nil, err
+		}
+		// The terminal keeps its input across restarts of the daemon,
+		// the stdin fifo is never at its end.
+		if stdin {
+			if s.stdin, err = open(stdinFifo, os.O_RDWR); err != nil {
+				return nil, err
+			}
+			s.stdinWriter = s.console
+		}
+		return s, nil
+	}
+
+	for _, name := range []string{stdoutFifo, stderrFifo} {
+		f, err := open(name, os.O_RDWR)
+		if err != nil {
+			return nil, err
+		}
+		s.processEnds = append(s.processEnds, f)
+	}
+	p.Stdout = s.processEnds[0]
+	p.Stderr = s.processEnds[1]
+
+	// The standard input of the process is closed when the daemon closes
+	// its end of the stdin fifo, or exits.
+	if stdin {
+		if s.stdin, err = open(stdinFifo, os.O_RDONLY|syscall.O_NONBLOCK); err != nil {
+			return nil, err
+		}
+		if err := syscall.SetNonblock(int(s.stdin.Fd()), false); err != nil {
+			return nil, err
+		}
+		r, w, err := os.Pipe()
+		if err != nil {
+			return nil, err
+		}
+		s.processEnds = append(s.processEnds, r)
+		s.stdinWriter = w
+		if err := syscall.Fchown(int(r.Fd()), rootuid, rootgid); err != nil {
+			return nil, fmt.Errorf("Failed to chown pipes fd: %v", err)
+		}
+		p.Stdin = r
+	}
+	return s, nil
+}
+
+// closeProcessEnds closes the ends of the pipes given to the process, once
+// it is started.
+func (s *shimStdio) closeProcessEnds() {
+	for _, f := range s.processEnds {
+		f.Close()
+	}
+	s.processEnds = nil
+}
+
+// copy copies the standard input to the process, and the output and the size
+// of its terminal if it has one.
+func (s *shimStdio) copy() {
+	if s.stdin != nil {
+		go func() {
+			io.Copy(s.stdinWriter, s.stdin)
+			if !s.tty {
+				s.stdinWriter.Close()
+			}
+		}()
+	}
+	if !s.tty {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		io.Copy(s.stdout, s.console)
+	}()
+	go func() {
+		scanner := bufio.NewScanner(s.control)
+		for scanner.Scan() {
+			var h, w uint16
+			if _, err := fmt.Sscanf(scanner.Text(), "%d %d", &h, &w); err != nil {
+				continue
+			}
+			term.SetWinsize(s.console.Fd(), &term.Winsize{Height: h, Width: w})
+		}
+	}()
+}
+
+// wait waits for the output of the terminal to be copied, once the process
+// exited.
+func (s *shimStdio) wait() {
+	s.wg.Wait()
+}
+
+// close closes the ends of the pipes held by the shim, when the container
+// cannot be started.
+func (s *shimStdio) close() {
+	s.closeProcessEnds()
+	for _, f := range []*os.File{s.exit, s.stdin, s.stdout, s.control} {
+		if f != nil {
+			f.Close()
+		}
+	}
+	if s.console != nil {
+		s.console.Close()
+	}
+	if !s.tty && s.stdinWriter != nil {
+		s.stdinWriter.Close()
+	}
+}
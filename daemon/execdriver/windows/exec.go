@@ -29,6 +29,9 @@ func (d *Driver) Exec(c *execdriver.Command, processConfig *execdriver.ProcessCo
 		EmulateConsole:   processConfig.Tty, // Note NOT c.ProcessConfig.Tty
 		WorkingDirectory: c.WorkingDir,
 	}
+	if processConfig.Dir != "" {
+		createProcessParms.WorkingDirectory = processConfig.Dir
+	}
 
 	// Configure the environment for the process // Note NOT c.ProcessConfig.Env
 	createProcessParms.Environment = setupEnvironmentVariables(processConfig.Env)
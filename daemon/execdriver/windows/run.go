@@ -86,6 +86,9 @@ const defaultOwner = "docker"
 
 // Run implements the exec driver Driver interface
 func (d *Driver) Run(c *execdriver.Command, pipes *execdriver.Pipes, hooks execdriver.Hooks) (execdriver.ExitStatus, error) {
+	if c.CheckpointDir != "" {
+		return execdriver.ExitStatus{ExitCode: -1}, execdriver.ErrCheckpointNotSupported
+	}
 
 	var (
 		term execdriver.Terminal
@@ -328,6 +331,18 @@ func (d *Driver) Run(c *execdriver.Command, pipes *execdriver.Pipes, hooks execd
 	return execdriver.ExitStatus{ExitCode: int(exitCode)}, nil
 }
 
+// Restore implements the exec driver Driver interface.
+// The windows driver does not support restoring containers
+func (d *Driver) Restore(c *execdriver.Command, pipes *execdriver.Pipes, hooks execdriver.Hooks) (execdriver.ExitStatus, error) {
+	return execdriver.ExitStatus{ExitCode: -1}, execdriver.ErrRestoreNotSupported
+}
+
+// Checkpoint implements the exec driver Driver interface.
+// The windows driver does not support checkpointing containers
+func (d *Driver) Checkpoint(c *execdriver.Command, opts *execdriver.CheckpointOpts) error {
+	return execdriver.ErrCheckpointNotSupported
+}
+
 // SupportsHooks implements the execdriver Driver interface.
 // The windows driver does not support the hook mechanism
 func (d *Driver) SupportsHooks() bool {
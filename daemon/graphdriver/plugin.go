@@ -23,10 +23,12 @@ func lookupPlugin(name, home string, opts []string) (Driver, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Error looking up graphdriver plugin %s: %v", name, err)
 	}
-	return newPluginDriver(name, home, opts, pl.Client)
+	return newPluginDriver(name, home, opts, pl.BasePath, pl.Client)
 }
 
-func newPluginDriver(name, home string, opts []string, c pluginClient) (Driver, error) {
-	proxy := &graphDriverProxy{name, c}
+// newPluginDriver returns a driver calling the plugin over c. The directories
+// returned by the plugin are relative to basePath, if set.
+func newPluginDriver(name, home string, opts []string, basePath string, c pluginClient) (Driver, error) {
+	proxy := &graphDriverProxy{name, basePath, c}
 	return proxy, proxy.Init(home, opts)
 }
@@ -5,13 +5,15 @@ package graphdriver
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/docker/docker/pkg/archive"
 )
 
 type graphDriverProxy struct {
-	name   string
-	client pluginClient
+	name     string
+	basePath string
+	client   pluginClient
 }
 
 type graphDriverRequest struct {
@@ -95,6 +97,9 @@ func (d *graphDriverProxy) Get(id, mountLabel string) (string, error) {
 	if ret.Err != "" {
 		err = errors.New(ret.Err)
 	}
+	if d.basePath != "" && ret.Dir != "" {
+		ret.Dir = filepath.Join(d.basePath, ret.Dir)
+	}
 	return ret.Dir, err
 }
 
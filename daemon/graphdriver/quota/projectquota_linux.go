@@ -0,0 +1,297 @@
+// +build linux
+
+package quota
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	xfsSuperMagic = 0x58465342
+
+	// quotactl commands of the XFS quota interface, see linux/dqblk_xfs.h
+	qXGetQuota = 'X'<<8 + 3
+	qXSetQLim  = 'X'<<8 + 4
+	prjQuota   = 2
+
+	fsDquotVersion = 1
+	fsProjQuota    = 2
+	fsDqBSoft      = 1 << 2
+	fsDqBHard      = 1 << 3
+
+	// ioctls reading and writing the fsxattr of a file, see linux/fs.h
+	fsIocFsGetXattr    = 0x801c581f
+	fsIocFsSetXattr    = 0x401c5820
+	fsXflagProjInherit = 0x200
+
+	// quota limits and usage are counted in blocks of 512 bytes
+	quotaBlockSize = 512
+
+	backingFsBlockDevName = "backingFsBlockDev"
+)
+
+// fsDiskQuota is the fs_disk_quota structure of linux/dqblk_xfs.h.
+type fsDiskQuota struct {
+	Version      int8
+	Flags        int8
+	FieldMask    uint16
+	ID           uint32
+	BlkHardLimit uint64
+	BlkSoftLimit uint64
+	InoHardLimit uint64
+	InoSoftLimit uint64
+	BCount       uint64
+	ICount       uint64
+	ITimer       int32
+	BTimer       int32
+	IWarns       uint16
+	BWarns       uint16
+	Padding2     int32
+	RtbHardLimit uint64
+	RtbSoftLimit uint64
+	RtbCount     uint64
+	RtbTimer     int32
+	RtbWarns     uint16
+	Padding3     int16
+	Padding4     [8]byte
+}
+
+// fsXattr is the fsxattr structure of linux/fs.h.
+type fsXattr struct {
+	XFlags     uint32
+	ExtSize    uint32
+	NExtents   uint32
+	ProjID     uint32
+	CowExtSize uint32
+	Pad        [8]byte
+}
+
+// Control sets and reads the project quotas of the directories of a base
+// directory.
+type Control struct {
+	sync.Mutex
+	backingFsBlockDev string
+	nextProjectID     uint32
+	quotas            map[string]uint32
+}
+
+// NewControl returns a Control for the directories of basePath. It returns
+// ErrQuotaNotSupported if basePath is not on an XFS filesystem with project
+// quotas enabled.
+//
+// The directories are assigned the project IDs following the project ID of
+// basePath. Different project IDs must be set on the base directories of the
+// users of project quotas sharing a filesystem, so that their projects do
+// not overlap.
+func NewControl(basePath string) (*Control, error) {
+	var buf syscall.Statfs_t
+	if err := syscall.Statfs(basePath, &buf); err != nil {
+		return nil, err
+	}
+	if buf.Type != xfsSuperMagic {
+		return nil, ErrQuotaNotSupported
+	}
+
+	minProjectID, err := getProjectID(basePath)
+	if err != nil {
+		return nil, err
+	}
+	minProjectID++
+
+	backingFsBlockDev, err := makeBackingFsDev(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the first project ID is reserved to check that quotas are enabled, by
+	// setting an empty quota
+	if err := setProjectQuota(backingFsBlockDev, minProjectID, Quota{}); err != nil {
+		logrus.Debugf("Error setting a project quota on %s: %v", basePath, err)
+		return nil, ErrQuotaNotSupported
+	}
+
+	q := &Control{
+		backingFsBlockDev: backingFsBlockDev,
+		nextProjectID:     minProjectID + 1,
+		quotas:            make(map[string]uint32),
+	}
+	if err := q.findNextProjectID(basePath); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// SetQuota sets the quota of the directory targetPath, a child of the base
+// directory of the control. A new project ID is assigned to the directory
+// the first time its quota is set.
+func (q *Control) SetQuota(targetPath string, quota Quota) error {
+	q.Lock()
+	defer q.Unlock()
+
+	projectID, ok := q.quotas[targetPath]
+	if !ok {
+		projectID = q.nextProjectID
+		if err := setProjectID(targetPath, projectID); err != nil {
+			return err
+		}
+		q.quotas[targetPath] = projectID
+		q.nextProjectID++
+	}
+
+	logrus.Debugf("Setting the quota of %s (project ID %d) to %d bytes", targetPath, projectID, quota.Size)
+	return setProjectQuota(q.backingFsBlockDev, projectID, quota)
+}
+
+// GetQuota reads the quota of the directory targetPath, and its usage.
+func (q *Control) GetQuota(targetPath string, quota *Quota) error {
+	q.Lock()
+	projectID, ok := q.quotas[targetPath]
+	q.Unlock()
+	if !ok {
+		return fmt.Errorf("no quota set on %s", targetPath)
+	}
+
+	var d fsDiskQuota
+	if err := quotactl(qXGetQuota, q.backingFsBlockDev, projectID, &d); err != nil {
+		return fmt.Errorf("failed to get the quota of %s: %v", targetPath, err)
+	}
+	quota.Size = d.BlkHardLimit * quotaBlockSize
+	quota.Used = d.BCount * quotaBlockSize
+	return nil
+}
+
+// setProjectQuota sets the block limits of the project projectID.
+func setProjectQuota(backingFsBlockDev string, projectID uint32, quota Quota) error {
+	d := fsDiskQuota{
+		Version:      fsDquotVersion,
+		Flags:        fsProjQuota,
+		FieldMask:    fsDqBHard | fsDqBSoft,
+		ID:           projectID,
+		BlkHardLimit: quota.Size / quotaBlockSize,
+		BlkSoftLimit: quota.Size / quotaBlockSize,
+	}
+	if err := quotactl(qXSetQLim, backingFsBlockDev, projectID, &d); err != nil {
+		return fmt.Errorf("failed to set the quota of project ID %d: %v", projectID, err)
+	}
+	return nil
+}
+
+func quotactl(cmd int, special string, id uint32, d *fsDiskQuota) error {
+	specialPtr, err := syscall.BytePtrFromString(special)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall6(syscall.SYS_QUOTACTL, uintptr(cmd<<8|prjQuota), uintptr(unsafe.Pointer(specialPtr)), uintptr(id), uintptr(unsafe.Pointer(d)), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// getProjectID returns the project ID of the file at path.
+func getProjectID(path string) (uint32, error) {
+	attr, err := getXattr(path)
+	if err != nil {
+		return 0, err
+	}
+	return attr.ProjID, nil
+}
+
+// setProjectID assigns the project ID projectID to the directory at path,
+// to be inherited by the files created in it.
+func setProjectID(path string, projectID uint32) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var attr fsXattr
+	if err := ioctl(f, fsIocFsGetXattr, &attr); err != nil {
+		return fmt.Errorf("failed to get the project ID of %s: %v", path, err)
+	}
+	attr.ProjID = projectID
+	attr.XFlags |= fsXflagProjInherit
+	if err := ioctl(f, fsIocFsSetXattr, &attr); err != nil {
+		return fmt.Errorf("failed to set the project ID of %s: %v", path, err)
+	}
+	return nil
+}
+
+func getXattr(path string) (*fsXattr, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var attr fsXattr
+	if err := ioctl(f, fsIocFsGetXattr, &attr); err != nil {
+		return nil, fmt.Errorf("failed to get the project ID of %s: %v", path, err)
+	}
+	return &attr, nil
+}
+
+func ioctl(f *os.File, req uintptr, attr *fsXattr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(attr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// findNextProjectID records the project IDs of the directories of home, and
+// sets the next project ID after the highest one.
+func (q *Control) findNextProjectID(home string) error {
+	files, err := ioutil.ReadDir(home)
+	if err != nil {
+		return err
+	}
+	minProjectID := q.nextProjectID
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		path := filepath.Join(home, file.Name())
+		projectID, err := getProjectID(path)
+		if err != nil {
+			return err
+		}
+		if projectID < minProjectID {
+			// the directory inherited the project ID of home, or has
+			// another one set by the administrator
+			continue
+		}
+		q.quotas[path] = projectID
+		if q.nextProjectID <= projectID {
+			q.nextProjectID = projectID + 1
+		}
+	}
+	return nil
+}
+
+// makeBackingFsDev creates a block device node for the device of home, as
+// quotactl expects the path of the block device of the filesystem.
+func makeBackingFsDev(home string) (string, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(home, &stat); err != nil {
+		return "", err
+	}
+
+	backingFsBlockDev := filepath.Join(home, backingFsBlockDevName)
+	// re-create the node, in case home was copied to another device
+	if err := syscall.Unlink(backingFsBlockDev); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := syscall.Mknod(backingFsBlockDev, syscall.S_IFBLK|0600, int(stat.Dev)); err != nil {
+		return "", fmt.Errorf("failed to mknod %s: %v", backingFsBlockDev, err)
+	}
+	return backingFsBlockDev, nil
+}
@@ -0,0 +1,113 @@
+// +build linux
+
+package quota
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+func TestStructSizes(t *testing.T) {
+	if size := unsafe.Sizeof(fsDiskQuota{}); size != 112 {
+		t.Fatalf("Expected the size of fs_disk_quota to be 112, got %d", size)
+	}
+	if size := unsafe.Sizeof(fsXattr{}); size != 28 {
+		t.Fatalf("Expected the size of fsxattr to be 28, got %d", size)
+	}
+}
+
+func TestNewControlUnsupported(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quota-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var buf syscall.Statfs_t
+	if err := syscall.Statfs(dir, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Type == xfsSuperMagic {
+		t.Skip("the temporary directory is on xfs")
+	}
+	if _, err := NewControl(dir); err != ErrQuotaNotSupported {
+		t.Fatalf("Expected ErrQuotaNotSupported, got %v", err)
+	}
+}
+
+// TestQuota sets a quota on a loopback xfs filesystem mounted with project
+// quotas.
+func TestQuota(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("root required")
+	}
+	mkfs, err := exec.LookPath("mkfs.xfs")
+	if err != nil {
+		t.Skip("mkfs.xfs not found")
+	}
+
+	dir, err := ioutil.TempDir("", "quota-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	image := filepath.Join(dir, "xfs.img")
+	f, err := os.Create(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(320 << 20); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if out, err := exec.Command(mkfs, image).CombinedOutput(); err != nil {
+		t.Skipf("mkfs.xfs failed: %v: %s", err, out)
+	}
+
+	home := filepath.Join(dir, "home")
+	if err := os.Mkdir(home, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("mount", "-o", "loop,pquota", image, home).CombinedOutput(); err != nil {
+		t.Skipf("mount of the xfs filesystem failed: %v: %s", err, out)
+	}
+	defer exec.Command("umount", home).Run()
+
+	ctl, err := NewControl(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := filepath.Join(home, "target")
+	if err := os.Mkdir(target, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.SetQuota(target, Quota{Size: 1 << 20}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(target, "small"), make([]byte, 512<<10), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(target, "big"), make([]byte, 1<<20), 0600); err == nil {
+		t.Fatal("Expected an error writing over the quota")
+	}
+
+	// the quota is found again by a new control
+	ctl, err = NewControl(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var q Quota
+	if err := ctl.GetQuota(target, &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.Size != 1<<20 || q.Used < 512<<10 {
+		t.Fatalf("Unexpected quota %+v", q)
+	}
+}
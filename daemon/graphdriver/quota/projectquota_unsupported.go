@@ -0,0 +1,23 @@
+// +build !linux
+
+package quota
+
+// Control sets and reads the project quotas of the directories of a base
+// directory. Project quotas are not supported on this platform.
+type Control struct{}
+
+// NewControl returns ErrQuotaNotSupported, as project quotas are not
+// supported on this platform.
+func NewControl(basePath string) (*Control, error) {
+	return nil, ErrQuotaNotSupported
+}
+
+// SetQuota returns ErrQuotaNotSupported.
+func (q *Control) SetQuota(targetPath string, quota Quota) error {
+	return ErrQuotaNotSupported
+}
+
+// GetQuota returns ErrQuotaNotSupported.
+func (q *Control) GetQuota(targetPath string, quota *Quota) error {
+	return ErrQuotaNotSupported
+}
@@ -0,0 +1,22 @@
+// Package quota sets and reads the project quotas of the directories of a
+// filesystem, which limit the disk usage of the directory trees.
+//
+// A directory is assigned a project ID, inherited by the files and the
+// directories created in it, and the blocks of the project are limited.
+// Project quotas are only supported on XFS filesystems mounted with the
+// pquota or prjquota option.
+package quota
+
+import "errors"
+
+// ErrQuotaNotSupported is returned when the filesystem does not support, or
+// has not enabled, project quotas.
+var ErrQuotaNotSupported = errors.New("filesystem does not support, or has not enabled, project quotas")
+
+// Quota is the size limit of a directory tree, and its usage.
+type Quota struct {
+	// Size is the limit, in bytes.
+	Size uint64
+	// Used is the disk usage, in bytes. It is only set by GetQuota.
+	Used uint64
+}
@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+	eventtypes "github.com/docker/engine-api/types/events"
+)
+
+func reset(c *container.Container) {
+	c.State = &container.State{}
+	c.State.Health = &container.Health{}
+	c.State.Health.Status = types.Starting
+}
+
+func TestHealthStates(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	expect := func(expected string) {
+		select {
+		case event := <-l:
+			ev := event.(eventtypes.Message)
+			if ev.Status != expected {
+				t.Errorf("Expecting event %#v, but got %#v\n", expected, ev.Status)
+			}
+		case <-time.After(1 * time.Second):
+			t.Errorf("Expecting event %#v, but got nothing\n", expected)
+		}
+	}
+
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:   "container_id",
+			Name: "container_name",
+			Config: &containertypes.Config{
+				Image: "image_name",
+				Healthcheck: &containertypes.HealthConfig{
+					Test:    []string{"CMD", "true"},
+					Retries: 1,
+				},
+			},
+		},
+	}
+	daemon := &Daemon{
+		EventsService: e,
+	}
+	stop := make(chan struct{})
+
+	handleResult := func(startTime time.Time, exitCode int) {
+		handleProbeResult(daemon, c, stop, &types.HealthcheckResult{
+			Start:    startTime,
+			End:      startTime,
+			ExitCode: exitCode,
+		})
+	}
+
+	// starting -> failed -> success -> failed
+
+	reset(c)
+	handleResult(c.State.StartedAt.Add(1*time.Second), 1)
+	expect("health_status: unhealthy")
+
+	handleResult(c.State.StartedAt.Add(2*time.Second), 0)
+	expect("health_status: healthy")
+
+	handleResult(c.State.StartedAt.Add(3*time.Second), 1)
+	expect("health_status: unhealthy")
+
+	// starting -> starting -> starting ->
+	// healthy -> starting (invalid transition)
+
+	reset(c)
+	handleResult(c.State.StartedAt.Add(20*time.Second), 2)
+	handleResult(c.State.StartedAt.Add(40*time.Second), 2)
+	if c.State.Health.Status != types.Starting {
+		t.Errorf("Expecting starting, but got %#v\n", c.State.Health.Status)
+	}
+
+	handleResult(c.State.StartedAt.Add(50*time.Second), 0)
+	expect("health_status: healthy")
+	handleResult(c.State.StartedAt.Add(60*time.Second), 2)
+	expect("health_status: unhealthy")
+
+	// Results received after the monitor was stopped are discarded.
+	reset(c)
+	close(stop)
+	handleResult(c.State.StartedAt.Add(70*time.Second), 0)
+	if c.State.Health.Status != types.Starting || len(c.State.Health.Log) != 0 {
+		t.Errorf("Expecting result to be discarded, but got %#v\n", c.State.Health)
+	}
+}
+
+func TestHealthMonitorHooks(t *testing.T) {
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:    "container_id",
+			State: container.NewState(),
+			Config: &containertypes.Config{
+				Healthcheck: &containertypes.HealthConfig{
+					Test: []string{"CMD", "true"},
+				},
+			},
+		},
+	}
+	daemon := &Daemon{}
+
+	start, stop := daemon.withHealthMonitor(c, func(*execdriver.ProcessConfig, int, <-chan struct{}) error {
+		c.SetRunning(1)
+		return nil
+	})
+
+	// The container is still locked when it fails to start.
+	c.Lock()
+	stop()
+	c.Unlock()
+
+	if err := start(&execdriver.ProcessConfig{}, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	c.Lock()
+	if c.State.Health == nil || c.State.Health.Status != types.Starting {
+		t.Fatalf("Expected the health monitor to be started, got %v", c.State.Health)
+	}
+	if c.State.Health.String() == "no healthcheck" {
+		t.Fatal("Expected the health monitor to be running")
+	}
+	c.Unlock()
+
+	stop()
+	c.Lock()
+	defer c.Unlock()
+	if c.State.Health.String() != "no healthcheck" {
+		t.Fatal("Expected the health monitor to be stopped")
+	}
+}
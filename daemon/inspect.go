@@ -118,6 +118,15 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 		hostConfig.LogConfig.Config = daemon.defaultLogConfig.Config
 	}
 
+	var containerHealth *types.Health
+	if container.State.Health != nil {
+		containerHealth = &types.Health{
+			Status:        container.State.Health.Status,
+			FailingStreak: container.State.Health.FailingStreak,
+			Log:           append([]*types.HealthcheckResult{}, container.State.Health.Log...),
+		}
+	}
+
 	containerState := &types.ContainerState{
 		Status:     container.State.StateString(),
 		Running:    container.State.Running,
@@ -130,6 +139,7 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 		Error:      container.State.Error,
 		StartedAt:  container.State.StartedAt.Format(time.RFC3339Nano),
 		FinishedAt: container.State.FinishedAt.Format(time.RFC3339Nano),
+		Health:     containerHealth,
 	}
 
 	contJSONBase := &types.ContainerJSONBase{
@@ -174,10 +184,15 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 }
 
 // ContainerExecInspect returns low-level information about the exec
-// command. An error is returned if the exec cannot be found.
+// command. A finished exec is looked up in the history of the containers,
+// even if its container is not running anymore. An error is returned if the
+// exec cannot be found.
 func (daemon *Daemon) ContainerExecInspect(id string) (*exec.Config, error) {
 	eConfig, err := daemon.getExecConfig(id)
 	if err != nil {
+		if eConfig := daemon.getExecFromHistory(id); eConfig != nil {
+			return eConfig, nil
+		}
 		return nil, err
 	}
 	return eConfig, nil
@@ -190,7 +205,9 @@ func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
 	if err != nil {
 		return nil, err
 	}
-	return volumeToAPIType(v), nil
+	apiV := volumeToAPIType(v)
+	apiV.Status = v.Status()
+	return apiV, nil
 }
 
 func (daemon *Daemon) getBackwardsCompatibleNetworkSettings(settings *network.Settings) *v1p20.NetworkSettings {
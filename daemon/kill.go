@@ -17,6 +17,8 @@ import (
 // for the container to exit.
 // If a signal is given, then just send it to the container and return.
 func (daemon *Daemon) ContainerKill(name string, sig uint64) error {
+	defer containerActions.ObserveSince(time.Now(), "kill")
+
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
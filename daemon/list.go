@@ -9,6 +9,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/volume"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
 	networktypes "github.com/docker/engine-api/types/network"
@@ -17,6 +18,9 @@ import (
 
 var acceptedVolumeFilterTags = map[string]bool{
 	"dangling": true,
+	"driver":   true,
+	"label":    true,
+	"name":     true,
 }
 
 // iterationAction represents possible outcomes happening during the container iteration.
@@ -164,6 +168,17 @@ func (daemon *Daemon) foldFilter(config *ContainersConfig) (*listContext, error)
 		return nil, err
 	}
 
+	err = psFilters.WalkValues("health", func(value string) error {
+		if !container.IsValidHealthString(value) {
+			return fmt.Errorf("Unrecognised filter value for health: %s", value)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var beforeContFilter, sinceContFilter *container.Container
 	err = psFilters.WalkValues("before", func(value string) error {
 		beforeContFilter, err = daemon.GetContainer(value)
@@ -295,6 +310,11 @@ func includeContainerInList(container *container.Container, ctx *listContext) it
 		return excludeContainer
 	}
 
+	// Do not include container if its health doesn't match the filter
+	if !ctx.filters.ExactMatch("health", container.State.HealthString()) {
+		return excludeContainer
+	}
+
 	if ctx.ancestorFilter {
 		if len(ctx.images) == 0 {
 			return excludeContainer
@@ -347,6 +367,7 @@ func (daemon *Daemon) transformContainer(container *container.Container, ctx *li
 		newC.Command = container.Path
 	}
 	newC.Created = container.Created.Unix()
+	newC.State = container.State.StateString()
 	newC.Status = container.State.String()
 	newC.HostConfig.NetworkMode = string(container.HostConfig.NetworkMode)
 	// copy networks to avoid races
@@ -442,12 +463,35 @@ func (daemon *Daemon) Volumes(filter string) ([]*types.Volume, []string, error)
 	if volFilters.Include("dangling") {
 		volumes = daemon.volumes.FilterByUsed(volumes, !danglingOnly)
 	}
+	volumes = filterVolumes(volumes, volFilters)
 	for _, v := range volumes {
 		volumesOut = append(volumesOut, volumeToAPIType(v))
 	}
 	return volumesOut, warnings, nil
 }
 
+// filterVolumes returns the volumes matching the name, driver and label
+// filters.
+func filterVolumes(vols []volume.Volume, volFilters filters.Args) []volume.Volume {
+	var out []volume.Volume
+	for _, v := range vols {
+		if volFilters.Include("name") && !volFilters.Match("name", v.Name()) {
+			continue
+		}
+		if volFilters.Include("driver") && !volFilters.Match("driver", v.DriverName()) {
+			continue
+		}
+		if volFilters.Include("label") {
+			lv, ok := v.(volume.LabeledVolume)
+			if !ok || !volFilters.MatchKVList("label", lv.Labels()) {
+				continue
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
 func populateImageFilterByParents(ancestorMap map[image.ID]bool, imageID image.ID, getChildren func(image.ID) []image.ID) {
 	if !ancestorMap[imageID] {
 		for _, id := range getChildren(imageID) {
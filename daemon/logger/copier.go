@@ -2,14 +2,60 @@ package logger
 
 import (
 	"bufio"
-	"bytes"
+	"fmt"
 	"io"
 	"sync"
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/go-units"
 )
 
+const (
+	maxMessageSizeKey = "max-message-size"
+
+	// DefaultMaxMessageSize is the default maximum size of a message. The
+	// lines longer than it are split into partial messages.
+	DefaultMaxMessageSize = 1024 * 1024
+
+	// minMessageSize is the smallest maximum size of a message.
+	minMessageSize = 16
+
+	// readSize is the size of the buffer the output of the container is
+	// read with.
+	readSize = 16 * 1024
+)
+
+func init() {
+	AddBuiltinLogOpts(map[string]bool{maxMessageSizeKey: true})
+	RegisterExternalValidator(validateMaxMessageSize)
+}
+
+// validateMaxMessageSize checks the max-message-size logging option.
+func validateMaxMessageSize(cfg map[string]string) error {
+	if v, ok := cfg[maxMessageSizeKey]; ok {
+		size, err := units.RAMInBytes(v)
+		if err != nil {
+			return fmt.Errorf("logger: invalid value for %s: %v", maxMessageSizeKey, err)
+		}
+		if size < minMessageSize {
+			return fmt.Errorf("logger: %s cannot be less than %d bytes", maxMessageSizeKey, minMessageSize)
+		}
+	}
+	return nil
+}
+
+// MaxMessageSize returns the maximum size of a message set by the logging
+// options cfg, or DefaultMaxMessageSize.
+func MaxMessageSize(cfg map[string]string) int {
+	if v, ok := cfg[maxMessageSizeKey]; ok {
+		if size, err := units.RAMInBytes(v); err == nil && size >= minMessageSize {
+			return int(size)
+		}
+	}
+	return DefaultMaxMessageSize
+}
+
 // Copier can copy logs from specified sources to Logger and attach
 // ContainerID and Timestamp.
 // Writes are concurrent, so you need implement some sync in your logger
@@ -17,19 +63,27 @@ type Copier struct {
 	// cid is the container id for which we are copying logs
 	cid string
 	// srcs is map of name -> reader pairs, for example "stdout", "stderr"
-	srcs     map[string]io.Reader
-	dst      Logger
+	srcs map[string]io.Reader
+	dst  Logger
+	// maxSize is the maximum size of a message
+	maxSize  int
 	copyJobs sync.WaitGroup
 	closed   chan struct{}
 }
 
-// NewCopier creates a new Copier
-func NewCopier(cid string, srcs map[string]io.Reader, dst Logger) *Copier {
+// NewCopier creates a new Copier. The lines longer than maxMessageSize are
+// split into partial messages, and a maxMessageSize of 0 selects
+// DefaultMaxMessageSize.
+func NewCopier(cid string, srcs map[string]io.Reader, dst Logger, maxMessageSize int) *Copier {
+	if maxMessageSize <= 0 {
+		maxMessageSize = DefaultMaxMessageSize
+	}
 	return &Copier{
-		cid:    cid,
-		srcs:   srcs,
-		dst:    dst,
-		closed: make(chan struct{}),
+		cid:     cid,
+		srcs:    srcs,
+		dst:     dst,
+		maxSize: maxMessageSize,
+		closed:  make(chan struct{}),
 	}
 }
 
@@ -43,25 +97,37 @@ func (c *Copier) Run() {
 
 func (c *Copier) copySrc(name string, src io.Reader) {
 	defer c.copyJobs.Done()
-	reader := bufio.NewReader(src)
+	reader := bufio.NewReaderSize(src, readSize)
+	// line holds the part of the current line which was not logged yet
+	var line []byte
 
 	for {
 		select {
 		case <-c.closed:
 			return
 		default:
-			line, err := reader.ReadBytes('\n')
-			line = bytes.TrimSuffix(line, []byte{'\n'})
+			chunk, err := reader.ReadSlice('\n')
+			line = append(line, chunk...)
+			complete := len(line) > 0 && line[len(line)-1] == '\n'
+			if complete {
+				line = line[:len(line)-1]
+			}
+
+			// A line longer than the maximum size is split into partial
+			// messages, the last part of the line being a full message.
+			for len(line) > c.maxSize || (len(line) == c.maxSize && err == bufio.ErrBufferFull) {
+				c.log(name, line[:c.maxSize], true)
+				line = append(line[:0], line[c.maxSize:]...)
+			}
 
-			// ReadBytes can return full or partial output even when it failed.
+			// ReadSlice can return full or partial output even when it failed.
 			// e.g. it can return a full entry and EOF.
-			if err == nil || len(line) > 0 {
-				if logErr := c.dst.Log(&Message{ContainerID: c.cid, Line: line, Source: name, Timestamp: time.Now().UTC()}); logErr != nil {
-					logrus.Errorf("Failed to log msg %q for logger %s: %s", line, c.dst.Name(), logErr)
-				}
+			if complete || (err != nil && err != bufio.ErrBufferFull && len(line) > 0) {
+				c.log(name, line, false)
+				line = line[:0]
 			}
 
-			if err != nil {
+			if err != nil && err != bufio.ErrBufferFull {
 				if err != io.EOF {
 					logrus.Errorf("Error scanning log stream: %s", err)
 				}
@@ -71,6 +137,21 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	}
 }
 
+// log sends a copy of line to the logger.
+func (c *Copier) log(name string, line []byte, partial bool) {
+	msg := &Message{
+		ContainerID: c.cid,
+		Line:        append([]byte(nil), line...),
+		Source:      name,
+		Timestamp:   time.Now().UTC(),
+		Partial:     partial,
+	}
+	if logErr := c.dst.Log(msg); logErr != nil {
+		loggerErrors.Inc(c.dst.Name())
+		logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
+	}
+}
+
 // Wait waits until all copying is done
 func (c *Copier) Wait() {
 	c.copyJobs.Wait()
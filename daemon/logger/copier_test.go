@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"strings"
 	"testing"
 	"time"
 )
@@ -61,7 +62,7 @@ func TestCopier(t *testing.T) {
 			"stdout": &stdout,
 			"stderr": &stderr,
 		},
-		jsonLog)
+		jsonLog, 0)
 	c.Run()
 	wait := make(chan struct{})
 	go func() {
@@ -115,7 +116,7 @@ func TestCopierSlow(t *testing.T) {
 	jsonLog := &TestLoggerJSON{Encoder: json.NewEncoder(&jsonBuf), delay: 100 * time.Millisecond}
 
 	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
-	c := NewCopier(cid, map[string]io.Reader{"stdout": &stdout}, jsonLog)
+	c := NewCopier(cid, map[string]io.Reader{"stdout": &stdout}, jsonLog, 0)
 	c.Run()
 	wait := make(chan struct{})
 	go func() {
@@ -130,3 +131,79 @@ func TestCopierSlow(t *testing.T) {
 	case <-wait:
 	}
 }
+
+type TestLoggerMessages struct {
+	msgs []*Message
+}
+
+func (l *TestLoggerMessages) Log(m *Message) error {
+	l.msgs = append(l.msgs, m)
+	return nil
+}
+
+func (l *TestLoggerMessages) Close() error { return nil }
+
+func (l *TestLoggerMessages) Name() string { return "messages" }
+
+func TestCopierMaxMessageSize(t *testing.T) {
+	long := strings.Repeat("a", 40)
+	last := strings.Repeat("b", 20)
+	for _, c := range []struct {
+		input    string
+		expected []Message
+	}{
+		{
+			input: long + "\nshort\n" + last,
+			expected: []Message{
+				{Line: []byte(long[:16]), Partial: true},
+				{Line: []byte(long[16:32]), Partial: true},
+				{Line: []byte(long[32:])},
+				{Line: []byte("short")},
+				{Line: []byte(last[:16]), Partial: true},
+				{Line: []byte(last[16:])},
+			},
+		},
+		{
+			input:    last[:16],
+			expected: []Message{{Line: []byte(last[:16])}},
+		},
+		{
+			input: last[:16] + "\n\n",
+			expected: []Message{
+				{Line: []byte(last[:16])},
+				{Line: []byte{}},
+			},
+		},
+	} {
+		l := &TestLoggerMessages{}
+		copier := NewCopier("cid", map[string]io.Reader{"stdout": strings.NewReader(c.input)}, l, 16)
+		copier.Run()
+		copier.Wait()
+
+		if len(l.msgs) != len(c.expected) {
+			t.Fatalf("Expected %d messages for %q, got %d", len(c.expected), c.input, len(l.msgs))
+		}
+		for i, msg := range l.msgs {
+			if string(msg.Line) != string(c.expected[i].Line) || msg.Partial != c.expected[i].Partial {
+				t.Fatalf("Wrong message %d for %q: %q (partial: %v), expected %q (partial: %v)", i, c.input, msg.Line, msg.Partial, c.expected[i].Line, c.expected[i].Partial)
+			}
+		}
+	}
+}
+
+func TestValidateMaxMessageSize(t *testing.T) {
+	if err := validateMaxMessageSize(map[string]string{maxMessageSizeKey: "64k"}); err != nil {
+		t.Fatal(err)
+	}
+	if size := MaxMessageSize(map[string]string{maxMessageSizeKey: "64k"}); size != 64*1024 {
+		t.Fatalf("Expected a maximum size of 64k, got %d", size)
+	}
+	if size := MaxMessageSize(map[string]string{}); size != DefaultMaxMessageSize {
+		t.Fatalf("Expected the default maximum size, got %d", size)
+	}
+	for _, v := range []string{"big", "8b"} {
+		if err := validateMaxMessageSize(map[string]string{maxMessageSizeKey: v}); err == nil {
+			t.Fatalf("Expected an error for %s", v)
+		}
+	}
+}
@@ -13,9 +13,11 @@ type Creator func(Context) (Logger, error)
 type LogOptValidator func(cfg map[string]string) error
 
 type logdriverFactory struct {
-	registry     map[string]Creator
-	optValidator map[string]LogOptValidator
-	m            sync.Mutex
+	registry           map[string]Creator
+	optValidator       map[string]LogOptValidator
+	builtinLogOpts     map[string]bool
+	externalValidators []LogOptValidator
+	m                  sync.Mutex
 }
 
 func (lf *logdriverFactory) register(name string, c Creator) error {
@@ -41,25 +43,57 @@ func (lf *logdriverFactory) registerLogOptValidator(name string, l LogOptValidat
 }
 
 func (lf *logdriverFactory) get(name string) (Creator, error) {
+	lf.m.Lock()
+	c, ok := lf.registry[name]
+	lf.m.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	// The drivers which are not built in are looked up as plugins
+	return getPluginDriver(name)
+}
+
+func (lf *logdriverFactory) addBuiltinLogOpts(opts map[string]bool) {
 	lf.m.Lock()
 	defer lf.m.Unlock()
 
-	c, ok := lf.registry[name]
-	if !ok {
-		return c, fmt.Errorf("logger: no log driver named '%s' is registered", name)
+	for k, v := range opts {
+		lf.builtinLogOpts[k] = v
 	}
-	return c, nil
 }
 
-func (lf *logdriverFactory) getLogOptValidator(name string) LogOptValidator {
+func (lf *logdriverFactory) registerExternalValidator(v LogOptValidator) {
 	lf.m.Lock()
 	defer lf.m.Unlock()
 
-	c, _ := lf.optValidator[name]
-	return c
+	lf.externalValidators = append(lf.externalValidators, v)
 }
 
-var factory = &logdriverFactory{registry: make(map[string]Creator), optValidator: make(map[string]LogOptValidator)} // global factory instance
+func (lf *logdriverFactory) validateLogOpts(name string, cfg map[string]string) error {
+	lf.m.Lock()
+	validators := lf.externalValidators
+	driverCfg := make(map[string]string, len(cfg))
+	for k, v := range cfg {
+		if !lf.builtinLogOpts[k] {
+			driverCfg[k] = v
+		}
+	}
+	l := lf.optValidator[name]
+	lf.m.Unlock()
+
+	for _, v := range validators {
+		if err := v(cfg); err != nil {
+			return err
+		}
+	}
+	if l != nil {
+		return l(driverCfg)
+	}
+	return nil
+}
+
+var factory = &logdriverFactory{registry: make(map[string]Creator), optValidator: make(map[string]LogOptValidator), builtinLogOpts: make(map[string]bool)} // global factory instance
 
 // RegisterLogDriver registers the given logging driver builder with given logging
 // driver name.
@@ -74,16 +108,27 @@ func RegisterLogOptValidator(name string, l LogOptValidator) error {
 }
 
 // GetLogDriver provides the logging driver builder for a logging driver name.
+// A name which is not registered is looked up as a LogDriver plugin.
 func GetLogDriver(name string) (Creator, error) {
 	return factory.get(name)
 }
 
+// AddBuiltinLogOpts adds opts to the built-in logging options. The built-in
+// options are supported by all the logging drivers, and are not passed to
+// their validators.
+func AddBuiltinLogOpts(opts map[string]bool) {
+	factory.addBuiltinLogOpts(opts)
+}
+
+// RegisterExternalValidator registers a validator of the built-in logging
+// options. It is given the options of every logging driver.
+func RegisterExternalValidator(v LogOptValidator) {
+	factory.registerExternalValidator(v)
+}
+
 // ValidateLogOpts checks the options for the given log driver. The
-// options supported are specific to the LogDriver implementation.
+// options supported are specific to the LogDriver implementation, along
+// with the built-in options.
 func ValidateLogOpts(name string, cfg map[string]string) error {
-	l := factory.getLogOptValidator(name)
-	if l != nil {
-		return l(cfg)
-	}
-	return nil
+	return factory.validateLogOpts(name, cfg)
 }
@@ -51,6 +51,12 @@ package journald
 //	}
 //	return rc;
 //}
+//static int is_partial(sd_journal *j)
+//{
+//	const void *data;
+//	size_t length;
+//	return sd_journal_get_data(j, "CONTAINER_PARTIAL_MESSAGE", &data, &length) == 0;
+//}
 //static int wait_for_data_or_close(sd_journal *j, int pipefd)
 //{
 //	struct pollfd fds[2];
@@ -143,7 +149,17 @@ drain:
 			}
 			// Set up the time and text of the entry.
 			timestamp := time.Unix(int64(stamp)/1000000, (int64(stamp)%1000000)*1000)
-			line := append(C.GoBytes(unsafe.Pointer(msg), C.int(length)), "\n"...)
+			// Stop at the first entry after the until bound.
+			if !config.Until.IsZero() && timestamp.After(config.Until) {
+				break
+			}
+			// The line of a partial message continues in the next
+			// entry, and is not terminated.
+			partial := C.is_partial(j) != 0
+			line := C.GoBytes(unsafe.Pointer(msg), C.int(length))
+			if !partial {
+				line = append(line, "\n"...)
+			}
 			// Recover the stream name by mapping
 			// from the journal priority back to
 			// the stream that we would have
@@ -158,7 +174,7 @@ drain:
 			}
 			// Send the log message.
 			cid := s.vars["CONTAINER_ID_FULL"]
-			logWatcher.Msg <- &logger.Message{ContainerID: cid, Line: line, Source: source, Timestamp: timestamp}
+			logWatcher.Msg <- &logger.Message{ContainerID: cid, Line: line, Source: source, Timestamp: timestamp, Partial: partial}
 		}
 		// If we're at the end of the journal, we're done (for now).
 		if C.sd_journal_next(j) <= 0 {
@@ -201,6 +217,7 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 	var cmatch *C.char
 	var stamp C.uint64_t
 	var sinceUnixMicro uint64
+	var untilUnixMicro uint64
 	var pipes [2]C.int
 	cursor := ""
 
@@ -231,10 +248,20 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 		nano := config.Since.UnixNano()
 		sinceUnixMicro = uint64(nano / 1000)
 	}
+	if !config.Until.IsZero() {
+		nano := config.Until.UnixNano()
+		untilUnixMicro = uint64(nano / 1000)
+	}
 	if config.Tail > 0 {
 		lines := config.Tail
-		// Start at the end of the journal.
-		if C.sd_journal_seek_tail(j) < 0 {
+		if untilUnixMicro != 0 {
+			// Start at the until bound.
+			if C.sd_journal_seek_realtime_usec(j, C.uint64_t(untilUnixMicro)) < 0 {
+				logWatcher.Err <- fmt.Errorf("error seeking to end time in journal")
+				return
+			}
+		} else if C.sd_journal_seek_tail(j) < 0 {
+			// Start at the end of the journal.
 			logWatcher.Err <- fmt.Errorf("error seeking to end of journal")
 			return
 		}
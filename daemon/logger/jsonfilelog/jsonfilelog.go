@@ -62,7 +62,23 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		}
 	}
 
-	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capval, maxFiles)
+	var compress bool
+	if compressString, ok := ctx.Config["compress"]; ok {
+		var err error
+		compress, err = strconv.ParseBool(compressString)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewRotated(ctx, capval, maxFiles, compress)
+}
+
+// NewRotated creates a JSONFileLogger writing to the file of ctx, which is
+// rotated once its size reaches maxSize, unless it is negative, keeping up to
+// maxFiles files. The rotated files are compressed if compress is set.
+func NewRotated(ctx logger.Context, maxSize int64, maxFiles int, compress bool) (*JSONFileLogger, error) {
+	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, maxSize, maxFiles, compress)
 	if err != nil {
 		return nil, err
 	}
@@ -90,10 +106,16 @@ func (l *JSONFileLogger) Log(msg *logger.Message) error {
 	if err != nil {
 		return err
 	}
+	// The line of a partial message continues in the next message, and is
+	// not terminated.
+	line := msg.Line
+	if !msg.Partial {
+		line = append(line, '\n')
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	err = (&jsonlog.JSONLogs{
-		Log:      append(msg.Line, '\n'),
+		Log:      line,
 		Stream:   msg.Source,
 		Created:  timestamp,
 		RawAttrs: l.extra,
@@ -109,12 +131,17 @@ func (l *JSONFileLogger) Log(msg *logger.Message) error {
 	return err
 }
 
-// ValidateLogOpt looks for json specific log options max-file & max-size.
+// ValidateLogOpt looks for json specific log options max-file, max-size &
+// compress.
 func ValidateLogOpt(cfg map[string]string) error {
 	for key := range cfg {
 		switch key {
 		case "max-file":
 		case "max-size":
+		case "compress":
+			if _, err := strconv.ParseBool(cfg[key]); err != nil {
+				return fmt.Errorf("invalid value for compress: %v", err)
+			}
 		case "labels":
 		case "env":
 		default:
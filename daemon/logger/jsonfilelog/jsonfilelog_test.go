@@ -1,12 +1,14 @@
 package jsonfilelog
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -152,6 +154,287 @@ func TestJSONFileLoggerWithOpts(t *testing.T) {
 
 }
 
+func TestJSONFileLoggerCompressed(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	config := map[string]string{"max-file": "3", "max-size": "1k", "compress": "true"}
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+		Config:      config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	for i := 0; i < 40; i++ {
+		if err := l.Log(&logger.Message{ContainerID: cid, Line: []byte("line" + strconv.Itoa(i)), Source: "src1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	watcher := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	var i int
+	for msg := range watcher.Msg {
+		if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+			t.Fatalf("Wrong log line %d: %q, expected %q", i, msg.Line, expected)
+		}
+		i++
+	}
+	if i != 40 {
+		t.Fatalf("Read %d log lines, expected 40", i)
+	}
+
+	watcher = l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: 3})
+	i = 37
+	for msg := range watcher.Msg {
+		if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+			t.Fatalf("Wrong log line %d: %q, expected %q", i, msg.Line, expected)
+		}
+		i++
+	}
+	if i != 40 {
+		t.Fatalf("Expected the last log line to be 39, got %d", i-1)
+	}
+
+	// The rotated files are decompressed next to the log file, and removed
+	// once read.
+	files, err := ioutil.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fi := range files {
+		if name := filepath.Join(tmp, fi.Name()); name != filename && !strings.HasPrefix(name, filename+".") {
+			t.Fatalf("Unexpected file %s left in the log directory", name)
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{filename + ".1.gz", filename + ".2.gz"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filename + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("Expected %s to be removed once compressed, got %v", filename+".1", err)
+	}
+}
+
+func TestRotatedFileCompressedOnceFound(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "container.log.1")
+	content := `{"log":"line0\n","stream":"src1","time":"0001-01-01T00:00:00Z"}` + "\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0640); err != nil {
+		t.Fatal(err)
+	}
+	f, err := findRotatedFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rotated file is compressed between being found and opened.
+	out, err := os.Create(path + compressedExt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(out)
+	zw.Write([]byte(content))
+	zw.Close()
+	out.Close()
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	rf, err := f.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+	res, err := ioutil.ReadAll(rf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != content {
+		t.Fatalf("Wrong content: %q, expected %q", res, content)
+	}
+}
+
+func TestJSONFileLoggerReadUntil(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	start := time.Date(2016, 4, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		msg := &logger.Message{ContainerID: cid, Line: []byte("line" + strconv.Itoa(i)), Source: "src1", Timestamp: start.Add(time.Duration(i) * time.Second)}
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, c := range []struct {
+		config      logger.ReadConfig
+		first, last int
+	}{
+		{logger.ReadConfig{Tail: -1, Until: start.Add(9 * time.Second)}, 0, 9},
+		{logger.ReadConfig{Tail: 3, Until: start.Add(9 * time.Second)}, 7, 9},
+		{logger.ReadConfig{Tail: -1, Since: start.Add(5 * time.Second), Until: start.Add(9 * time.Second)}, 5, 9},
+		{logger.ReadConfig{Tail: 3}, 17, 19},
+	} {
+		watcher := l.(logger.LogReader).ReadLogs(c.config)
+		i := c.first
+		for msg := range watcher.Msg {
+			if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+				t.Fatalf("Wrong log line with %+v: %q, expected %q", c.config, msg.Line, expected)
+			}
+			i++
+		}
+		if i != c.last+1 {
+			t.Fatalf("Expected the last log line with %+v to be %d, got %d", c.config, c.last, i-1)
+		}
+	}
+}
+
+func TestJSONFileLoggerPartial(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, msg := range []*logger.Message{
+		{ContainerID: cid, Line: []byte("aaa"), Source: "src1", Partial: true},
+		{ContainerID: cid, Line: []byte("bbb"), Source: "src1", Partial: true},
+		{ContainerID: cid, Line: []byte("ccc"), Source: "src1"},
+		{ContainerID: cid, Line: []byte("ddd"), Source: "src1"},
+		{ContainerID: cid, Line: []byte("eee"), Source: "src1", Partial: true},
+	} {
+		if err := l.Log(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"log":"aaa","stream":"src1","time":"0001-01-01T00:00:00Z"}
+{"log":"bbb","stream":"src1","time":"0001-01-01T00:00:00Z"}
+{"log":"ccc\n","stream":"src1","time":"0001-01-01T00:00:00Z"}
+{"log":"ddd\n","stream":"src1","time":"0001-01-01T00:00:00Z"}
+{"log":"eee","stream":"src1","time":"0001-01-01T00:00:00Z"}
+`
+	if string(res) != expected {
+		t.Fatalf("Wrong log content: %q, expected %q", res, expected)
+	}
+
+	watcher := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	var lines []string
+	var partial []bool
+	for msg := range watcher.Msg {
+		lines = append(lines, string(msg.Line))
+		partial = append(partial, msg.Partial)
+	}
+	expectedLines := []string{"aaabbbccc\n", "ddd\n", "eee"}
+	expectedPartial := []bool{false, false, true}
+	if !reflect.DeepEqual(lines, expectedLines) || !reflect.DeepEqual(partial, expectedPartial) {
+		t.Fatalf("Wrong log lines: %q %v, expected %q %v", lines, partial, expectedLines, expectedPartial)
+	}
+}
+
+func TestJSONFileLoggerTailPartial(t *testing.T) {
+	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "container.log")
+	l, err := New(logger.Context{
+		ContainerID: cid,
+		LogPath:     filename,
+		Config:      map[string]string{"max-file": "10", "max-size": "512"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// Each line is split into 3 partial entries, and the lines are rotated
+	// across several files.
+	for i := 0; i < 10; i++ {
+		for _, msg := range []*logger.Message{
+			{ContainerID: cid, Line: []byte("aaa"), Source: "src1", Partial: true},
+			{ContainerID: cid, Line: []byte("bbb"), Source: "src1", Partial: true},
+			{ContainerID: cid, Line: []byte(strconv.Itoa(i)), Source: "src1"},
+		} {
+			if err := l.Log(msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for _, tail := range []int{1, 2, 4, 10, 20} {
+		watcher := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: tail})
+		var lines []string
+		for msg := range watcher.Msg {
+			lines = append(lines, string(msg.Line))
+		}
+		first := 10 - tail
+		if first < 0 {
+			first = 0
+		}
+		var expected []string
+		for i := first; i < 10; i++ {
+			expected = append(expected, "aaabbb"+strconv.Itoa(i)+"\n")
+		}
+		if !reflect.DeepEqual(lines, expected) {
+			t.Fatalf("Wrong log lines with a tail of %d: %q, expected %q", tail, lines, expected)
+		}
+	}
+}
+
+func TestValidateLogOptCompress(t *testing.T) {
+	if err := ValidateLogOpt(map[string]string{"compress": "true", "max-file": "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateLogOpt(map[string]string{"compress": "gzip"}); err == nil {
+		t.Fatal("Expected an error for an invalid value of compress")
+	}
+}
+
 func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 	cid := "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657"
 	tmp, err := ioutil.TempDir("", "docker-logger-")
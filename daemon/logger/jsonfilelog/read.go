@@ -2,10 +2,14 @@ package jsonfilelog
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -18,6 +22,9 @@ import (
 
 const maxJSONDecodeRetry = 20000
 
+// compressedExt is the extension of the rotated log files once compressed.
+const compressedExt = ".gz"
+
 func decodeLogLine(dec *json.Decoder, l *jsonlog.JSONLog) (*logger.Message, error) {
 	l.Reset()
 	if err := dec.Decode(l); err != nil {
@@ -27,6 +34,7 @@ func decodeLogLine(dec *json.Decoder, l *jsonlog.JSONLog) (*logger.Message, erro
 		Source:    l.Stream,
 		Timestamp: l.Created,
 		Line:      []byte(l.Log),
+		Partial:   !strings.HasSuffix(l.Log, "\n"),
 	}
 	return msg, nil
 }
@@ -44,20 +52,6 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	defer close(logWatcher.Msg)
 
 	pth := l.writer.LogPath()
-	var files []io.ReadSeeker
-	for i := l.writer.MaxFiles(); i > 1; i-- {
-		f, err := os.Open(fmt.Sprintf("%s.%d", pth, i-1))
-		if err != nil {
-			if !os.IsNotExist(err) {
-				logWatcher.Err <- err
-				break
-			}
-			continue
-		}
-		defer f.Close()
-		files = append(files, f)
-	}
-
 	latestFile, err := os.Open(pth)
 	if err != nil {
 		logWatcher.Err <- err
@@ -65,11 +59,22 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	}
 	defer latestFile.Close()
 
+	var files []io.ReadSeeker
+	if config.Tail != 0 {
+		rotated, err := openRotatedFiles(pth, l.writer.MaxFiles(), latestFile, config)
+		if err != nil {
+			logWatcher.Err <- err
+		}
+		for _, f := range rotated {
+			defer f.Close()
+			files = append(files, f)
+		}
+	}
 	files = append(files, latestFile)
 	tailer := ioutils.MultiReadSeeker(files...)
 
 	if config.Tail != 0 {
-		tailFile(tailer, logWatcher, config.Tail, config.Since)
+		tailFile(tailer, logWatcher, config.Tail, config.Since, config.Until)
 	}
 
 	if !config.Follow {
@@ -85,7 +90,7 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.mu.Unlock()
 
 	notifyRotate := l.writer.NotifyRotate()
-	followLogs(latestFile, logWatcher, notifyRotate, config.Since)
+	followLogs(latestFile, logWatcher, notifyRotate, config.Since, config.Until)
 
 	l.mu.Lock()
 	delete(l.readers, logWatcher)
@@ -94,10 +99,236 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.writer.NotifyRotateEvict(notifyRotate)
 }
 
-func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since time.Time) {
+type readSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// openRotatedFiles opens, oldest first, the rotated files of the log file
+// path which hold messages to read for config. The latest log file is only
+// read to count its lines. The rotated files older than since, newer than
+// until, or not needed for the tail of the logs are not opened, so that the
+// compressed ones are not decompressed.
+func openRotatedFiles(path string, maxFiles int, latest io.ReadSeeker, config logger.ReadConfig) ([]readSeekCloser, error) {
+	// With an until bound, the tail is only known once it is reached.
+	countLines := config.Tail > 0 && config.Until.IsZero()
+	var lines int
+	if countLines {
+		n, err := lineCount(latest)
+		if err != nil {
+			return nil, err
+		}
+		lines = n
+	}
+
+	var files []readSeekCloser
+	for i := 1; i < maxFiles; i++ {
+		if countLines && lines >= config.Tail {
+			break
+		}
+		f, err := findRotatedFile(fmt.Sprintf("%s.%d", path, i))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return files, err
+		}
+		if !config.Since.IsZero() {
+			// The older files were last written to before this one.
+			if last, err := f.lastWrite(); err == nil && !last.IsZero() && last.Before(config.Since) {
+				break
+			}
+		}
+		if !config.Until.IsZero() {
+			if first, err := f.firstTimestamp(); err == nil && first.After(config.Until) {
+				continue
+			}
+		}
+		rf, err := f.open()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return files, err
+		}
+		if countLines {
+			n, err := lineCount(rf)
+			if err != nil {
+				rf.Close()
+				return files, err
+			}
+			lines += n
+		}
+		files = append([]readSeekCloser{rf}, files...)
+	}
+	return files, nil
+}
+
+// lineCount returns the number of lines logged in f, a line split into
+// partial entries being counted once, and seeks back to its start.
+func lineCount(f io.ReadSeeker) (int, error) {
+	var n int
+	dec := json.NewDecoder(f)
+	var l jsonlog.JSONLog
+	for {
+		l.Reset()
+		// The last entry may still be being written.
+		if err := dec.Decode(&l); err != nil {
+			break
+		}
+		if strings.HasSuffix(l.Log, "\n") {
+			n++
+		}
+	}
+	_, err := f.Seek(0, os.SEEK_SET)
+	return n, err
+}
+
+// rotatedFile is a rotated log file, which may have been compressed.
+type rotatedFile struct {
+	path       string
+	compressed bool
+}
+
+// findRotatedFile returns the rotated log file path, or its compressed
+// version.
+func findRotatedFile(path string) (*rotatedFile, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return &rotatedFile{path: path}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if _, err := os.Stat(path + compressedExt); err != nil {
+		return nil, err
+	}
+	return &rotatedFile{path: path + compressedExt, compressed: true}, nil
+}
+
+// gzipFile is a decompressed reader of a compressed file.
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (z *gzipFile) Close() error {
+	z.Reader.Close()
+	return z.f.Close()
+}
+
+func openGzipFile(path string) (*gzipFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error decompressing log file %s: %v", path, err)
+	}
+	return &gzipFile{zr, f}, nil
+}
+
+// lastWrite returns the time the file was last written to, after the
+// messages it holds were timestamped, or the zero time if it is unknown.
+func (f *rotatedFile) lastWrite() (time.Time, error) {
+	if !f.compressed {
+		fi, err := os.Stat(f.path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return fi.ModTime(), nil
+	}
+	z, err := openGzipFile(f.path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer z.Close()
+	if z.Header.ModTime.IsZero() {
+		return time.Time{}, nil
+	}
+	// The modification time in the gzip header is truncated to the second.
+	return z.Header.ModTime.Add(time.Second), nil
+}
+
+// firstTimestamp returns the timestamp of the first message of the file,
+// only decompressing what is needed to read it.
+func (f *rotatedFile) firstTimestamp() (time.Time, error) {
+	var r io.ReadCloser
+	var err error
+	if f.compressed {
+		r, err = openGzipFile(f.path)
+	} else {
+		r, err = os.Open(f.path)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer r.Close()
+	var l jsonlog.JSONLog
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return time.Time{}, err
+	}
+	return l.Created, nil
+}
+
+// tempFile is a temporary file, removed once closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if rmErr := os.Remove(f.Name()); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// open opens the file to be read backwards. A compressed file is
+// decompressed to a temporary file next to it, rather than in the
+// temporary directory of the system which may be backed by memory.
+func (f *rotatedFile) open() (readSeekCloser, error) {
+	if !f.compressed {
+		rf, err := os.Open(f.path)
+		if err == nil {
+			return rf, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		// The file was compressed since it was found.
+		f = &rotatedFile{path: f.path + compressedExt, compressed: true}
+	}
+	z, err := openGzipFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), "."+strings.TrimSuffix(filepath.Base(f.path), compressedExt)+"-")
+	if err != nil {
+		return nil, err
+	}
+	decompressed := &tempFile{tmp}
+	if _, err = io.Copy(tmp, z); err == nil {
+		_, err = tmp.Seek(0, os.SEEK_SET)
+	}
+	if err != nil {
+		decompressed.Close()
+		return nil, fmt.Errorf("error decompressing log file %s: %v", f.path, err)
+	}
+	return decompressed, nil
+}
+
+// tailFile sends the tail last messages of f logged between since and until,
+// or all of them if tail is negative. The partial messages are reassembled
+// into the line they were split from.
+func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since, until time.Time) {
 	var rdr io.Reader = f
-	if tail > 0 {
-		ls, err := tailfile.TailFile(f, tail)
+	if tail > 0 && until.IsZero() {
+		ls, err := tailEntries(f, tail)
 		if err != nil {
 			logWatcher.Err <- err
 			return
@@ -106,22 +337,85 @@ func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since ti
 	}
 	dec := json.NewDecoder(rdr)
 	l := &jsonlog.JSONLog{}
+	// With an until bound, the last messages are only known once it is
+	// reached, so the tail is kept until then.
+	var pending []*logger.Message
+	var partial *logger.Message
 	for {
 		msg, err := decodeLogLine(dec, l)
 		if err != nil {
 			if err != io.EOF {
 				logWatcher.Err <- err
+				return
 			}
-			return
+			if partial == nil {
+				break
+			}
+			// the end of the line was not logged yet
+			msg, partial = partial, nil
+		} else if partial != nil {
+			partial.Line = append(partial.Line, msg.Line...)
+			partial.Partial = msg.Partial
+			if msg.Partial {
+				continue
+			}
+			msg, partial = partial, nil
+		} else if msg.Partial {
+			partial = msg
+			continue
 		}
 		if !since.IsZero() && msg.Timestamp.Before(since) {
 			continue
 		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			break
+		}
+		if tail > 0 && !until.IsZero() {
+			if len(pending) == tail {
+				pending = pending[1:]
+			}
+			pending = append(pending, msg)
+			continue
+		}
 		logWatcher.Msg <- msg
 	}
+	for _, msg := range pending {
+		logWatcher.Msg <- msg
+	}
+}
+
+// tailEntries returns the log entries of the last n lines of f. The lines
+// split into partial entries span several entries, so f is read further
+// back until n whole lines are read.
+func tailEntries(f io.ReadSeeker, n int) ([][]byte, error) {
+	for entries := n; ; entries *= 2 {
+		ls, err := tailfile.TailFile(f, entries)
+		if err != nil {
+			return nil, err
+		}
+		// A line starts after an entry which is not partial, or at the start
+		// of f.
+		var starts []int
+		if len(ls) < entries {
+			starts = append(starts, 0)
+		}
+		var l jsonlog.JSONLog
+		for i := 0; i < len(ls)-1; i++ {
+			l.Reset()
+			if err := json.Unmarshal(ls[i], &l); err != nil || strings.HasSuffix(l.Log, "\n") {
+				starts = append(starts, i+1)
+			}
+		}
+		if len(starts) >= n {
+			return ls[starts[len(starts)-n]:], nil
+		}
+		if len(ls) < entries {
+			return ls, nil
+		}
+	}
 }
 
-func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since time.Time) {
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since, until time.Time) {
 	dec := json.NewDecoder(f)
 	l := &jsonlog.JSONLog{}
 
@@ -197,6 +491,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		if !since.IsZero() && msg.Timestamp.Before(since) {
 			continue
 		}
+		if !until.IsZero() && msg.Timestamp.After(until) {
+			return
+		}
 		select {
 		case logWatcher.Msg <- msg:
 		case <-logWatcher.WatchClose():
@@ -209,6 +506,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 				if !since.IsZero() && msg.Timestamp.Before(since) {
 					continue
 				}
+				if !until.IsZero() && msg.Timestamp.After(until) {
+					return
+				}
 				logWatcher.Msg <- msg
 			}
 		}
@@ -0,0 +1,93 @@
+// Package logdriver defines the stream of log messages exchanged between the
+// daemon and a log driver plugin. The messages are sent to the plugin over a
+// FIFO, and read back from the response of the ReadLogs call. Each message is
+// a LogEntry encoded in JSON, preceded by its size as a 4 bytes big-endian
+// unsigned integer.
+package logdriver
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MaxEntrySize is the maximum size of an encoded log entry.
+const MaxEntrySize = 16 * 1024 * 1024
+
+// LogEntry is a log message of a container.
+type LogEntry struct {
+	// Source is the stream the message was written to, stdout or stderr
+	Source string
+	// TimeNano is the time the message was logged at, in nanoseconds since
+	// the Unix epoch
+	TimeNano int64
+	// Line is the message, without the trailing newline
+	Line []byte
+	// Partial is set when the line continues in the next entry
+	Partial bool
+}
+
+// LogEntryEncoder writes log entries to a stream.
+type LogEntryEncoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewLogEntryEncoder returns an encoder writing log entries to w.
+func NewLogEntryEncoder(w io.Writer) *LogEntryEncoder {
+	return &LogEntryEncoder{w: w}
+}
+
+// Encode writes the log entry e to the stream. The frame is written with a
+// single write, so that the entries written to a FIFO are not interleaved.
+func (enc *LogEntryEncoder) Encode(e *LogEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxEntrySize {
+		return fmt.Errorf("log entry too large: %d bytes", len(data))
+	}
+
+	enc.buf = append(enc.buf[:0], 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(enc.buf, uint32(len(data)))
+	enc.buf = append(enc.buf, data...)
+	_, err = enc.w.Write(enc.buf)
+	return err
+}
+
+// LogEntryDecoder reads log entries from a stream.
+type LogEntryDecoder struct {
+	r   io.Reader
+	buf []byte
+}
+
+// NewLogEntryDecoder returns a decoder reading log entries from r.
+func NewLogEntryDecoder(r io.Reader) *LogEntryDecoder {
+	return &LogEntryDecoder{r: r, buf: make([]byte, 4)}
+}
+
+// Decode reads the next log entry of the stream into e. It returns io.EOF
+// when the stream ends between two entries.
+func (dec *LogEntryDecoder) Decode(e *LogEntry) error {
+	if _, err := io.ReadFull(dec.r, dec.buf[:4]); err != nil {
+		return err
+	}
+	size := int(binary.BigEndian.Uint32(dec.buf[:4]))
+	if size > MaxEntrySize {
+		return fmt.Errorf("log entry too large: %d bytes", size)
+	}
+	if cap(dec.buf) < size {
+		dec.buf = make([]byte, size)
+	}
+	data := dec.buf[:size]
+	if _, err := io.ReadFull(dec.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	*e = LogEntry{}
+	return json.Unmarshal(data, e)
+}
@@ -0,0 +1,54 @@
+package logdriver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func TestLogEntryEncodeDecode(t *testing.T) {
+	entries := []LogEntry{
+		{Source: "stdout", TimeNano: 1458000000000000001, Line: []byte("hello")},
+		{Source: "stderr", TimeNano: 1458000000000000002, Line: bytes.Repeat([]byte("a"), 64*1024)},
+		{Source: "stdout", TimeNano: 1458000000000000003},
+	}
+
+	var buf bytes.Buffer
+	enc := NewLogEntryEncoder(&buf)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := NewLogEntryDecoder(&buf)
+	for _, expected := range entries {
+		var e LogEntry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Source != expected.Source || e.TimeNano != expected.TimeNano || !bytes.Equal(e.Line, expected.Line) {
+			t.Fatalf("Expected %+v, got %+v", expected, e)
+		}
+	}
+	var e LogEntry
+	if err := dec.Decode(&e); err != io.EOF {
+		t.Fatalf("Expected EOF at the end of the stream, got %v", err)
+	}
+}
+
+func TestLogEntryDecodeInvalid(t *testing.T) {
+	var e LogEntry
+
+	truncated := []byte{0, 0, 0, 10, '{'}
+	if err := NewLogEntryDecoder(bytes.NewReader(truncated)).Decode(&e); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Expected ErrUnexpectedEOF for a truncated entry, got %v", err)
+	}
+
+	tooLarge := make([]byte, 4)
+	binary.BigEndian.PutUint32(tooLarge, MaxEntrySize+1)
+	if err := NewLogEntryDecoder(bytes.NewReader(tooLarge)).Decode(&e); err == nil {
+		t.Fatal("Expected an error for an entry too large")
+	}
+}
@@ -29,6 +29,9 @@ type Message struct {
 	Line        []byte
 	Source      string
 	Timestamp   time.Time
+	// Partial is set when the message is a part of a line longer than the
+	// maximum message size, other than its last part.
+	Partial bool
 }
 
 // Logger is the interface for docker logging drivers.
@@ -41,6 +44,7 @@ type Logger interface {
 // ReadConfig is the configuration passed into ReadLogs.
 type ReadConfig struct {
 	Since  time.Time
+	Until  time.Time
 	Tail   int
 	Follow bool
 }
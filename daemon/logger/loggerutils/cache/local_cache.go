@@ -0,0 +1,150 @@
+// Package cache provides a local cache of the log messages of a container.
+// The logs of a container are read back from the cache when its logging
+// driver cannot read them, so that `docker logs` works with any driver.
+package cache
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/jsonfilelog"
+	"github.com/docker/go-units"
+)
+
+const (
+	cacheEnabledKey  = "cache-enabled"
+	cacheMaxSizeKey  = "cache-max-size"
+	cacheMaxFileKey  = "cache-max-file"
+	cacheCompressKey = "cache-compress"
+
+	defaultMaxSize  = 20 * 1024 * 1024
+	defaultMaxFile  = 5
+	defaultCompress = true
+)
+
+var builtinCacheLogOpts = map[string]bool{
+	cacheEnabledKey:  true,
+	cacheMaxSizeKey:  true,
+	cacheMaxFileKey:  true,
+	cacheCompressKey: true,
+}
+
+func init() {
+	logger.AddBuiltinLogOpts(builtinCacheLogOpts)
+	logger.RegisterExternalValidator(validateLogCacheOpts)
+}
+
+// ShouldUseCache reports whether the local cache is enabled by the logging
+// options cfg. The cache is only enabled when cache-enabled is set.
+func ShouldUseCache(cfg map[string]string) bool {
+	enabled, _ := strconv.ParseBool(cfg[cacheEnabledKey])
+	return enabled
+}
+
+// WithLocalCache returns a logger sending the log messages to l, and writing
+// them to a local cache at info.LogPath, from which they are read back. The
+// size of the cache is bounded, and its rotated files are compressed.
+func WithLocalCache(l logger.Logger, info logger.Context) (logger.Logger, error) {
+	var maxSize int64 = defaultMaxSize
+	if v, ok := info.Config[cacheMaxSizeKey]; ok {
+		var err error
+		if maxSize, err = units.FromHumanSize(v); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", cacheMaxSizeKey, err)
+		}
+	}
+	maxFiles := defaultMaxFile
+	if v, ok := info.Config[cacheMaxFileKey]; ok {
+		var err error
+		if maxFiles, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", cacheMaxFileKey, err)
+		}
+		if maxFiles < 1 {
+			return nil, fmt.Errorf("%s cannot be less than 1", cacheMaxFileKey)
+		}
+	}
+	compress := defaultCompress
+	if v, ok := info.Config[cacheCompressKey]; ok {
+		var err error
+		if compress, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", cacheCompressKey, err)
+		}
+	}
+	// The extra attributes of the logging driver are not written to the cache.
+	info.Config = nil
+
+	cache, err := jsonfilelog.NewRotated(info, maxSize, maxFiles, compress)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the local log cache: %v", err)
+	}
+	return &loggerWithCache{
+		l:     l,
+		cache: cache,
+	}, nil
+}
+
+// loggerWithCache is a Logger writing the log messages both to a logging
+// driver and to the local cache, which the logs are read from.
+type loggerWithCache struct {
+	l     logger.Logger
+	cache *jsonfilelog.JSONFileLogger
+}
+
+// Log sends msg to the logging driver, and writes it to the cache. An error
+// of the cache is logged, but not returned.
+func (l *loggerWithCache) Log(msg *logger.Message) error {
+	if err := l.cache.Log(msg); err != nil {
+		logrus.WithField("container", msg.ContainerID).Errorf("Error writing to the local log cache: %v", err)
+	}
+	return l.l.Log(msg)
+}
+
+// Name returns the name of the logging driver.
+func (l *loggerWithCache) Name() string {
+	return l.l.Name()
+}
+
+// ReadLogs reads the log messages from the cache.
+func (l *loggerWithCache) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	return l.cache.ReadLogs(config)
+}
+
+// Close closes the logging driver and the cache.
+func (l *loggerWithCache) Close() error {
+	err := l.l.Close()
+	if cacheErr := l.cache.Close(); err == nil {
+		err = cacheErr
+	}
+	return err
+}
+
+// validateLogCacheOpts checks the options of the cache in the logging
+// options cfg.
+func validateLogCacheOpts(cfg map[string]string) error {
+	if v, ok := cfg[cacheEnabledKey]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", cacheEnabledKey, err)
+		}
+	}
+	if v, ok := cfg[cacheMaxSizeKey]; ok {
+		if _, err := units.FromHumanSize(v); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", cacheMaxSizeKey, err)
+		}
+	}
+	if v, ok := cfg[cacheMaxFileKey]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %v", cacheMaxFileKey, err)
+		}
+		if n < 1 {
+			return fmt.Errorf("%s cannot be less than 1", cacheMaxFileKey)
+		}
+	}
+	if v, ok := cfg[cacheCompressKey]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", cacheCompressKey, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/docker/docker/daemon/logger"
+)
+
+type testLogger struct {
+	msgs   []*logger.Message
+	closed bool
+}
+
+func (l *testLogger) Log(msg *logger.Message) error {
+	l.msgs = append(l.msgs, msg)
+	return nil
+}
+
+func (l *testLogger) Name() string {
+	return "test"
+}
+
+func (l *testLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestLoggerWithCache(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-cache-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	driver := &testLogger{}
+	l, err := WithLocalCache(driver, logger.Context{
+		ContainerID: "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657",
+		LogPath:     filepath.Join(tmp, "container-cache.log"),
+		Config:      map[string]string{"gelf-address": "udp://localhost:12201", cacheMaxSizeKey: "1k"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name() != "test" {
+		t.Fatalf("Expected the name of the driver, got %s", l.Name())
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := l.Log(&logger.Message{Line: []byte("line" + strconv.Itoa(i)), Source: "stdout"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(driver.msgs) != 20 {
+		t.Fatalf("Expected 20 messages to be sent to the driver, got %d", len(driver.msgs))
+	}
+
+	reader, ok := l.(logger.LogReader)
+	if !ok {
+		t.Fatal("Expected the logger to read the logs from the cache")
+	}
+	watcher := reader.ReadLogs(logger.ReadConfig{Tail: 5})
+	i := 15
+	for msg := range watcher.Msg {
+		if expected := "line" + strconv.Itoa(i) + "\n"; string(msg.Line) != expected {
+			t.Fatalf("Wrong log line: %q, expected %q", msg.Line, expected)
+		}
+		i++
+	}
+	if i != 20 {
+		t.Fatalf("Read %d log lines, expected 5", i-15)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !driver.closed {
+		t.Fatal("Expected the driver to be closed")
+	}
+	// The rotated files of the cache are compressed by default.
+	if _, err := os.Stat(filepath.Join(tmp, "container-cache.log.1.gz")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShouldUseCache(t *testing.T) {
+	if ShouldUseCache(map[string]string{}) {
+		t.Fatal("Expected the cache to be disabled by default")
+	}
+	if ShouldUseCache(map[string]string{cacheEnabledKey: "false"}) {
+		t.Fatalf("Expected the cache to be disabled by %s=false", cacheEnabledKey)
+	}
+	if !ShouldUseCache(map[string]string{cacheEnabledKey: "true"}) {
+		t.Fatalf("Expected the cache to be enabled by %s", cacheEnabledKey)
+	}
+}
+
+func TestValidateLogCacheOpts(t *testing.T) {
+	valid := map[string]string{
+		cacheEnabledKey:  "true",
+		cacheMaxSizeKey:  "10m",
+		cacheMaxFileKey:  "3",
+		cacheCompressKey: "false",
+	}
+	if err := logger.ValidateLogOpts("test-cache", valid); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, invalid := range []map[string]string{
+		{cacheEnabledKey: "maybe"},
+		{cacheMaxSizeKey: "big"},
+		{cacheMaxFileKey: "0"},
+		{cacheCompressKey: "gzip"},
+	} {
+		if err := logger.ValidateLogOpts("test-cache", invalid); err == nil {
+			t.Fatalf("Expected an error for %v", invalid)
+		}
+	}
+}
+
+func TestValidateLogOptsStripsCacheOpts(t *testing.T) {
+	if err := logger.RegisterLogOptValidator("test-strict", func(cfg map[string]string) error {
+		if len(cfg) != 0 {
+			t.Fatalf("Expected the cache options to be removed, got %v", cfg)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.ValidateLogOpts("test-strict", map[string]string{cacheMaxFileKey: "2"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,24 +1,33 @@
 package loggerutils
 
 import (
+	"compress/gzip"
+	"io"
 	"os"
 	"strconv"
 	"sync"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/pubsub"
 )
 
+// compressedExt is the extension of the rotated files once compressed.
+const compressedExt = ".gz"
+
 // RotateFileWriter is Logger implementation for default Docker logging.
 type RotateFileWriter struct {
 	f            *os.File // store for closing
 	mu           sync.Mutex
 	capacity     int64 //maximum size of each file
 	maxFiles     int   //maximum number of files
+	compress     bool  //whether the rotated files are compressed
+	compressing  sync.WaitGroup
 	notifyRotate *pubsub.Publisher
 }
 
-//NewRotateFileWriter creates new RotateFileWriter
-func NewRotateFileWriter(logPath string, capacity int64, maxFiles int) (*RotateFileWriter, error) {
+//NewRotateFileWriter creates new RotateFileWriter. If compress is set, the
+//rotated files are compressed with gzip.
+func NewRotateFileWriter(logPath string, capacity int64, maxFiles int, compress bool) (*RotateFileWriter, error) {
 	log, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
 	if err != nil {
 		return &RotateFileWriter{}, err
@@ -28,6 +37,7 @@ func NewRotateFileWriter(logPath string, capacity int64, maxFiles int) (*RotateF
 		f:            log,
 		capacity:     capacity,
 		maxFiles:     maxFiles,
+		compress:     compress,
 		notifyRotate: pubsub.NewPublisher(0, 1),
 	}, nil
 }
@@ -58,9 +68,20 @@ func (w *RotateFileWriter) checkCapacityAndRotate() error {
 		if err := w.f.Close(); err != nil {
 			return err
 		}
+		// the previous rotated file must be compressed before being rotated again
+		w.compressing.Wait()
 		if err := rotate(name, w.maxFiles); err != nil {
 			return err
 		}
+		if w.compress && w.maxFiles > 1 {
+			w.compressing.Add(1)
+			go func() {
+				defer w.compressing.Done()
+				if err := compressFile(name + ".1"); err != nil {
+					logrus.Errorf("Error compressing log file %s: %v", name+".1", err)
+				}
+			}()
+		}
 		file, err := os.OpenFile(name, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 06400)
 		if err != nil {
 			return err
@@ -72,18 +93,25 @@ func (w *RotateFileWriter) checkCapacityAndRotate() error {
 	return nil
 }
 
+// rotate shifts the rotated files, compressed or not, and rotates the file
+// name to name.1.
 func rotate(name string, maxFiles int) error {
 	if maxFiles < 2 {
 		return nil
 	}
 	for i := maxFiles - 1; i > 1; i-- {
-		toPath := name + "." + strconv.Itoa(i)
-		fromPath := name + "." + strconv.Itoa(i-1)
-		if err := backup(fromPath, toPath); err != nil && !os.IsNotExist(err) {
-			return err
+		for _, ext := range []string{"", compressedExt} {
+			toPath := name + "." + strconv.Itoa(i) + ext
+			fromPath := name + "." + strconv.Itoa(i-1) + ext
+			if err := backup(fromPath, toPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
 		}
 	}
 
+	if err := os.Remove(name + ".1" + compressedExt); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	if err := backup(name, name+".1"); err != nil {
 		return err
 	}
@@ -106,6 +134,46 @@ func backup(fromPath, toPath string) error {
 	return os.Rename(fromPath, toPath)
 }
 
+// compressFile compresses the file fileName to fileName.gz, and removes it.
+// The compressed file is renamed in place once complete, so that readers
+// find either of the files. The modification time of the file is kept in
+// the gzip header, for readers to know when it was last written to without
+// decompressing it.
+func compressFile(fileName string) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	fi, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := fileName + compressedExt + ".tmp"
+	out, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	zw.Header.ModTime = fi.ModTime()
+	_, err = io.Copy(zw, file)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, fileName+compressedExt)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Remove(fileName)
+}
+
 // LogPath returns the location the given writer logs to.
 func (w *RotateFileWriter) LogPath() string {
 	return w.f.Name()
@@ -126,7 +194,10 @@ func (w *RotateFileWriter) NotifyRotateEvict(sub chan interface{}) {
 	w.notifyRotate.Evict(sub)
 }
 
-// Close closes underlying file and signals all readers to stop.
+// Close closes underlying file and signals all readers to stop. It waits for
+// the compression of the last rotated file.
 func (w *RotateFileWriter) Close() error {
-	return w.f.Close()
+	err := w.f.Close()
+	w.compressing.Wait()
+	return err
 }
@@ -0,0 +1,8 @@
+package logger
+
+import "github.com/docker/docker/pkg/metrics"
+
+var (
+	loggerDroppedMessages = metrics.NewCounter("engine_daemon_logger_dropped_messages_total", "Number of messages dropped in the non-blocking mode, by logging driver.", "driver")
+	loggerErrors          = metrics.NewCounter("engine_daemon_logger_errors_total", "Number of messages which failed to be logged, by logging driver.", "driver")
+)
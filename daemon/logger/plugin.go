@@ -0,0 +1,232 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/logger/logdriver"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/pkg/stringid"
+)
+
+// pluginExtName is the type of the plugins implementing a logging driver.
+const pluginExtName = "LogDriver"
+
+// pluginFifoDir is the directory of the FIFOs the log messages are sent to
+// the plugins on. For a managed plugin, it is relative to the root filesystem
+// of the plugin.
+const pluginFifoDir = "/run/docker/logging"
+
+type pluginClient interface {
+	// Call calls the specified method with the specified arguments for the plugin.
+	Call(string, interface{}, interface{}) error
+	// Stream calls the specified method with the specified arguments for the plugin and returns the response IO stream
+	Stream(string, interface{}) (io.ReadCloser, error)
+}
+
+type pluginStartLoggingRequest struct {
+	File string
+	Info Context
+}
+
+type pluginStopLoggingRequest struct {
+	File string
+}
+
+type pluginReadLogsRequest struct {
+	Info   Context
+	Config ReadConfig
+}
+
+type pluginCapabilitiesResponse struct {
+	Cap pluginCapability
+	Err string
+}
+
+// pluginCapability lists the optional features implemented by a plugin.
+type pluginCapability struct {
+	ReadLogs bool
+}
+
+type pluginResponse struct {
+	Err string
+}
+
+// getPluginDriver returns a Creator for the logging driver implemented by the
+// plugin name.
+func getPluginDriver(name string) (Creator, error) {
+	pl, err := plugins.Get(name, pluginExtName)
+	if err != nil {
+		return nil, fmt.Errorf("logger: no log driver named '%s' is registered: %v", name, err)
+	}
+	return makePluginCreator(name, pl.Client, pl.BasePath), nil
+}
+
+func makePluginCreator(name string, c pluginClient, basePath string) Creator {
+	return func(ctx Context) (Logger, error) {
+		file := filepath.Join(pluginFifoDir, ctx.ContainerID+"-"+stringid.GenerateNonCryptoID())
+		a := &pluginAdapter{
+			driverName: name,
+			client:     c,
+			file:       file,
+			fifoPath:   filepath.Join(basePath, file),
+			info:       ctx,
+		}
+
+		stream, err := openPluginFifo(a.fifoPath, func() error {
+			return a.call("LogDriver.StartLogging", &pluginStartLoggingRequest{File: file, Info: ctx}, nil)
+		})
+		if err != nil {
+			return nil, err
+		}
+		a.stream = stream
+		a.enc = logdriver.NewLogEntryEncoder(stream)
+
+		var caps pluginCapabilitiesResponse
+		if err := c.Call("LogDriver.Capabilities", nil, &caps); err != nil || caps.Err != "" {
+			logrus.Debugf("Logging driver %s does not report its capabilities: %v %s", name, err, caps.Err)
+		}
+		if caps.Cap.ReadLogs {
+			return &pluginAdapterWithRead{a}, nil
+		}
+		return a, nil
+	}
+}
+
+// pluginAdapter is a Logger sending the log messages of a container to a
+// plugin, over a FIFO.
+type pluginAdapter struct {
+	driverName string
+	client     pluginClient
+	file       string
+	fifoPath   string
+	info       Context
+
+	mu     sync.Mutex
+	stream io.WriteCloser
+	enc    *logdriver.LogEntryEncoder
+	closed bool
+}
+
+func (a *pluginAdapter) call(method string, args interface{}, ret *pluginResponse) error {
+	if ret == nil {
+		ret = &pluginResponse{}
+	}
+	if err := a.client.Call(method, args, ret); err != nil {
+		return err
+	}
+	if ret.Err != "" {
+		return fmt.Errorf("%s: %s", method, ret.Err)
+	}
+	return nil
+}
+
+// Log sends msg to the plugin.
+func (a *pluginAdapter) Log(msg *Message) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return fmt.Errorf("logger: %s is closed", a.driverName)
+	}
+	return a.enc.Encode(&logdriver.LogEntry{
+		Source:   msg.Source,
+		TimeNano: msg.Timestamp.UnixNano(),
+		Line:     msg.Line,
+		Partial:  msg.Partial,
+	})
+}
+
+// Name returns the name of the plugin.
+func (a *pluginAdapter) Name() string {
+	return a.driverName
+}
+
+// Close closes the FIFO, so that the plugin reads the end of the stream, and
+// stops the logging of the container in the plugin.
+func (a *pluginAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+
+	err := a.stream.Close()
+	if stopErr := a.call("LogDriver.StopLogging", &pluginStopLoggingRequest{File: a.file}, nil); err == nil {
+		err = stopErr
+	}
+	if rmErr := os.Remove(a.fifoPath); rmErr != nil && !os.IsNotExist(rmErr) {
+		logrus.Errorf("Error removing logging FIFO %s: %v", a.fifoPath, rmErr)
+	}
+	return err
+}
+
+// pluginAdapterWithRead is the Logger of a plugin which can read back the log
+// messages of a container.
+type pluginAdapterWithRead struct {
+	*pluginAdapter
+}
+
+// ReadLogs reads the log messages of the container from the plugin.
+func (a *pluginAdapterWithRead) ReadLogs(config ReadConfig) *LogWatcher {
+	watcher := NewLogWatcher()
+
+	go func() {
+		defer close(watcher.Msg)
+
+		stream, err := a.client.Stream("LogDriver.ReadLogs", &pluginReadLogsRequest{Info: a.info, Config: config})
+		if err != nil {
+			watcher.Err <- err
+			return
+		}
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-watcher.WatchClose():
+			case <-done:
+			}
+			stream.Close()
+		}()
+
+		dec := logdriver.NewLogEntryDecoder(stream)
+		for {
+			var e logdriver.LogEntry
+			if err := dec.Decode(&e); err != nil {
+				select {
+				case <-watcher.WatchClose():
+				default:
+					if err != io.EOF {
+						watcher.Err <- err
+					}
+				}
+				return
+			}
+
+			msg := &Message{
+				ContainerID: a.info.ContainerID,
+				Line:        e.Line,
+				Source:      e.Source,
+				Timestamp:   time.Unix(0, e.TimeNano).UTC(),
+				Partial:     e.Partial,
+			}
+			if !e.Partial {
+				msg.Line = append(msg.Line, '\n')
+			}
+			select {
+			case watcher.Msg <- msg:
+			case <-watcher.WatchClose():
+				return
+			}
+		}
+	}()
+
+	return watcher
+}
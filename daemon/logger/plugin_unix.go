@@ -0,0 +1,63 @@
+// +build linux freebsd
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// pluginFifoTimeout is the time a plugin is given to open the FIFO, once it
+// is asked to start logging.
+const pluginFifoTimeout = 10 * time.Second
+
+// openPluginFifo creates a FIFO at path, and calls start to let the plugin
+// open it for reading. The FIFO is returned once opened for writing.
+func openPluginFifo(path string, start func() error) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := syscall.Mkfifo(path, 0700); err != nil {
+		return nil, fmt.Errorf("logger: error creating FIFO %s: %v", path, err)
+	}
+
+	type openResult struct {
+		f   *os.File
+		err error
+	}
+	// Opening a FIFO blocks until the other end is opened, which the
+	// plugin may do before or after answering.
+	opened := make(chan openResult, 1)
+	go func() {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		opened <- openResult{f, err}
+	}()
+
+	err := start()
+	if err == nil {
+		select {
+		case res := <-opened:
+			if res.err != nil {
+				os.Remove(path)
+				return nil, res.err
+			}
+			return res.f, nil
+		case <-time.After(pluginFifoTimeout):
+			err = fmt.Errorf("logger: timeout waiting for the plugin to open %s", path)
+		}
+	}
+
+	// Unblock the pending open by opening the read end.
+	if r, rErr := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0); rErr == nil {
+		if res := <-opened; res.f != nil {
+			res.f.Close()
+		}
+		r.Close()
+	}
+	os.Remove(path)
+	return nil, err
+}
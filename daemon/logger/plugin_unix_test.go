@@ -0,0 +1,144 @@
+// +build linux freebsd
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger/logdriver"
+)
+
+// fakeLogPlugin is a LogDriver plugin reading the messages sent over the FIFO.
+type fakeLogPlugin struct {
+	t        *testing.T
+	basePath string
+	entries  chan logdriver.LogEntry
+	stopped  string
+}
+
+func (p *fakeLogPlugin) Call(method string, args interface{}, ret interface{}) error {
+	switch method {
+	case "LogDriver.StartLogging":
+		req := args.(*pluginStartLoggingRequest)
+		go func() {
+			f, err := os.Open(filepath.Join(p.basePath, req.File))
+			if err != nil {
+				p.t.Error(err)
+				return
+			}
+			defer f.Close()
+			dec := logdriver.NewLogEntryDecoder(f)
+			for {
+				var e logdriver.LogEntry
+				if err := dec.Decode(&e); err != nil {
+					close(p.entries)
+					return
+				}
+				p.entries <- e
+			}
+		}()
+	case "LogDriver.StopLogging":
+		p.stopped = args.(*pluginStopLoggingRequest).File
+	case "LogDriver.Capabilities":
+		ret.(*pluginCapabilitiesResponse).Cap.ReadLogs = true
+	}
+	return nil
+}
+
+func (p *fakeLogPlugin) Stream(method string, args interface{}) (io.ReadCloser, error) {
+	var buf bytes.Buffer
+	enc := logdriver.NewLogEntryEncoder(&buf)
+	enc.Encode(&logdriver.LogEntry{Source: "stdout", TimeNano: 1458000000000000000, Line: []byte("hello")})
+	enc.Encode(&logdriver.LogEntry{Source: "stderr", TimeNano: 1458000000000000001, Line: []byte("world")})
+	return ioutil.NopCloser(&buf), nil
+}
+
+func TestPluginLogger(t *testing.T) {
+	basePath, err := ioutil.TempDir("", "logger-plugin-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(basePath)
+
+	p := &fakeLogPlugin{t: t, basePath: basePath, entries: make(chan logdriver.LogEntry, 10)}
+	l, err := makePluginCreator("test", p, basePath)(Context{ContainerID: "abcdef"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name() != "test" {
+		t.Fatalf("Expected the logger to be named after the plugin, got %s", l.Name())
+	}
+
+	now := time.Now().UTC()
+	if err := l.Log(&Message{ContainerID: "abcdef", Line: []byte("line1"), Source: "stdout", Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-p.entries:
+		if e.Source != "stdout" || string(e.Line) != "line1" || e.TimeNano != now.UnixNano() {
+			t.Fatalf("Unexpected log entry %+v", e)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for the log entry")
+	}
+
+	reader, ok := l.(LogReader)
+	if !ok {
+		t.Fatal("Expected the logger to read logs")
+	}
+	watcher := reader.ReadLogs(ReadConfig{Tail: -1})
+	var lines []string
+	for msg := range watcher.Msg {
+		lines = append(lines, msg.Source+":"+string(msg.Line))
+	}
+	if len(lines) != 2 || lines[0] != "stdout:hello\n" || lines[1] != "stderr:world\n" {
+		t.Fatalf("Unexpected logs read %q", lines)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-p.entries; ok {
+		t.Fatal("Expected the FIFO to be closed")
+	}
+	if p.stopped == "" {
+		t.Fatal("Expected the logging to be stopped")
+	}
+	if _, err := os.Stat(filepath.Join(basePath, p.stopped)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the FIFO to be removed, got %v", err)
+	}
+}
+
+func TestPluginLoggerStartError(t *testing.T) {
+	basePath, err := ioutil.TempDir("", "logger-plugin-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(basePath)
+
+	_, err = makePluginCreator("test", &failingLogPlugin{}, basePath)(Context{ContainerID: "abcdef"})
+	if err == nil {
+		t.Fatal("Expected an error when the plugin fails to start logging")
+	}
+	files, _ := ioutil.ReadDir(filepath.Join(basePath, pluginFifoDir))
+	if len(files) != 0 {
+		t.Fatalf("Expected the FIFO to be removed, found %v", files)
+	}
+}
+
+type failingLogPlugin struct{}
+
+func (p *failingLogPlugin) Call(method string, args interface{}, ret interface{}) error {
+	return json.Unmarshal([]byte(`{"Err":"no such collector"}`), ret)
+}
+
+func (p *failingLogPlugin) Stream(method string, args interface{}) (io.ReadCloser, error) {
+	return nil, io.EOF
+}
@@ -0,0 +1,12 @@
+// +build !linux,!freebsd
+
+package logger
+
+import (
+	"fmt"
+	"io"
+)
+
+func openPluginFifo(path string, start func() error) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("logger: logging plugins are not supported on this platform")
+}
@@ -0,0 +1,286 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/go-units"
+)
+
+const (
+	modeKey          = "mode"
+	maxBufferSizeKey = "max-buffer-size"
+
+	// ModeBlocking is the default delivery mode, where the container blocks
+	// on its output until the logging driver accepts each message.
+	ModeBlocking = "blocking"
+	// ModeNonBlocking is the delivery mode where the messages are buffered
+	// for the logging driver, and the oldest are dropped when the buffer is
+	// full.
+	ModeNonBlocking = "non-blocking"
+
+	// DefaultMaxBufferSize is the default size of the buffer of the
+	// non-blocking mode.
+	DefaultMaxBufferSize = 1024 * 1024
+)
+
+// dropReportInterval is the interval the dropped messages are reported at.
+var dropReportInterval = 10 * time.Second
+
+var errRingClosed = errors.New("logger: the ring buffer is closed")
+
+func init() {
+	AddBuiltinLogOpts(map[string]bool{
+		modeKey:          true,
+		maxBufferSizeKey: true,
+	})
+	RegisterExternalValidator(validateRingLogOpts)
+}
+
+// validateRingLogOpts checks the mode and max-buffer-size logging options.
+func validateRingLogOpts(cfg map[string]string) error {
+	switch cfg[modeKey] {
+	case "", ModeBlocking, ModeNonBlocking:
+	default:
+		return fmt.Errorf("logger: invalid mode %q, expected %s or %s", cfg[modeKey], ModeBlocking, ModeNonBlocking)
+	}
+	if v, ok := cfg[maxBufferSizeKey]; ok {
+		if cfg[modeKey] != ModeNonBlocking {
+			return fmt.Errorf("logger: %s is only supported in the %s mode", maxBufferSizeKey, ModeNonBlocking)
+		}
+		size, err := units.RAMInBytes(v)
+		if err != nil {
+			return fmt.Errorf("logger: invalid value for %s: %v", maxBufferSizeKey, err)
+		}
+		if size <= 0 {
+			return fmt.Errorf("logger: %s must be positive", maxBufferSizeKey)
+		}
+	}
+	return nil
+}
+
+// IsNonBlocking reports whether the logging options cfg select the
+// non-blocking mode.
+func IsNonBlocking(cfg map[string]string) bool {
+	return cfg[modeKey] == ModeNonBlocking
+}
+
+// RingLogger is a Logger buffering the messages in memory, and sending them
+// to a logging driver in the background, so that a slow driver does not
+// block the container. When the buffer is full, the oldest messages are
+// dropped.
+type RingLogger struct {
+	l      Logger
+	buffer *messageRing
+	onDrop func(dropped int64)
+
+	mu      sync.Mutex
+	dropped int64
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+}
+
+// ringWithReader is the RingLogger of a driver which can read the logs back.
+type ringWithReader struct {
+	*RingLogger
+}
+
+// ReadLogs reads the logs from the logging driver.
+func (r *ringWithReader) ReadLogs(config ReadConfig) *LogWatcher {
+	return r.l.(LogReader).ReadLogs(config)
+}
+
+// NewRingLogger returns a Logger buffering up to the max-buffer-size of the
+// logging options cfg for the driver l. onDrop, if not nil, is called
+// periodically with the number of messages dropped since its last call.
+func NewRingLogger(l Logger, cfg map[string]string, onDrop func(dropped int64)) Logger {
+	maxSize := int64(DefaultMaxBufferSize)
+	if v, ok := cfg[maxBufferSizeKey]; ok {
+		if size, err := units.RAMInBytes(v); err == nil && size > 0 {
+			maxSize = size
+		}
+	}
+
+	r := &RingLogger{
+		l:      l,
+		buffer: newRing(maxSize),
+		onDrop: onDrop,
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	go r.reportDrops()
+
+	if _, ok := l.(LogReader); ok {
+		return &ringWithReader{r}
+	}
+	return r
+}
+
+// Log queues msg for the logging driver. It does not block, and drops the
+// oldest queued messages if the buffer is full.
+func (r *RingLogger) Log(msg *Message) error {
+	dropped, err := r.buffer.Enqueue(msg)
+	if err != nil {
+		return err
+	}
+	if dropped > 0 {
+		r.mu.Lock()
+		r.dropped += int64(dropped)
+		r.mu.Unlock()
+		loggerDroppedMessages.Add(float64(dropped), r.l.Name())
+	}
+	return nil
+}
+
+// Name returns the name of the logging driver.
+func (r *RingLogger) Name() string {
+	return r.l.Name()
+}
+
+// Close sends the queued messages to the logging driver, and closes it.
+func (r *RingLogger) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.closed)
+		r.buffer.Close()
+		<-r.done
+
+		for _, msg := range r.buffer.Drain() {
+			if logErr := r.l.Log(msg); logErr != nil {
+				loggerErrors.Inc(r.l.Name())
+				logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, r.l.Name(), logErr)
+				break
+			}
+		}
+		r.report()
+		err = r.l.Close()
+	})
+	return err
+}
+
+// run sends the queued messages to the logging driver until the logger is
+// closed.
+func (r *RingLogger) run() {
+	defer close(r.done)
+	for {
+		msg, err := r.buffer.Dequeue()
+		if err != nil {
+			return
+		}
+		if err := r.l.Log(msg); err != nil {
+			loggerErrors.Inc(r.l.Name())
+			logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, r.l.Name(), err)
+		}
+	}
+}
+
+// reportDrops reports the dropped messages every dropReportInterval, until
+// the logger is closed.
+func (r *RingLogger) reportDrops() {
+	ticker := time.NewTicker(dropReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.report()
+		case <-r.closed:
+			return
+		}
+	}
+}
+
+// report calls onDrop with the messages dropped since the last report.
+func (r *RingLogger) report() {
+	r.mu.Lock()
+	dropped := r.dropped
+	r.dropped = 0
+	r.mu.Unlock()
+
+	if dropped > 0 && r.onDrop != nil {
+		r.onDrop(dropped)
+	}
+}
+
+// messageRing is a queue of messages bounded by the size of their lines.
+type messageRing struct {
+	mu   sync.Mutex
+	wait *sync.Cond
+
+	size    int64
+	maxSize int64
+	queue   []*Message
+	closed  bool
+}
+
+func newRing(maxSize int64) *messageRing {
+	r := &messageRing{maxSize: maxSize}
+	r.wait = sync.NewCond(&r.mu)
+	return r
+}
+
+// Enqueue adds msg to the queue, and drops the oldest messages until the
+// queue fits in its maximum size. It returns the number of messages dropped.
+func (r *messageRing) Enqueue(msg *Message) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, errRingClosed
+	}
+	r.queue = append(r.queue, msg)
+	r.size += int64(len(msg.Line))
+
+	var dropped int
+	for r.size > r.maxSize && len(r.queue) > 1 {
+		r.size -= int64(len(r.queue[0].Line))
+		r.queue[0] = nil
+		r.queue = r.queue[1:]
+		dropped++
+	}
+	r.wait.Signal()
+	return dropped, nil
+}
+
+// Dequeue removes the oldest message from the queue, waiting for one if the
+// queue is empty. It returns an error once the queue is closed.
+func (r *messageRing) Dequeue() (*Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for len(r.queue) == 0 && !r.closed {
+		r.wait.Wait()
+	}
+	if r.closed {
+		return nil, errRingClosed
+	}
+	msg := r.queue[0]
+	r.queue[0] = nil
+	r.queue = r.queue[1:]
+	r.size -= int64(len(msg.Line))
+	return msg, nil
+}
+
+// Close closes the queue, and wakes up a pending Dequeue.
+func (r *messageRing) Close() {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.wait.Broadcast()
+}
+
+// Drain removes and returns the messages of the queue.
+func (r *messageRing) Drain() []*Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msgs := r.queue
+	r.queue = nil
+	r.size = 0
+	return msgs
+}
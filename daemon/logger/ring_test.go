@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockedLogger is a Logger blocking until it is released.
+type blockedLogger struct {
+	release chan struct{}
+	mu      sync.Mutex
+	lines   []string
+	closed  bool
+}
+
+func (l *blockedLogger) Log(m *Message) error {
+	<-l.release
+	l.mu.Lock()
+	l.lines = append(l.lines, string(m.Line))
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *blockedLogger) Name() string { return "blocked" }
+
+func (l *blockedLogger) Close() error {
+	l.closed = true
+	return nil
+}
+
+func TestRingLoggerDropsOldest(t *testing.T) {
+	driver := &blockedLogger{release: make(chan struct{})}
+	var dropped int64
+	l := NewRingLogger(driver, map[string]string{modeKey: ModeNonBlocking, maxBufferSizeKey: "10b"}, func(n int64) {
+		dropped += n
+	})
+
+	// The first message is taken by the driver, and the next ones fill the
+	// buffer of 10 bytes.
+	if err := l.Log(&Message{Line: []byte("first")}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := l.Log(&Message{Line: []byte("line" + strconv.Itoa(i))}); err != nil {
+				t.Error(err)
+			}
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Log blocked on the driver")
+	}
+
+	close(driver.release)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !driver.closed {
+		t.Fatal("Expected the driver to be closed")
+	}
+
+	expected := []string{"first", "line8", "line9"}
+	if len(driver.lines) != len(expected) {
+		t.Fatalf("Expected the driver to get %v, got %v", expected, driver.lines)
+	}
+	for i := range expected {
+		if driver.lines[i] != expected[i] {
+			t.Fatalf("Expected the driver to get %v, got %v", expected, driver.lines)
+		}
+	}
+	if dropped != 8 {
+		t.Fatalf("Expected 8 dropped messages to be reported, got %d", dropped)
+	}
+	if v := loggerDroppedMessages.Value("blocked"); v != 8 {
+		t.Fatalf("Expected the dropped messages to be counted in the metrics, got %v", v)
+	}
+
+	if err := l.Log(&Message{Line: []byte("closed")}); err == nil {
+		t.Fatal("Expected an error logging to a closed logger")
+	}
+}
+
+func TestRingLoggerWithoutReader(t *testing.T) {
+	l := NewRingLogger(&TestLoggerText{}, map[string]string{modeKey: ModeNonBlocking}, nil)
+	defer l.Close()
+	if _, ok := l.(LogReader); ok {
+		t.Fatal("Expected the logger not to read the logs of a driver which cannot")
+	}
+}
+
+func TestValidateRingLogOpts(t *testing.T) {
+	for _, cfg := range []map[string]string{
+		{},
+		{modeKey: ModeBlocking},
+		{modeKey: ModeNonBlocking},
+		{modeKey: ModeNonBlocking, maxBufferSizeKey: "4m"},
+	} {
+		if err := validateRingLogOpts(cfg); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", cfg, err)
+		}
+	}
+	for _, cfg := range []map[string]string{
+		{modeKey: "fast"},
+		{maxBufferSizeKey: "4m"},
+		{modeKey: ModeNonBlocking, maxBufferSizeKey: "big"},
+		{modeKey: ModeNonBlocking, maxBufferSizeKey: "0"},
+	} {
+		if err := validateRingLogOpts(cfg); err == nil {
+			t.Fatalf("Expected an error for %v", cfg)
+		}
+	}
+}
@@ -24,6 +24,8 @@ type ContainerLogsConfig struct {
 	Tail string
 	// filter logs by returning on those entries after this time
 	Since time.Time
+	// filter logs by returning on those entries before this time
+	Until time.Time
 	// whether or not to show stdout and stderr as well as log entries.
 	UseStdout, UseStderr bool
 	OutStream            io.Writer
@@ -54,12 +56,17 @@ func (daemon *Daemon) ContainerLogs(containerName string, config *ContainerLogsC
 	if err != nil {
 		return err
 	}
+	if cLog != container.LogDriver {
+		// The logger was started only to read the logs of a stopped
+		// container, and a plugin must be told that it is done.
+		defer cLog.Close()
+	}
 	logReader, ok := cLog.(logger.LogReader)
 	if !ok {
 		return logger.ErrReadLogsNotSupported
 	}
 
-	follow := config.Follow && container.IsRunning()
+	follow := config.Follow && container.IsRunning() && (config.Until.IsZero() || config.Until.After(time.Now()))
 	tailLines, err := strconv.Atoi(config.Tail)
 	if err != nil {
 		tailLines = -1
@@ -68,11 +75,21 @@ func (daemon *Daemon) ContainerLogs(containerName string, config *ContainerLogsC
 	logrus.Debug("logs: begin stream")
 	readConfig := logger.ReadConfig{
 		Since:  config.Since,
+		Until:  config.Until,
 		Tail:   tailLines,
 		Follow: follow,
 	}
 	logs := logReader.ReadLogs(readConfig)
 
+	// The logs are followed until the until bound, even if the container
+	// does not log anything past it.
+	var untilReached <-chan time.Time
+	if follow && !config.Until.IsZero() {
+		untilTimer := time.NewTimer(config.Until.Sub(time.Now()))
+		defer untilTimer.Stop()
+		untilReached = untilTimer.C
+	}
+
 	for {
 		select {
 		case err := <-logs.Err:
@@ -81,6 +98,9 @@ func (daemon *Daemon) ContainerLogs(containerName string, config *ContainerLogsC
 		case <-config.Stop:
 			logs.Close()
 			return nil
+		case <-untilReached:
+			logs.Close()
+			return nil
 		case msg, ok := <-logs.Msg:
 			if !ok {
 				logrus.Debugf("logs: end stream")
@@ -126,15 +146,26 @@ func (daemon *Daemon) StartLogging(container *container.Container) error {
 		return derr.ErrorCodeInitLogger.WithArgs(err)
 	}
 
-	copier := logger.NewCopier(container.ID, map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l)
-	container.LogCopier = copier
-	copier.Run()
-	container.LogDriver = l
-
 	// set LogPath field only for json-file logdriver
 	if jl, ok := l.(*jsonfilelog.JSONFileLogger); ok {
 		container.LogPath = jl.LogPath()
 	}
 
+	// In the non-blocking mode, the messages are buffered so that a slow
+	// logging driver does not block the output of the container.
+	if logger.IsNonBlocking(cfg.Config) {
+		l = logger.NewRingLogger(l, cfg.Config, func(dropped int64) {
+			logrus.Warnf("Dropped %d log messages of container %s for logging driver %s", dropped, container.ID, cfg.Type)
+			daemon.LogContainerEventWithAttributes(container, "logs_dropped", map[string]string{
+				"dropped": strconv.FormatInt(dropped, 10),
+			})
+		})
+	}
+
+	copier := logger.NewCopier(container.ID, map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l, logger.MaxMessageSize(cfg.Config))
+	container.LogCopier = copier
+	copier.Run()
+	container.LogDriver = l
+
 	return nil
 }
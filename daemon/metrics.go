@@ -0,0 +1,5 @@
+package daemon
+
+import "github.com/docker/docker/pkg/metrics"
+
+var containerActions = metrics.NewHistogram("engine_daemon_container_actions_seconds", "Duration of the container lifecycle actions, by action.", metrics.DefaultBuckets, "action")
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
+	"github.com/Sirupsen/logrus"
 	derr "github.com/docker/docker/errors"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/engine-api/types/network"
@@ -101,7 +103,7 @@ func (daemon *Daemon) GetAllNetworks() []libnetwork.Network {
 }
 
 // CreateNetwork creates a network with the given name, driver and other optional parameters
-func (daemon *Daemon) CreateNetwork(name, driver string, ipam network.IPAM, options map[string]string, internal bool) (libnetwork.Network, error) {
+func (daemon *Daemon) CreateNetwork(name, driver string, ipam network.IPAM, options, labels map[string]string, internal bool) (libnetwork.Network, error) {
 	c := daemon.netController
 	if driver == "" {
 		driver = c.Config().Daemon.DefaultDriver
@@ -123,6 +125,9 @@ func (daemon *Daemon) CreateNetwork(name, driver string, ipam network.IPAM, opti
 	if err != nil {
 		return nil, err
 	}
+	if err := daemon.networkMetadata.set(n.ID(), networkMetadata{Created: time.Now().UTC(), Labels: labels}); err != nil {
+		logrus.Errorf("Error storing the metadata of network %s: %v", n.Name(), err)
+	}
 
 	daemon.LogNetworkEvent(n, "create")
 	return n, nil
@@ -193,6 +198,14 @@ func (daemon *Daemon) GetNetworkDriverList() map[string]bool {
 	return pluginList
 }
 
+// NetworkMetadata returns the creation time and the labels of the network
+// with the given ID. They are zero for the networks which were not created
+// through the daemon, such as the predefined ones.
+func (daemon *Daemon) NetworkMetadata(networkID string) (time.Time, map[string]string) {
+	meta := daemon.networkMetadata.get(networkID)
+	return meta.Created, meta.Labels
+}
+
 // DeleteNetwork destroys a network unless it's one of docker's predefined networks.
 func (daemon *Daemon) DeleteNetwork(networkID string) error {
 	nw, err := daemon.FindNetwork(networkID)
@@ -207,6 +220,9 @@ func (daemon *Daemon) DeleteNetwork(networkID string) error {
 	if err := nw.Delete(); err != nil {
 		return err
 	}
+	if err := daemon.networkMetadata.remove(nw.ID()); err != nil {
+		logrus.Errorf("Error removing the metadata of network %s: %v", nw.Name(), err)
+	}
 	daemon.LogNetworkEvent(nw, "destroy")
 	return nil
 }
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// networkMetadata is the metadata the daemon keeps for the networks created
+// through its API, which libnetwork does not store.
+type networkMetadata struct {
+	Created time.Time
+	Labels  map[string]string
+}
+
+// networkMetadataStore keeps the metadata of the networks by network ID in a
+// JSON file, so that it survives daemon restarts.
+type networkMetadataStore struct {
+	mu   sync.Mutex
+	path string
+	nws  map[string]networkMetadata
+}
+
+// newNetworkMetadataStore loads the metadata stored at path, if any.
+func newNetworkMetadataStore(path string) (*networkMetadataStore, error) {
+	s := &networkMetadataStore{
+		path: path,
+		nws:  make(map[string]networkMetadata),
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.nws); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// get returns the metadata of the network id. It is zero for the networks
+// which were not created through the daemon, such as the predefined ones.
+func (s *networkMetadataStore) get(id string) networkMetadata {
+	if s == nil {
+		return networkMetadata{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nws[id]
+}
+
+// set stores the metadata of the network id.
+func (s *networkMetadataStore) set(id string, meta networkMetadata) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nws[id] = meta
+	return s.save()
+}
+
+// remove deletes the metadata of the network id, if any.
+func (s *networkMetadataStore) remove(id string) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.nws[id]; !ok {
+		return nil
+	}
+	delete(s.nws, id)
+	return s.save()
+}
+
+// save writes the metadata to a temporary file which then replaces the
+// stored one, so that a crash never leaves a truncated file. Called with
+// s.mu held.
+func (s *networkMetadataStore) save() error {
+	b, err := json.Marshal(s.nws)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNetworkMetadataStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-network-metadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "network", "metadata.json")
+	s, err := newNetworkMetadataStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Unix(1458000000, 0).UTC()
+	if err := s.set("nw1", networkMetadata{Created: created, Labels: map[string]string{"team": "web"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.set("nw2", networkMetadata{Created: created}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.remove("nw2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the metadata is kept across restarts
+	s, err = newNetworkMetadataStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := s.get("nw1")
+	if !meta.Created.Equal(created) || meta.Labels["team"] != "web" {
+		t.Fatalf("Expected the metadata of nw1 to be restored, got %+v", meta)
+	}
+	if meta := s.get("nw2"); !meta.Created.IsZero() {
+		t.Fatalf("Expected the metadata of nw2 to be removed, got %+v", meta)
+	}
+
+	var nilStore *networkMetadataStore
+	if err := nilStore.set("nw1", meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta := nilStore.get("nw1"); !meta.Created.IsZero() {
+		t.Fatalf("Expected no metadata without a store, got %+v", meta)
+	}
+}
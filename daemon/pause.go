@@ -1,12 +1,16 @@
 package daemon
 
 import (
+	"time"
+
 	"github.com/docker/docker/container"
 	derr "github.com/docker/docker/errors"
 )
 
 // ContainerPause pauses a container
 func (daemon *Daemon) ContainerPause(name string) error {
+	defer containerActions.ObserveSince(time.Now(), "pause")
+
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -39,6 +43,7 @@ func (daemon *Daemon) containerPause(container *container.Container) error {
 		return err
 	}
 	container.Paused = true
+	daemon.updateHealthMonitor(container)
 	daemon.LogContainerEvent(container, "pause")
 	return nil
 }
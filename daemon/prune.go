@@ -0,0 +1,250 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/runconfig"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/filters"
+	timetypes "github.com/docker/engine-api/types/time"
+)
+
+var (
+	acceptedContainersPruneFilterTags = map[string]bool{
+		"label": true,
+		"until": true,
+	}
+	acceptedImagesPruneFilterTags = map[string]bool{
+		"dangling": true,
+		"label":    true,
+		"until":    true,
+	}
+	acceptedVolumesPruneFilterTags = map[string]bool{
+		"label": true,
+		"until": true,
+	}
+	acceptedNetworksPruneFilterTags = map[string]bool{
+		"label": true,
+		"until": true,
+	}
+)
+
+// ContainersPrune removes the stopped containers matching the given filters
+// and reports the space reclaimed from their writable layers.
+func (daemon *Daemon) ContainersPrune(pruneFilters filters.Args) (*types.ContainersPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedContainersPruneFilterTags); err != nil {
+		return nil, err
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &types.ContainersPruneReport{}
+	for _, c := range daemon.List() {
+		if c.IsRunning() {
+			continue
+		}
+		if !until.IsZero() && !c.Created.Before(until) {
+			continue
+		}
+		if pruneFilters.Include("label") && !pruneFilters.MatchKVList("label", c.Config.Labels) {
+			continue
+		}
+
+		sizeRw, _ := daemon.getSize(c)
+		if err := daemon.ContainerRm(c.ID, &types.ContainerRmConfig{}); err != nil {
+			logrus.Warnf("failed to prune container %s: %v", c.ID, err)
+			continue
+		}
+		if sizeRw > 0 {
+			rep.SpaceReclaimed += uint64(sizeRw)
+		}
+		rep.ContainersDeleted = append(rep.ContainersDeleted, c.ID)
+	}
+	return rep, nil
+}
+
+// ImagesPrune removes the images which are not used by any container and
+// match the given filters. Only dangling images are removed unless the
+// "dangling" filter is set to false.
+func (daemon *Daemon) ImagesPrune(pruneFilters filters.Args) (*types.ImagesPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedImagesPruneFilterTags); err != nil {
+		return nil, err
+	}
+	danglingOnly := true
+	if pruneFilters.Include("dangling") {
+		if pruneFilters.ExactMatch("dangling", "false") || pruneFilters.ExactMatch("dangling", "0") {
+			danglingOnly = false
+		} else if !pruneFilters.ExactMatch("dangling", "true") && !pruneFilters.ExactMatch("dangling", "1") {
+			return nil, fmt.Errorf("Invalid filter 'dangling=%s'", pruneFilters.Get("dangling"))
+		}
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[image.ID]bool)
+	for _, c := range daemon.List() {
+		used[c.ImageID] = true
+	}
+
+	// Record the size of the layers of every candidate before removing
+	// them, the layers are gone once the images are deleted.
+	layerSizes := make(map[layer.ChainID]int64)
+	var candidates []image.ID
+	for id, img := range daemon.imageStore.Heads() {
+		if used[id] {
+			continue
+		}
+		if danglingOnly && len(daemon.referenceStore.References(id)) > 0 {
+			continue
+		}
+		if !until.IsZero() && !img.Created.Before(until) {
+			continue
+		}
+		if pruneFilters.Include("label") {
+			if img.Config == nil || !pruneFilters.MatchKVList("label", img.Config.Labels) {
+				continue
+			}
+		}
+		if _, err := daemon.layerChain(img, layerSizes); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, id)
+	}
+
+	rep := &types.ImagesPruneReport{}
+	for _, id := range candidates {
+		var deleted []types.ImageDelete
+		refs := daemon.referenceStore.References(id)
+		if len(refs) > 0 {
+			for _, ref := range refs {
+				records, err := daemon.ImageDelete(ref.String(), false, true)
+				if err != nil {
+					logrus.Warnf("failed to prune image %s: %v", ref.String(), err)
+					break
+				}
+				deleted = append(deleted, records...)
+			}
+		} else {
+			records, err := daemon.ImageDelete(id.String(), false, true)
+			if err != nil {
+				logrus.Warnf("failed to prune image %s: %v", id, err)
+			}
+			deleted = append(deleted, records...)
+		}
+		rep.ImagesDeleted = append(rep.ImagesDeleted, deleted...)
+	}
+
+	for _, d := range rep.ImagesDeleted {
+		if size, ok := layerSizes[layer.ChainID(d.Deleted)]; ok && size > 0 {
+			rep.SpaceReclaimed += uint64(size)
+		}
+	}
+	return rep, nil
+}
+
+// VolumesPrune removes the volumes which are not referenced by any container
+// and match the given filters, and reports the space reclaimed from the local
+// ones. The volumes without a known creation time, such as the ones created by
+// older daemons, never match the "until" filter.
+func (daemon *Daemon) VolumesPrune(pruneFilters filters.Args) (*types.VolumesPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedVolumesPruneFilterTags); err != nil {
+		return nil, err
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, _, err := daemon.volumes.List()
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &types.VolumesPruneReport{}
+	for _, v := range filterVolumes(daemon.volumes.FilterByUsed(volumes, false), pruneFilters) {
+		if !until.IsZero() {
+			tv, ok := v.(volume.TimedVolume)
+			if !ok || tv.CreatedAt().IsZero() || !tv.CreatedAt().Before(until) {
+				continue
+			}
+		}
+		size := volumeSize(v)
+		if err := daemon.VolumeRm(v.Name()); err != nil {
+			logrus.Warnf("failed to prune volume %s: %v", v.Name(), err)
+			continue
+		}
+		if size > 0 {
+			rep.SpaceReclaimed += uint64(size)
+		}
+		rep.VolumesDeleted = append(rep.VolumesDeleted, v.Name())
+	}
+	return rep, nil
+}
+
+// NetworksPrune removes the networks which have no endpoints and match the
+// given filters, except for the networks pre-defined by the daemon. The
+// networks which were not created through the daemon have no creation time
+// and never match the "until" filter.
+func (daemon *Daemon) NetworksPrune(pruneFilters filters.Args) (*types.NetworksPruneReport, error) {
+	if err := pruneFilters.Validate(acceptedNetworksPruneFilterTags); err != nil {
+		return nil, err
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &types.NetworksPruneReport{}
+	for _, nw := range daemon.GetAllNetworks() {
+		if runconfig.IsPreDefinedNetwork(nw.Name()) {
+			continue
+		}
+		if len(nw.Endpoints()) > 0 {
+			continue
+		}
+		created, labels := daemon.NetworkMetadata(nw.ID())
+		if !until.IsZero() && (created.IsZero() || !created.Before(until)) {
+			continue
+		}
+		if pruneFilters.Include("label") && !pruneFilters.MatchKVList("label", labels) {
+			continue
+		}
+		if err := daemon.DeleteNetwork(nw.ID()); err != nil {
+			logrus.Warnf("failed to prune network %s: %v", nw.Name(), err)
+			continue
+		}
+		rep.NetworksDeleted = append(rep.NetworksDeleted, nw.Name())
+	}
+	return rep, nil
+}
+
+// getUntilFromPruneFilters returns the time set by the "until" filter, or a
+// zero time if there is none. The filter accepts the same timestamps and
+// durations as the --since and --until options of docker events.
+func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
+	if !pruneFilters.Include("until") {
+		return time.Time{}, nil
+	}
+	values := pruneFilters.Get("until")
+	if len(values) > 1 {
+		return time.Time{}, fmt.Errorf("more than one until filter specified")
+	}
+	ts, err := timetypes.GetTimestamp(values[0], time.Now())
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, nanoseconds), nil
+}
@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/engine-api/types/filters"
+)
+
+func TestGetUntilFromPruneFilters(t *testing.T) {
+	args := filters.NewArgs()
+	until, err := getUntilFromPruneFilters(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !until.IsZero() {
+		t.Fatalf("Expected zero time without until filter, got %v", until)
+	}
+
+	args.Add("until", "1458000000")
+	until, err = getUntilFromPruneFilters(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !until.Equal(time.Unix(1458000000, 0)) {
+		t.Fatalf("Expected until to be 1458000000, got %v", until.Unix())
+	}
+
+	args = filters.NewArgs()
+	args.Add("until", "1h")
+	until, err = getUntilFromPruneFilters(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(until); d < 59*time.Minute || d > 61*time.Minute {
+		t.Fatalf("Expected until to be an hour ago, got %v", until)
+	}
+
+	args.Add("until", "2h")
+	if _, err := getUntilFromPruneFilters(args); err == nil {
+		t.Fatal("Expected an error with more than one until filter")
+	}
+}
+
+func TestPruneInvalidFilters(t *testing.T) {
+	daemon := &Daemon{}
+
+	args := filters.NewArgs()
+	args.Add("dangling", "true")
+	if _, err := daemon.ContainersPrune(args); err == nil {
+		t.Fatal("Expected an error for dangling filter on containers")
+	}
+
+	args = filters.NewArgs()
+	args.Add("dangling", "maybe")
+	if _, err := daemon.ImagesPrune(args); err == nil {
+		t.Fatal("Expected an error for invalid dangling filter on images")
+	}
+
+	args = filters.NewArgs()
+	args.Add("dangling", "true")
+	if _, err := daemon.VolumesPrune(args); err == nil {
+		t.Fatal("Expected an error for dangling filter on volumes")
+	}
+
+	args = filters.NewArgs()
+	args.Add("dangling", "true")
+	if _, err := daemon.NetworksPrune(args); err == nil {
+		t.Fatal("Expected an error for dangling filter on networks")
+	}
+
+	args = filters.NewArgs()
+	args.Add("until", "2h")
+	args.Add("until", "1h")
+	if _, err := daemon.NetworksPrune(args); err == nil {
+		t.Fatal("Expected an error for more than one until filter on networks")
+	}
+}
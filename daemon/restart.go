@@ -1,6 +1,8 @@
 package daemon
 
 import (
+	"time"
+
 	"github.com/docker/docker/container"
 	derr "github.com/docker/docker/errors"
 )
@@ -12,6 +14,8 @@ import (
 // stop. Returns an error if the container cannot be found, or if
 // there is an underlying error at any stage of the restart.
 func (daemon *Daemon) ContainerRestart(name string, seconds int) error {
+	defer containerActions.ObserveSince(time.Now(), "restart")
+
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -38,7 +42,7 @@ func (daemon *Daemon) containerRestart(container *container.Container, seconds i
 		return err
 	}
 
-	if err := daemon.containerStart(container); err != nil {
+	if err := daemon.containerStart(container, ""); err != nil {
 		return err
 	}
 
@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+)
+
+// SquashImage creates a new image from the image id, with all the layers
+// above the image parent merged into a single one. The config and history of
+// the image are kept, the history items of the merged layers are marked as
+// empty. If parent is empty, all the layers of the image are merged.
+func (daemon *Daemon) SquashImage(id, parent string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("Squashing images is not supported on this platform")
+	}
+
+	img, err := daemon.imageStore.Get(image.ID(id))
+	if err != nil {
+		return "", err
+	}
+
+	parentImg := &image.Image{RootFS: image.NewRootFS()}
+	if parent != "" {
+		parentImg, err = daemon.imageStore.Get(image.ID(parent))
+		if err != nil {
+			return "", fmt.Errorf("error getting specified parent layer: %v", err)
+		}
+	}
+	parentChainID := parentImg.RootFS.ChainID()
+
+	l, err := daemon.squashLayers(img.RootFS.ChainID(), parentChainID)
+	if err != nil {
+		return "", err
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+
+	newImage := *img
+	newImage.Parent = ""
+	rootFS := *parentImg.RootFS
+	rootFS.DiffIDs = append([]layer.DiffID(nil), parentImg.RootFS.DiffIDs...)
+	newImage.RootFS = &rootFS
+
+	newImage.History = append([]image.History(nil), img.History...)
+	for i := len(parentImg.History); i < len(newImage.History); i++ {
+		newImage.History[i].EmptyLayer = true
+	}
+
+	now := time.Now().UTC()
+	h := image.History{
+		Created:    now,
+		EmptyLayer: true,
+	}
+	if parent != "" {
+		h.Comment = fmt.Sprintf("merge %s to %s", id, parent)
+	} else {
+		h.Comment = fmt.Sprintf("create new from %s", id)
+	}
+	if diffID := l.DiffID(); diffID != layer.DigestSHA256EmptyTar {
+		h.EmptyLayer = false
+		newImage.RootFS.Append(diffID)
+	}
+	newImage.History = append(newImage.History, h)
+	newImage.Created = now
+
+	config, err := json.Marshal(&newImage)
+	if err != nil {
+		return "", err
+	}
+	newID, err := daemon.imageStore.Create(config)
+	if err != nil {
+		return "", err
+	}
+	if parent != "" {
+		if err := daemon.imageStore.SetParent(newID, parentImg.ID()); err != nil {
+			return "", err
+		}
+	}
+	return newID.String(), nil
+}
+
+// squashLayers registers a new layer on top of parentChainID, holding the
+// differences between the filesystems of the layers chainID and
+// parentChainID. The caller has to release the returned layer.
+func (daemon *Daemon) squashLayers(chainID, parentChainID layer.ChainID) (layer.Layer, error) {
+	newRoot, releaseNew, err := daemon.mountLayerChain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := releaseNew(); err != nil {
+			logrus.Errorf("Error releasing squashed layer: %v", err)
+		}
+	}()
+	parentRoot, releaseParent, err := daemon.mountLayerChain(parentChainID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := releaseParent(); err != nil {
+			logrus.Errorf("Error releasing parent layer: %v", err)
+		}
+	}()
+
+	changes, err := archive.ChangesDirs(newRoot, parentRoot)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := archive.ExportChanges(newRoot, changes, daemon.uidMaps, daemon.gidMaps)
+	if err != nil {
+		return nil, err
+	}
+	defer ts.Close()
+
+	return daemon.layerStore.Register(ts, parentChainID)
+}
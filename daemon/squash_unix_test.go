@@ -0,0 +1,191 @@
+// +build !windows
+
+package daemon
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/daemon/graphdriver/vfs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stringid"
+)
+
+func newSquashTestDaemon(t *testing.T) (*Daemon, func()) {
+	graphdriver.ApplyUncompressedLayer = archive.UnpackLayer
+	vfs.CopyWithTar = archive.CopyWithTar
+
+	tmp, err := ioutil.TempDir("", "docker-squash-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver, err := graphdriver.GetDriver("vfs", filepath.Join(tmp, "graph"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fms, err := layer.NewFSMetadataStore(filepath.Join(tmp, "layers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls, err := layer.NewStoreFromGraphDriver(fms, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := image.NewFSStoreBackend(filepath.Join(tmp, "images"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := image.NewImageStore(fs, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Daemon{layerStore: ls, imageStore: is}, func() {
+		driver.Cleanup()
+		os.RemoveAll(tmp)
+	}
+}
+
+// createSquashTestLayer registers a layer on top of parent, with the changes
+// made by fn to its filesystem.
+func createSquashTestLayer(t *testing.T, ls layer.Store, parent layer.ChainID, fn func(root string) error) layer.Layer {
+	rwLayer, err := ls.CreateRWLayer(stringid.GenerateRandomID(), parent, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := rwLayer.Mount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fn(root); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := rwLayer.TarStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	l, err := ls.Register(ts, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rwLayer.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ls.ReleaseRWLayer(rwLayer); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func createSquashTestImage(t *testing.T, is image.Store, layers []layer.Layer, history []image.History) image.ID {
+	rootFS := image.NewRootFS()
+	for _, l := range layers {
+		rootFS.Append(l.DiffID())
+	}
+	config, err := json.Marshal(&image.Image{
+		V1Image: image.V1Image{Created: history[len(history)-1].Created},
+		RootFS:  rootFS,
+		History: history,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := is.Create(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestSquashImage(t *testing.T) {
+	daemon, cleanup := newSquashTestDaemon(t)
+	defer cleanup()
+	ls, is := daemon.layerStore, daemon.imageStore
+
+	base := createSquashTestLayer(t, ls, "", func(root string) error {
+		return ioutil.WriteFile(filepath.Join(root, "a"), []byte("a"), 0644)
+	})
+	defer layer.ReleaseAndLog(ls, base)
+	secret := createSquashTestLayer(t, ls, base.ChainID(), func(root string) error {
+		return ioutil.WriteFile(filepath.Join(root, "secret"), []byte("secret"), 0600)
+	})
+	defer layer.ReleaseAndLog(ls, secret)
+	top := createSquashTestLayer(t, ls, secret.ChainID(), func(root string) error {
+		if err := os.Remove(filepath.Join(root, "secret")); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(root, "c"), []byte("c"), 0644)
+	})
+	defer layer.ReleaseAndLog(ls, top)
+
+	created := time.Unix(1458000000, 0).UTC()
+	history := []image.History{
+		{Created: created, CreatedBy: "ADD a"},
+		{Created: created, CreatedBy: "ADD secret"},
+		{Created: created, CreatedBy: "/bin/sh -c #(nop) ENV foo=bar", EmptyLayer: true},
+		{Created: created, CreatedBy: "RUN rm secret"},
+	}
+	baseID := createSquashTestImage(t, is, []layer.Layer{base}, history[:1])
+	imgID := createSquashTestImage(t, is, []layer.Layer{base, secret, top}, history)
+
+	squashedID, err := daemon.SquashImage(imgID.String(), baseID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	squashed, err := is.Get(image.ID(squashedID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if squashed.Parent != baseID {
+		t.Fatalf("Expected the squashed image to have parent %s, got %s", baseID, squashed.Parent)
+	}
+	if len(squashed.RootFS.DiffIDs) != 2 || squashed.RootFS.DiffIDs[0] != base.DiffID() {
+		t.Fatalf("Expected the base layer and a squashed layer, got %v", squashed.RootFS.DiffIDs)
+	}
+	if len(squashed.History) != len(history)+1 {
+		t.Fatalf("Expected %d history items, got %d", len(history)+1, len(squashed.History))
+	}
+	for i, h := range squashed.History[:len(history)] {
+		if h.CreatedBy != history[i].CreatedBy || h.EmptyLayer != (i > 0) {
+			t.Fatalf("Unexpected history item %d: %+v", i, h)
+		}
+	}
+	if h := squashed.History[len(history)]; h.EmptyLayer {
+		t.Fatalf("Expected the last history item to have the squashed layer, got %+v", h)
+	}
+
+	l, err := ls.Get(squashed.RootFS.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer layer.ReleaseAndLog(ls, l)
+	ts, err := l.TarStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	var names []string
+	tr := tar.NewReader(ts)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+	if len(names) != 1 || names[0] != "c" {
+		t.Fatalf("Expected the squashed layer to only hold c, got %v", names)
+	}
+}
@@ -2,17 +2,24 @@ package daemon
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/execdriver"
 	derr "github.com/docker/docker/errors"
 	"github.com/docker/docker/runconfig"
 	containertypes "github.com/docker/engine-api/types/container"
 )
 
-// ContainerStart starts a container.
-func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.HostConfig) error {
+// ContainerStart starts a container. If checkpoint is not empty, the
+// processes of the container are restored from this checkpoint.
+func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.HostConfig, checkpoint string) error {
+	defer containerActions.ObserveSince(time.Now(), "start")
+
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -67,19 +74,30 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 		return err
 	}
 
-	return daemon.containerStart(container)
+	var checkpointDir string
+	if checkpoint != "" {
+		if err := verifyCheckpointSettings(container); err != nil {
+			return err
+		}
+		if checkpointDir, err = existingCheckpointDir(container, checkpoint); err != nil {
+			return err
+		}
+	}
+
+	return daemon.containerStart(container, checkpointDir)
 }
 
 // Start starts a container
 func (daemon *Daemon) Start(container *container.Container) error {
-	return daemon.containerStart(container)
+	return daemon.containerStart(container, "")
 }
 
 // containerStart prepares the container to run by setting up everything the
 // container needs, such as storage and networking, as well as links
 // between containers. The container is left waiting for a signal to
-// begin running.
-func (daemon *Daemon) containerStart(container *container.Container) (err error) {
+// begin running. If checkpointDir is not empty, the processes of the
+// container are restored from the checkpoint in this directory.
+func (daemon *Daemon) containerStart(container *container.Container, checkpointDir string) (err error) {
 	container.Lock()
 	defer container.Unlock()
 
@@ -91,6 +109,13 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		return derr.ErrorCodeContainerBeingRemoved
 	}
 
+	// The checkpoint may have been deleted before the container was locked.
+	if checkpointDir != "" {
+		if _, err := os.Stat(checkpointDir); err != nil {
+			return derr.ErrorCodeNoSuchCheckpoint.WithArgs(filepath.Base(checkpointDir))
+		}
+	}
+
 	// if we encounter an error during start we need to ensure that any other
 	// setup has been cleaned up properly
 	defer func() {
@@ -146,6 +171,7 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 	mounts = append(mounts, container.TmpfsMounts()...)
 
 	container.Command.Mounts = mounts
+	container.Command.CheckpointDir = checkpointDir
 	if err := daemon.waitForStart(container); err != nil {
 		return err
 	}
@@ -153,6 +179,62 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 	return nil
 }
 
+// containerRestore re-attaches to a container which kept running while the
+// daemon was down. Its network sandbox and mounts are still in place, only
+// the execution driver command has to be built again. The container is
+// stopped if it cannot be re-attached to.
+func (daemon *Daemon) containerRestore(container *container.Container) (err error) {
+	monitored := false
+	defer func() {
+		if err == nil || !container.IsRunning() {
+			return
+		}
+		if !monitored {
+			daemon.terminateOldContainer(container)
+			daemon.releaseNetwork(container)
+			return
+		}
+		// The monitor already released the network and mounts of the
+		// container, only its process is left.
+		daemon.execDriver.Terminate(&execdriver.Command{
+			CommonCommand: execdriver.CommonCommand{
+				ID: container.ID,
+			},
+		})
+		container.SetStoppedLocking(&execdriver.ExitStatus{ExitCode: 137})
+		if err := container.ToDiskLocking(); err != nil {
+			logrus.Errorf("Error saving stopped state to disk: %v", err)
+		}
+	}()
+	container.Lock()
+	defer container.Unlock()
+
+	if err := daemon.conditionalMountOnStart(container); err != nil {
+		return err
+	}
+	// The environment is the one of the processes exec'd in the container,
+	// a linked container which is gone does not prevent re-attaching to it.
+	linkedEnv, err := daemon.setupLinkedContainers(container)
+	if err != nil {
+		logrus.Warnf("Failed to set up the links of container %s: %v", container.ID, err)
+	}
+	env := container.CreateDaemonEnvironment(linkedEnv)
+	if err := daemon.populateCommand(container, env); err != nil {
+		return err
+	}
+
+	mounts, err := daemon.setupMounts(container)
+	if err != nil {
+		return err
+	}
+	mounts = append(mounts, container.IpcMounts()...)
+	mounts = append(mounts, container.TmpfsMounts()...)
+	container.Command.Mounts = mounts
+
+	monitored = true
+	return container.RestoreMonitor(daemon, container.HostConfig.RestartPolicy)
+}
+
 func (daemon *Daemon) waitForStart(container *container.Container) error {
 	return container.StartMonitor(daemon, container.HostConfig.RestartPolicy)
 }
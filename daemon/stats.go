@@ -3,7 +3,10 @@ package daemon
 import (
 	"encoding/json"
 	"io"
+	"strings"
+	"time"
 
+	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/execdriver"
 	"github.com/docker/docker/pkg/version"
 	"github.com/docker/engine-api/types"
@@ -19,6 +22,27 @@ type ContainerStatsConfig struct {
 	Version   version.Version
 }
 
+// ContainerStatsAllConfig holds information for configuring the runtime
+// behavior of a daemon.ContainerStatsAll() call.
+type ContainerStatsAllConfig struct {
+	// All includes the containers which are not running.
+	All bool
+	// Filters are the filters of the containers, as for the list of
+	// containers.
+	Filters   string
+	Stream    bool
+	OutStream io.Writer
+	Stop      <-chan bool
+}
+
+// statsBatchInterval is the interval between two batches of the aggregate
+// stats stream.
+var statsBatchInterval = time.Second
+
+// statsPrimeBatches is the maximum number of intervals waited for the CPU
+// stats of the running containers to be primed when not streaming.
+const statsPrimeBatches = 3
+
 // ContainerStats writes information about the container to the stream
 // given in the config object.
 func (daemon *Daemon) ContainerStats(prefixOrName string, config *ContainerStatsConfig) error {
@@ -41,12 +65,7 @@ func (daemon *Daemon) ContainerStats(prefixOrName string, config *ContainerStats
 
 	var preCPUStats types.CPUStats
 	getStatJSON := func(v interface{}) *types.StatsJSON {
-		update := v.(*execdriver.ResourceStats)
-		ss := convertStatsToAPITypes(update.Stats)
-		ss.PreCPUStats = preCPUStats
-		ss.MemoryStats.Limit = uint64(update.MemoryLimit)
-		ss.Read = update.Read
-		ss.CPUStats.SystemUsage = update.SystemUsage
+		ss := resourceStatsToAPIType(v.(*execdriver.ResourceStats), preCPUStats)
 		preCPUStats = ss.CPUStats
 		return ss
 	}
@@ -122,3 +141,206 @@ func (daemon *Daemon) ContainerStats(prefixOrName string, config *ContainerStats
 		}
 	}
 }
+
+// statsSubscription is the subscription of an aggregate stats stream to the
+// stats of a container.
+type statsSubscription struct {
+	container   *container.Container
+	updates     chan interface{}
+	closed      bool
+	samples     int
+	preCPUStats types.CPUStats
+	latest      *types.StatsJSON
+}
+
+// statsUpdate is a stats update of a container forwarded to an aggregate
+// stats stream. stats is nil once the subscription is closed.
+type statsUpdate struct {
+	sub   *statsSubscription
+	stats *execdriver.ResourceStats
+}
+
+// statsTarget is a container selected by an aggregate stats stream.
+type statsTarget struct {
+	container *container.Container
+	id        string
+	name      string
+	running   bool
+}
+
+// ContainerStatsAll writes the stats of the containers selected by the
+// config object to its stream, as a JSON array of samples per interval. The
+// selection is refreshed at every interval, so that the containers which
+// start or stop while streaming are added or removed.
+func (daemon *Daemon) ContainerStatsAll(config *ContainerStatsAllConfig) error {
+	// Select the containers once before the stream starts, so that invalid
+	// filters are reported with the appropriate status code.
+	targets, err := daemon.statsTargets(config)
+	if err != nil {
+		return err
+	}
+
+	if config.Stream {
+		// Write an empty chunk of data.
+		// This is to ensure that the HTTP status code is sent immediately,
+		// even if the containers have not yet produced any data.
+		config.OutStream.Write(nil)
+	}
+
+	var (
+		enc           = json.NewEncoder(config.OutStream)
+		updates       = make(chan statsUpdate)
+		done          = make(chan struct{})
+		subscriptions = make(map[string]*statsSubscription)
+	)
+	defer func() {
+		close(done)
+		for _, sub := range subscriptions {
+			if !sub.closed {
+				daemon.unsubscribeToContainerStats(sub.container, sub.updates)
+			}
+		}
+	}()
+
+	forward := func(sub *statsSubscription) {
+		for {
+			select {
+			case v, ok := <-sub.updates:
+				update := statsUpdate{sub: sub}
+				if ok {
+					update.stats = v.(*execdriver.ResourceStats)
+				}
+				select {
+				case updates <- update:
+				case <-done:
+					return
+				}
+				if !ok {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}
+
+	// subscribe updates the subscriptions to the selected containers which
+	// are running.
+	subscribe := func(targets []statsTarget) {
+		selected := make(map[string]bool)
+		for _, t := range targets {
+			if !t.running {
+				continue
+			}
+			selected[t.id] = true
+			if sub, exists := subscriptions[t.id]; exists && !sub.closed {
+				continue
+			}
+			sub := &statsSubscription{
+				container: t.container,
+				updates:   daemon.subscribeToContainerStats(t.container),
+			}
+			subscriptions[t.id] = sub
+			go forward(sub)
+		}
+		for id, sub := range subscriptions {
+			if !selected[id] {
+				if !sub.closed {
+					daemon.unsubscribeToContainerStats(sub.container, sub.updates)
+				}
+				delete(subscriptions, id)
+			}
+		}
+	}
+
+	// primed returns whether the CPU stats of all the running containers
+	// have a previous sample to compute the CPU usage with.
+	primed := func() bool {
+		for _, sub := range subscriptions {
+			if !sub.closed && sub.samples < 2 {
+				return false
+			}
+		}
+		return true
+	}
+
+	subscribe(targets)
+	ticker := time.NewTicker(statsBatchInterval)
+	defer ticker.Stop()
+
+	var ticks int
+	for {
+		select {
+		case update := <-updates:
+			sub := update.sub
+			if subscriptions[sub.container.ID] != sub {
+				// stale update of a removed subscription
+				continue
+			}
+			if update.stats == nil {
+				sub.closed = true
+				continue
+			}
+			sub.latest = resourceStatsToAPIType(update.stats, sub.preCPUStats)
+			sub.preCPUStats = sub.latest.CPUStats
+			sub.samples++
+		case <-ticker.C:
+			ticks++
+			if !config.Stream && !primed() && ticks < statsPrimeBatches {
+				continue
+			}
+
+			batch := make([]types.ContainerStatsSample, 0, len(targets))
+			for _, t := range targets {
+				sample := types.ContainerStatsSample{ID: t.id, Name: t.name}
+				if sub, exists := subscriptions[t.id]; exists && sub.latest != nil {
+					sample.StatsJSON = *sub.latest
+				}
+				batch = append(batch, sample)
+			}
+			if err := enc.Encode(batch); err != nil {
+				return err
+			}
+			if !config.Stream {
+				return nil
+			}
+
+			if targets, err = daemon.statsTargets(config); err != nil {
+				return err
+			}
+			subscribe(targets)
+		case <-config.Stop:
+			return nil
+		}
+	}
+}
+
+// statsTargets returns the containers selected by an aggregate stats stream.
+func (daemon *Daemon) statsTargets(config *ContainerStatsAllConfig) ([]statsTarget, error) {
+	var targets []statsTarget
+	listConfig := &ContainersConfig{
+		All:     config.All,
+		Filters: config.Filters,
+	}
+	_, err := daemon.reduceContainers(listConfig, func(c *container.Container, ctx *listContext) (*types.Container, error) {
+		targets = append(targets, statsTarget{
+			container: c,
+			id:        c.ID,
+			name:      strings.TrimPrefix(c.Name, "/"),
+			running:   c.Running,
+		})
+		return &types.Container{ID: c.ID}, nil
+	})
+	return targets, err
+}
+
+// resourceStatsToAPIType converts the stats collected for a container to the
+// API type, with the CPU stats of the previous sample.
+func resourceStatsToAPIType(update *execdriver.ResourceStats, preCPUStats types.CPUStats) *types.StatsJSON {
+	ss := convertStatsToAPITypes(update.Stats)
+	ss.PreCPUStats = preCPUStats
+	ss.MemoryStats.Limit = uint64(update.MemoryLimit)
+	ss.Read = update.Read
+	ss.CPUStats.SystemUsage = update.SystemUsage
+	return ss
+}
@@ -0,0 +1,129 @@
+// +build !windows
+
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/docker/pkg/pubsub"
+	"github.com/docker/docker/pkg/registrar"
+	"github.com/docker/docker/pkg/truncindex"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/system"
+)
+
+type fakeStatsSupervisor struct{}
+
+func (fakeStatsSupervisor) GetContainerStats(c *container.Container) (*execdriver.ResourceStats, error) {
+	return &execdriver.ResourceStats{
+		Stats:       &libcontainer.Stats{},
+		Read:        time.Now(),
+		MemoryLimit: 1024,
+	}, nil
+}
+
+func newTestStatsContainer(id, name string, running bool) *container.Container {
+	c := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID:     id,
+			Name:   "/" + name,
+			State:  container.NewState(),
+			Config: &containertypes.Config{},
+		},
+	}
+	if running {
+		c.State.SetRunning(1234)
+	}
+	return c
+}
+
+func TestContainerStatsAll(t *testing.T) {
+	defer func(interval time.Duration) { statsBatchInterval = interval }(statsBatchInterval)
+	statsBatchInterval = 50 * time.Millisecond
+
+	running := newTestStatsContainer("5a4ff6a163ad4533d22d69a2b8960bf7fafdcba06e72d2febdba229008b0bf57", "web", true)
+	stopped := newTestStatsContainer("8c7b0ee4d3a9bd8b11b3de1b23a4ed2a0b6e1e1b1c9f3c6a8f5e1a2b3c4d5e6f", "db", false)
+	store := container.NewMemoryStore()
+	store.Add(running.ID, running)
+	store.Add(stopped.ID, stopped)
+
+	collector := &statsCollector{
+		interval:            10 * time.Millisecond,
+		supervisor:          fakeStatsSupervisor{},
+		publishers:          make(map[*container.Container]*pubsub.Publisher),
+		clockTicksPerSecond: uint64(system.GetClockTicks()),
+		bufReader:           bufio.NewReaderSize(nil, 128),
+	}
+	go collector.run()
+
+	daemon := &Daemon{
+		containers:     store,
+		statsCollector: collector,
+		idIndex:        truncindex.NewTruncIndex([]string{running.ID, stopped.ID}),
+		nameIndex:      registrar.NewRegistrar(),
+	}
+
+	var buf bytes.Buffer
+	if err := daemon.ContainerStatsAll(&ContainerStatsAllConfig{All: true, OutStream: &buf}); err != nil {
+		t.Fatal(err)
+	}
+	var batch []types.ContainerStatsSample
+	if err := json.Unmarshal(buf.Bytes(), &batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 2 {
+		t.Fatalf("Expected 2 samples, got %v", batch)
+	}
+	for _, sample := range batch {
+		switch sample.ID {
+		case running.ID:
+			if sample.Name != "web" || sample.MemoryStats.Limit != 1024 || sample.Read.IsZero() {
+				t.Fatalf("Unexpected sample of the running container %+v", sample)
+			}
+			if sample.CPUStats.SystemUsage == 0 || sample.PreCPUStats.SystemUsage == 0 {
+				t.Fatalf("Expected the CPU stats to be primed, got %+v", sample)
+			}
+		case stopped.ID:
+			if sample.Name != "db" || sample.MemoryStats.Limit != 0 || !sample.Read.IsZero() {
+				t.Fatalf("Expected empty stats for the stopped container, got %+v", sample)
+			}
+		default:
+			t.Fatalf("Unexpected sample %+v", sample)
+		}
+	}
+
+	buf.Reset()
+	if err := daemon.ContainerStatsAll(&ContainerStatsAllConfig{OutStream: &buf}); err != nil {
+		t.Fatal(err)
+	}
+	batch = nil
+	if err := json.Unmarshal(buf.Bytes(), &batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 1 || batch[0].ID != running.ID {
+		t.Fatalf("Expected only the running container, got %v", batch)
+	}
+
+	buf.Reset()
+	if err := daemon.ContainerStatsAll(&ContainerStatsAllConfig{Filters: `{"status":{"invalid":true}}`, OutStream: &buf}); err == nil {
+		t.Fatal("Expected an error with an invalid filter")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Expected nothing to be written with an invalid filter, got %q", buf.String())
+	}
+
+	collector.m.Lock()
+	publishers := len(collector.publishers)
+	collector.m.Unlock()
+	if publishers != 0 {
+		t.Fatalf("Expected the stats subscriptions to be removed, got %d publishers", publishers)
+	}
+}
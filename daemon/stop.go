@@ -15,6 +15,8 @@ import (
 // container is not found, is already stopped, or if there is a
 // problem stopping the container.
 func (daemon *Daemon) ContainerStop(name string, seconds int) error {
+	defer containerActions.ObserveSince(time.Now(), "stop")
+
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -1,12 +1,16 @@
 package daemon
 
 import (
+	"time"
+
 	"github.com/docker/docker/container"
 	derr "github.com/docker/docker/errors"
 )
 
 // ContainerUnpause unpauses a container
 func (daemon *Daemon) ContainerUnpause(name string) error {
+	defer containerActions.ObserveSince(time.Now(), "unpause")
+
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -39,6 +43,7 @@ func (daemon *Daemon) containerUnpause(container *container.Container) error {
 	}
 
 	container.Paused = false
+	daemon.updateHealthMonitor(container)
 	daemon.LogContainerEvent(container, "unpause")
 	return nil
 }
@@ -1,9 +1,12 @@
 package daemon
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/testutils"
+	"github.com/docker/engine-api/types/filters"
 )
 
 func TestParseVolumesFrom(t *testing.T) {
@@ -37,3 +40,51 @@ func TestParseVolumesFrom(t *testing.T) {
 		}
 	}
 }
+
+type labeledVolume struct {
+	volume.Volume
+	labels map[string]string
+}
+
+func (v labeledVolume) Labels() map[string]string {
+	return v.labels
+}
+
+func TestFilterVolumes(t *testing.T) {
+	vols := []volume.Volume{
+		labeledVolume{volumetestutils.NewFakeVolume("web-data"), map[string]string{"team": "web", "app": "blog"}},
+		labeledVolume{volumetestutils.NewFakeVolume("db-data"), map[string]string{"team": "db"}},
+		volumetestutils.NewFakeVolume("scratch"),
+	}
+
+	cases := []struct {
+		filters  []string
+		expected []string
+	}{
+		{nil, []string{"web-data", "db-data", "scratch"}},
+		{[]string{"name=data"}, []string{"web-data", "db-data"}},
+		{[]string{"name=^scratch$"}, []string{"scratch"}},
+		{[]string{"driver=fake"}, []string{"web-data", "db-data", "scratch"}},
+		{[]string{"driver=local"}, nil},
+		{[]string{"label=team"}, []string{"web-data", "db-data"}},
+		{[]string{"label=team=db"}, []string{"db-data"}},
+		{[]string{"label=team", "label=app=blog"}, []string{"web-data"}},
+		{[]string{"name=data", "label=team=web"}, []string{"web-data"}},
+	}
+	for _, c := range cases {
+		args := filters.NewArgs()
+		for _, f := range c.filters {
+			var err error
+			if args, err = filters.ParseFlag(f, args); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var names []string
+		for _, v := range filterVolumes(vols, args) {
+			names = append(names, v.Name())
+		}
+		if !reflect.DeepEqual(names, c.expected) {
+			t.Fatalf("Expected %v for %v, got %v", c.expected, c.filters, names)
+		}
+	}
+}
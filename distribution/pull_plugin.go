@@ -0,0 +1,222 @@
+package distribution
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// PluginPullConfig stores the configuration to pull a plugin.
+type PluginPullConfig struct {
+	// MetaHeaders stores HTTP headers with metadata about the plugin
+	MetaHeaders map[string][]string
+	// AuthConfig holds authentication credentials for authenticating with
+	// the registry.
+	AuthConfig *types.AuthConfig
+	// RegistryService is the registry service to use for TLS configuration
+	// and endpoint lookup.
+	RegistryService *registry.Service
+	// ValidateConfig, if set, is called with the config of the plugin
+	// before its layers are pulled. The pull fails if it returns an
+	// error.
+	ValidateConfig func(configJSON []byte) error
+}
+
+// PullPlugin pulls the plugin referenced by ref from a v2 registry. A plugin
+// is distributed as a schema2 manifest, whose config is the manifest of the
+// plugin and whose layers make up its root filesystem. The layers are
+// extracted to rootfs, and the config of the plugin is returned.
+func PullPlugin(ctx context.Context, ref reference.Named, config *PluginPullConfig, rootfs string) ([]byte, error) {
+	var configJSON []byte
+	err := withPluginManifest(ctx, ref, config, func(repo distribution.Repository, mfst *schema2.DeserializedManifest) error {
+		var err error
+		configJSON, err = pullPluginBundle(ctx, repo, mfst, config.ValidateConfig, rootfs)
+		return err
+	})
+	return configJSON, err
+}
+
+// PullPluginConfig pulls only the config of the plugin referenced by ref,
+// which is the manifest of the plugin.
+func PullPluginConfig(ctx context.Context, ref reference.Named, config *PluginPullConfig) ([]byte, error) {
+	var configJSON []byte
+	err := withPluginManifest(ctx, ref, config, func(repo distribution.Repository, mfst *schema2.DeserializedManifest) error {
+		var err error
+		configJSON, err = pullPluginConfig(ctx, repo.Blobs(ctx), mfst)
+		return err
+	})
+	return configJSON, err
+}
+
+// withPluginManifest calls fn with the schema2 manifest referenced by ref,
+// from the first v2 endpoint of the registry serving it.
+func withPluginManifest(ctx context.Context, ref reference.Named, config *PluginPullConfig, fn func(distribution.Repository, *schema2.DeserializedManifest) error) error {
+	repoInfo, err := config.RegistryService.ResolveRepository(ref)
+	if err != nil {
+		return err
+	}
+	if err := validateRepoName(repoInfo.Name()); err != nil {
+		return err
+	}
+
+	endpoints, err := config.RegistryService.LookupPullEndpoints(repoInfo)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, endpoint := range endpoints {
+		if endpoint.Version != registry.APIVersion2 {
+			continue
+		}
+		logrus.Debugf("Trying to pull plugin %s from %s", repoInfo.Name(), endpoint.URL)
+
+		repo, confirmedV2, err := NewV2Repository(ctx, repoInfo, endpoint, config.MetaHeaders, config.AuthConfig, "pull")
+		if err != nil {
+			logrus.Warnf("Error getting v2 registry: %v", err)
+			lastErr = err
+			if confirmedV2 {
+				return err
+			}
+			continue
+		}
+
+		mfst, err := getPluginManifest(ctx, repo, ref)
+		if err != nil {
+			if registry.ContinueOnError(err) {
+				logrus.Debugf("Error trying v2 registry: %v", err)
+				lastErr = err
+				continue
+			}
+			return err
+		}
+		return fn(repo, mfst)
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no endpoints found for %s", ref.String())
+	}
+	return lastErr
+}
+
+// getPluginManifest returns the schema2 manifest referenced by ref.
+func getPluginManifest(ctx context.Context, repo distribution.Repository, ref reference.Named) (*schema2.DeserializedManifest, error) {
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest distribution.Manifest
+	if digested, isDigested := ref.(reference.Canonical); isDigested {
+		manifest, err = manSvc.Get(ctx, digested.Digest())
+	} else if tagged, isTagged := ref.(reference.NamedTagged); isTagged {
+		manifest, err = manSvc.Get(ctx, "", client.WithTag(tagged.Tag()))
+	} else {
+		return nil, fmt.Errorf("internal error: reference has neither a tag nor a digest: %s", ref.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mfst, ok := manifest.(*schema2.DeserializedManifest)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a plugin: unsupported manifest format", ref.String())
+	}
+	if _, err := schema2ManifestDigest(ref, mfst); err != nil {
+		return nil, err
+	}
+	return mfst, nil
+}
+
+// pullPluginBundle downloads the config and the layers of the plugin manifest
+// mfst, and extracts the layers to rootfs. The config is checked with
+// validateConfig, if set, before the layers are downloaded.
+func pullPluginBundle(ctx context.Context, repo distribution.Repository, mfst *schema2.DeserializedManifest, validateConfig func([]byte) error, rootfs string) ([]byte, error) {
+	blobs := repo.Blobs(ctx)
+
+	configJSON, err := pullPluginConfig(ctx, blobs, mfst)
+	if err != nil {
+		return nil, err
+	}
+	if validateConfig != nil {
+		if err := validateConfig(configJSON); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(rootfs, 0755); err != nil {
+		return nil, err
+	}
+	for _, l := range mfst.Layers {
+		if err := pullPluginLayer(ctx, blobs, l.Digest, rootfs); err != nil {
+			return nil, err
+		}
+	}
+	return configJSON, nil
+}
+
+// pullPluginConfig downloads the config of the plugin manifest mfst, and
+// verifies its digest.
+func pullPluginConfig(ctx context.Context, blobs distribution.BlobStore, mfst *schema2.DeserializedManifest) ([]byte, error) {
+	configJSON, err := blobs.Get(ctx, mfst.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := digest.NewDigestVerifier(mfst.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := verifier.Write(configJSON); err != nil {
+		return nil, err
+	}
+	if !verifier.Verified() {
+		return nil, fmt.Errorf("plugin config verification failed for digest %s", mfst.Config.Digest)
+	}
+	return configJSON, nil
+}
+
+// pullPluginLayer downloads the layer dgst to a temporary file, verifies its
+// digest and extracts it to rootfs.
+func pullPluginLayer(ctx context.Context, blobs distribution.BlobStore, dgst digest.Digest, rootfs string) error {
+	layerDownload, err := blobs.Open(ctx, dgst)
+	if err != nil {
+		return err
+	}
+	defer layerDownload.Close()
+
+	tmpFile, err := ioutil.TempFile("", "GetPluginLayer")
+	if err != nil {
+		return err
+	}
+	defer tmpFileCloser(tmpFile)()
+
+	verifier, err := digest.NewDigestVerifier(dgst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmpFile, io.TeeReader(layerDownload, verifier)); err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("filesystem layer verification failed for digest %s", dgst)
+	}
+	if _, err := tmpFile.Seek(0, 0); err != nil {
+		return err
+	}
+
+	logrus.Debugf("Extracting plugin layer %s", dgst)
+	_, err = chrootarchive.ApplyLayer(rootfs, tmpFile)
+	return err
+}
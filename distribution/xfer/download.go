@@ -208,6 +208,13 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 				<-start
 			}
 
+			transferStart := time.Now()
+			defer func() {
+				if d.err != nil {
+					layerTransferErrors.Inc(directionPull)
+				}
+			}()
+
 			if parentDownload != nil {
 				// Did the parent download already fail or get
 				// cancelled?
@@ -294,7 +301,8 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 				parentLayer = l.ChainID()
 			}
 
-			reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(d.Transfer.Context(), downloadReader), progressOutput, size, descriptor.ID(), "Extracting")
+			counter := &countingReadCloser{ReadCloser: downloadReader}
+			reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(d.Transfer.Context(), counter), progressOutput, size, descriptor.ID(), "Extracting")
 			defer reader.Close()
 
 			inflatedLayerData, err := archive.DecompressStream(reader)
@@ -314,6 +322,9 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 				return
 			}
 
+			layerTransferDuration.ObserveSince(transferStart, directionPull)
+			layerTransferBytes.Add(float64(counter.count()), directionPull)
+
 			progress.Update(progressOutput, descriptor.ID(), "Pull complete")
 			withRegistered, hasRegistered := descriptor.(DownloadDescriptorWithRegistered)
 			if hasRegistered {
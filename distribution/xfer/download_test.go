@@ -262,6 +262,9 @@ func TestSuccessfulDownload(t *testing.T) {
 	}
 	firstDescriptor.diffID = l.DiffID()
 
+	pulled := layerTransferDuration.Count(directionPull)
+	pulledBytes := layerTransferBytes.Value(directionPull)
+
 	rootFS, releaseFunc, err := ldm.Download(context.Background(), *image.NewRootFS(), descriptors, progress.ChanOutput(progressChan))
 	if err != nil {
 		t.Fatalf("download error: %v", err)
@@ -295,6 +298,15 @@ func TestSuccessfulDownload(t *testing.T) {
 			t.Fatal("diffID mismatch between rootFS and Registered callback")
 		}
 	}
+
+	// The first layer already exists, and the second download of id2
+	// reuses the first one.
+	if n := layerTransferDuration.Count(directionPull); n != pulled+4 {
+		t.Fatalf("expected 4 layer pulls to be recorded, got %d", n-pulled)
+	}
+	if layerTransferBytes.Value(directionPull) <= pulledBytes {
+		t.Fatal("expected the pulled bytes to be recorded")
+	}
 }
 
 func TestCancelledDownload(t *testing.T) {
@@ -0,0 +1,57 @@
+package xfer
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/docker/docker/pkg/metrics"
+	"github.com/docker/docker/pkg/progress"
+)
+
+var (
+	layerTransferDuration = metrics.NewHistogram("engine_daemon_layer_transfer_duration_seconds", "Duration of the successful layer pulls and pushes, by direction.", metrics.DefaultBuckets, "direction")
+	layerTransferBytes    = metrics.NewCounter("engine_daemon_layer_transfer_bytes_total", "Number of bytes of the successful layer pulls and pushes, by direction.", "direction")
+	layerTransferErrors   = metrics.NewCounter("engine_daemon_layer_transfer_errors_total", "Number of failed layer pulls and pushes, by direction.", "direction")
+)
+
+const (
+	directionPull = "pull"
+	directionPush = "push"
+)
+
+// countingReadCloser counts the bytes read from a ReadCloser.
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+// count returns the number of bytes read so far.
+func (r *countingReadCloser) count() int64 {
+	return atomic.LoadInt64(&r.n)
+}
+
+// countingOutput keeps the number of bytes last reported by the progress
+// updates written to an Output, for transfers which do not expose their
+// data stream.
+type countingOutput struct {
+	progress.Output
+	n int64
+}
+
+func (out *countingOutput) WriteProgress(p progress.Progress) error {
+	if p.Current > 0 {
+		atomic.StoreInt64(&out.n, p.Current)
+	}
+	return out.Output.WriteProgress(p)
+}
+
+// count returns the number of bytes last reported.
+func (out *countingOutput) count() int64 {
+	return atomic.LoadInt64(&out.n)
+}
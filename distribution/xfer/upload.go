@@ -103,10 +103,20 @@ func (lum *LayerUploadManager) makeUploadFunc(descriptor UploadDescriptor) DoFun
 				<-start
 			}
 
+			transferStart := time.Now()
+			defer func() {
+				if u.err != nil {
+					layerTransferErrors.Inc(directionPush)
+				}
+			}()
+
 			retries := 0
 			for {
-				err := descriptor.Upload(u.Transfer.Context(), progressOutput)
+				counter := &countingOutput{Output: progressOutput}
+				err := descriptor.Upload(u.Transfer.Context(), counter)
 				if err == nil {
+					layerTransferDuration.ObserveSince(transferStart, directionPush)
+					layerTransferBytes.Add(float64(counter.count()), directionPush)
 					break
 				}
 
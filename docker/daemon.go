@@ -246,6 +246,10 @@ func (cli *DaemonCli) CmdDaemon(args ...string) error {
 	if err := migrateKey(); err != nil {
 		logrus.Fatal(err)
 	}
+
+	if err := startMetricsServer(cli.Config.MetricsAddress); err != nil {
+		logrus.Fatal(err)
+	}
 	cli.TrustKeyPath = commonFlags.TrustKey
 
 	registryService := registry.NewService(cli.registryOptions)
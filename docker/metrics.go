@@ -0,0 +1,35 @@
+// +build daemon
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/metrics"
+)
+
+// startMetricsServer serves the metrics of the daemon on /metrics at addr,
+// if it is not empty.
+func startMetricsServer(addr string) error {
+	if addr == "" {
+		return nil
+	}
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error listening on the metrics address %s: %v", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	go func() {
+		logrus.Infof("Metrics listen on %s", l.Addr())
+		if err := http.Serve(l, mux); err != nil {
+			logrus.Errorf("Error serving the metrics: %v", err)
+		}
+	}()
+	return nil
+}
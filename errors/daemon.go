@@ -787,6 +787,24 @@ var (
 		HTTPStatusCode: http.StatusInternalServerError,
 	})
 
+	// ErrorCodeExecNotRunning is generated when we try to kill an exec
+	// but its process is not running.
+	ErrorCodeExecNotRunning = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "EXECNOTRUNNING",
+		Message:        "Exec %s is not running",
+		Description:    "An attempt was made to kill an 'exec', but the 'exec' is not running",
+		HTTPStatusCode: http.StatusConflict,
+	})
+
+	// ErrorCodeNoExecKill is generated when we try to kill an exec
+	// but can't because we're on windows.
+	ErrorCodeNoExecKill = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "NOEXECKILL",
+		Message:        "Killing an exec is not supported on Windows",
+		Description:    "The process of an 'exec' cannot be signaled on Windows",
+		HTTPStatusCode: http.StatusInternalServerError,
+	})
+
 	// ErrorCodeDefaultName is generated when we try to delete the
 	// default name of a container.
 	ErrorCodeDefaultName = errcode.Register(errGroup, errcode.ErrorDescriptor{
@@ -984,4 +1002,41 @@ var (
 		Description:    "A container can only be connected to one network at the time",
 		HTTPStatusCode: http.StatusBadRequest,
 	})
+
+	// ErrorCodeNoSuchCheckpoint is generated when we look for a checkpoint
+	// of a container which does not exist.
+	ErrorCodeNoSuchCheckpoint = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "NOSUCHCHECKPOINT",
+		Message:        "No such checkpoint: %s",
+		Description:    "The specified checkpoint of the container can not be found",
+		HTTPStatusCode: http.StatusNotFound,
+	})
+
+	// ErrorCodeCheckpointExists is generated when we create a checkpoint
+	// with the name of an existing one.
+	ErrorCodeCheckpointExists = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "CHECKPOINTEXISTS",
+		Message:        "Checkpoint %s already exists",
+		Description:    "A checkpoint of the container with the same name already exists",
+		HTTPStatusCode: http.StatusConflict,
+	})
+
+	// ErrorCodeInvalidCheckpointName is generated when the name of a
+	// checkpoint contains invalid characters.
+	ErrorCodeInvalidCheckpointName = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "INVALIDCHECKPOINTNAME",
+		Message:        "Invalid checkpoint name (%s), only %s are allowed",
+		Description:    "The name of the checkpoint contains invalid characters",
+		HTTPStatusCode: http.StatusBadRequest,
+	})
+
+	// ErrorCodeCheckpointNetworkMode is generated when we checkpoint a
+	// container whose network namespace cannot be restored, such as a
+	// container on the default bridge network.
+	ErrorCodeCheckpointNetworkMode = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "CHECKPOINTNETWORKMODE",
+		Message:        "Cannot checkpoint container %s: only containers with the host or none network mode can be checkpointed, as the network namespace of a container on the bridge or another network is restored without its connection to the network",
+		Description:    "The network namespace of the container cannot be restored from a checkpoint, as it would not be connected to the networks set up by the daemon, such as the default bridge",
+		HTTPStatusCode: http.StatusBadRequest,
+	})
 )
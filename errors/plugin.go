@@ -0,0 +1,75 @@
+package errors
+
+// This file contains all of the errors that can be generated from the
+// docker/plugin component.
+
+import (
+	"net/http"
+
+	"github.com/docker/distribution/registry/api/errcode"
+)
+
+var (
+	// ErrorCodeNoSuchPlugin is generated when we look for a plugin by name
+	// or ID and we can't find it.
+	ErrorCodeNoSuchPlugin = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "NOSUCHPLUGIN",
+		Message:        "No such plugin: %s",
+		Description:    "The specified plugin can not be found",
+		HTTPStatusCode: http.StatusNotFound,
+	})
+
+	// ErrorCodePluginExists is generated when we install a plugin with the
+	// name of an installed one.
+	ErrorCodePluginExists = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "PLUGINEXISTS",
+		Message:        "Plugin %s is already installed",
+		Description:    "A plugin with the same name is already installed",
+		HTTPStatusCode: http.StatusConflict,
+	})
+
+	// ErrorCodeInvalidPlugin is generated when the manifest of a plugin
+	// cannot be used to run it.
+	ErrorCodeInvalidPlugin = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "INVALIDPLUGIN",
+		Message:        "Invalid plugin %s: %s",
+		Description:    "The manifest of the plugin is invalid",
+		HTTPStatusCode: http.StatusBadRequest,
+	})
+
+	// ErrorCodePluginPrivileges is generated when we install a plugin
+	// without granting the privileges it requests.
+	ErrorCodePluginPrivileges = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "PLUGINPRIVILEGES",
+		Message:        "Plugin %s requests privileges which were not granted",
+		Description:    "The privileges requested by the plugin must be granted to install it",
+		HTTPStatusCode: http.StatusForbidden,
+	})
+
+	// ErrorCodePluginEnabled is generated when we enable a plugin which is
+	// already enabled, or remove an enabled plugin without force.
+	ErrorCodePluginEnabled = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "PLUGINENABLED",
+		Message:        "Plugin %s is enabled",
+		Description:    "The operation is not allowed on an enabled plugin",
+		HTTPStatusCode: http.StatusConflict,
+	})
+
+	// ErrorCodePluginEnabling is generated when we enable, disable or
+	// remove a plugin which is being enabled.
+	ErrorCodePluginEnabling = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "PLUGINENABLING",
+		Message:        "Plugin %s is being enabled",
+		Description:    "The operation is not allowed while the plugin is being enabled",
+		HTTPStatusCode: http.StatusConflict,
+	})
+
+	// ErrorCodePluginDisabled is generated when we disable a plugin which is
+	// not enabled.
+	ErrorCodePluginDisabled = errcode.Register(errGroup, errcode.ErrorDescriptor{
+		Value:          "PLUGINDISABLED",
+		Message:        "Plugin %s is not enabled",
+		Description:    "The operation is not allowed on a disabled plugin",
+		HTTPStatusCode: http.StatusConflict,
+	})
+)
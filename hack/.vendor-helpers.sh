@@ -135,6 +135,17 @@ clean() {
 	echo done
 }
 
+# Apply a patch of hack/vendor-patches to a package, for the changes which are
+# not merged upstream yet
+apply_patch () {
+	local pkg="$1"
+	local patch="$2"
+	local target="vendor/src/$pkg"
+
+	echo "$pkg: applying $patch"
+	patch --quiet -p1 -d "$target" < "hack/vendor-patches/$patch"
+}
+
 # Fix up hard-coded imports that refer to Godeps paths so they'll work with our vendoring
 fix_rewritten_imports () {
        local pkg="$1"
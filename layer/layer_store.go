@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/distribution/digest"
@@ -211,7 +212,9 @@ func (ls *layerStore) applyTar(tx MetadataTransaction, ts io.Reader, parent stri
 		return err
 	}
 
+	start := time.Now()
 	applySize, err := ls.driver.ApplyDiff(layer.cacheID, parent, archive.Reader(rdr))
+	ls.observeDriverOperation("applydiff", start)
 	if err != nil {
 		return err
 	}
@@ -263,7 +266,10 @@ func (ls *layerStore) Register(ts io.Reader, parent ChainID) (Layer, error) {
 		references:     map[Layer]struct{}{},
 	}
 
-	if err = ls.driver.Create(layer.cacheID, pid, ""); err != nil {
+	start := time.Now()
+	err = ls.driver.Create(layer.cacheID, pid, "")
+	ls.observeDriverOperation("create", start)
+	if err != nil {
 		return nil, err
 	}
 
@@ -343,7 +349,9 @@ func (ls *layerStore) Get(l ChainID) (Layer, error) {
 }
 
 func (ls *layerStore) deleteLayer(layer *roLayer, metadata *Metadata) error {
+	start := time.Now()
 	err := ls.driver.Remove(layer.cacheID)
+	ls.observeDriverOperation("remove", start)
 	if err != nil {
 		return err
 	}
@@ -458,7 +466,10 @@ func (ls *layerStore) CreateRWLayer(name string, parent ChainID, mountLabel stri
 		m.initID = pid
 	}
 
-	if err = ls.driver.Create(m.mountID, pid, ""); err != nil {
+	start := time.Now()
+	err = ls.driver.Create(m.mountID, pid, "")
+	ls.observeDriverOperation("create", start)
+	if err != nil {
 		return nil, err
 	}
 
@@ -496,7 +507,10 @@ func (ls *layerStore) ReleaseRWLayer(l RWLayer) ([]Metadata, error) {
 		return []Metadata{}, nil
 	}
 
-	if err := ls.driver.Remove(m.mountID); err != nil {
+	start := time.Now()
+	err := ls.driver.Remove(m.mountID)
+	ls.observeDriverOperation("remove", start)
+	if err != nil {
 		logrus.Errorf("Error removing mounted layer %s: %s", m.name, err)
 		return nil, err
 	}
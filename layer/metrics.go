@@ -0,0 +1,15 @@
+package layer
+
+import (
+	"time"
+
+	"github.com/docker/docker/pkg/metrics"
+)
+
+var graphDriverOperationDuration = metrics.NewHistogram("engine_daemon_graphdriver_operation_duration_seconds", "Duration of the graph driver operations, by driver and operation.", metrics.DefaultBuckets, "driver", "operation")
+
+// observeDriverOperation records the duration of a graph driver operation
+// which started at start.
+func (ls *layerStore) observeDriverOperation(operation string, start time.Time) {
+	graphDriverOperationDuration.ObserveSince(start, ls.driver.String(), operation)
+}
@@ -3,6 +3,7 @@ package layer
 import (
 	"io"
 	"sync"
+	"time"
 
 	"github.com/docker/docker/pkg/archive"
 )
@@ -50,18 +51,22 @@ func (ml *mountedLayer) Parent() Layer {
 }
 
 func (ml *mountedLayer) Mount(mountLabel string) (string, error) {
+	defer ml.layerStore.observeDriverOperation("get", time.Now())
 	return ml.layerStore.driver.Get(ml.mountID, mountLabel)
 }
 
 func (ml *mountedLayer) Unmount() error {
+	defer ml.layerStore.observeDriverOperation("put", time.Now())
 	return ml.layerStore.driver.Put(ml.mountID)
 }
 
 func (ml *mountedLayer) Size() (int64, error) {
+	defer ml.layerStore.observeDriverOperation("diffsize", time.Now())
 	return ml.layerStore.driver.DiffSize(ml.mountID, ml.cacheParent())
 }
 
 func (ml *mountedLayer) Changes() ([]archive.Change, error) {
+	defer ml.layerStore.observeDriverOperation("changes", time.Now())
 	return ml.layerStore.driver.Changes(ml.mountID, ml.cacheParent())
 }
 
@@ -0,0 +1,154 @@
+// Package metrics provides counters and histograms labeled by a set of
+// dimensions. They are Prometheus metrics, exposed over HTTP by the
+// Prometheus client library.
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// DefaultBuckets are the default upper bounds of the buckets of a
+// histogram, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// DefaultRegistry is the registry of the metrics created by NewCounter and
+// NewHistogram, and served by Handler. It is the default registry of the
+// Prometheus client library, which also holds the metrics of the Go runtime
+// and of the process.
+var DefaultRegistry = &Registry{
+	registerer: prometheus.DefaultRegisterer,
+	gatherer:   prometheus.DefaultGatherer,
+}
+
+// Registry is a set of metrics.
+type Registry struct {
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	r := prometheus.NewRegistry()
+	return &Registry{registerer: r, gatherer: r}
+}
+
+// NewCounter registers and returns a counter with the label names labels.
+// It panics if a metric with the same name is already registered.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	r.registerer.MustRegister(c)
+	return &Counter{vec: c}
+}
+
+// NewHistogram registers and returns a histogram with the bucket upper
+// bounds buckets and the label names labels. It panics if a metric with the
+// same name is already registered.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: sorted}, labels)
+	r.registerer.MustRegister(h)
+	return &Histogram{vec: h}
+}
+
+// WriteTo writes the metrics of the registry in the Prometheus text format,
+// sorted by name.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	families, err := r.gatherer.Gather()
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, f := range families {
+		written, err := expfmt.MetricFamilyToText(w, f)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ServeHTTP serves the metrics of the registry, in the format negotiated
+// with the client.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{}).ServeHTTP(w, req)
+}
+
+// NewCounter registers and returns a counter of the default registry.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return DefaultRegistry.NewCounter(name, help, labels...)
+}
+
+// NewHistogram registers and returns a histogram of the default registry.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return DefaultRegistry.NewHistogram(name, help, buckets, labels...)
+}
+
+// Handler returns an http.Handler serving the metrics of the default
+// registry.
+func Handler() http.Handler {
+	return DefaultRegistry
+}
+
+// Counter is a metric which only goes up, such as a number of requests.
+type Counter struct {
+	vec *prometheus.CounterVec
+}
+
+// Inc increments the counter for the label values by 1.
+func (c *Counter) Inc(labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Inc()
+}
+
+// Add adds v, which must not be negative, to the counter for the label
+// values.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Add(v)
+}
+
+// Value returns the value of the counter for the label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	var m dto.Metric
+	if err := c.vec.WithLabelValues(labelValues...).Write(&m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
+// Histogram is a metric sampling observations, such as request durations,
+// in buckets.
+type Histogram struct {
+	vec *prometheus.HistogramVec
+}
+
+// Observe adds the observation v to the histogram for the label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(v)
+}
+
+// ObserveSince adds the number of seconds since start to the histogram for
+// the label values.
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Count returns the number of observations of the histogram for the label
+// values.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	var m dto.Metric
+	if err := h.vec.WithLabelValues(labelValues...).Write(&m); err != nil {
+		return 0
+	}
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_requests_total", "Number of requests.", "method")
+	c.Inc("GET")
+	c.Add(2, "GET")
+	c.Inc("POST")
+
+	if v := c.Value("GET"); v != 3 {
+		t.Fatalf("Expected 3 GET requests, got %v", v)
+	}
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP test_requests_total Number of requests.
+# TYPE test_requests_total counter
+test_requests_total{method="GET"} 3
+test_requests_total{method="POST"} 1
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	if v := c.Value("DELETE"); v != 0 {
+		t.Fatalf("Expected no DELETE request, got %v", v)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("test_duration_seconds", "Duration.", []float64{1, 0.5}, "op")
+	h.Observe(0.2, "pull")
+	h.Observe(0.7, "pull")
+	h.Observe(3, "pull")
+
+	if n := h.Count("pull"); n != 3 {
+		t.Fatalf("Expected 3 observations, got %d", n)
+	}
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{op="pull",le="0.5"} 1
+test_duration_seconds_bucket{op="pull",le="1"} 2
+test_duration_seconds_bucket{op="pull",le="+Inf"} 3
+test_duration_seconds_sum{op="pull"} 3.9
+test_duration_seconds_count{op="pull"} 3
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "Help with a \\ and a\nnewline.", "path")
+	c.Inc("/a\"b\\c")
+
+	var buf bytes.Buffer
+	r.WriteTo(&buf)
+	if !strings.Contains(buf.String(), `# HELP test_total Help with a \\ and a\nnewline.`) {
+		t.Fatalf("Expected an escaped help line, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `test_total{path="/a\"b\\c"} 1`) {
+		t.Fatalf("Expected an escaped label value, got:\n%s", buf.String())
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "Test.")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a panic registering a metric twice")
+		}
+	}()
+	r.NewCounter("test_total", "Test.")
+}
+
+func TestWrongLabelCount(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "Test.", "a", "b")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a panic with a wrong number of label values")
+		}
+	}()
+	c.Inc("a")
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "Test.").Inc()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4" {
+		t.Fatalf("Unexpected content type %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "test_total 1\n") {
+		t.Fatalf("Unexpected body:\n%s", w.Body.String())
+	}
+}
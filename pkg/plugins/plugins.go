@@ -24,6 +24,7 @@ package plugins
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -64,7 +65,11 @@ type Plugin struct {
 	Client *Client `json:"-"`
 	// Manifest of the plugin (see above)
 	Manifest *Manifest `json:"-"`
+	// BasePath is the host path of the root filesystem of a managed
+	// plugin, which the paths returned by the plugin are relative to
+	BasePath string `json:"-"`
 
+	managed      bool
 	activatErr   error
 	activateOnce sync.Once
 }
@@ -182,9 +187,71 @@ func Get(name, imp string) (*Plugin, error) {
 	return nil, ErrNotImplements
 }
 
-// Handle adds the specified function to the extpointHandlers.
+// Handle adds the specified function to the extpointHandlers. The function
+// is also called for the managed plugins already registered which implement
+// iface.
 func Handle(iface string, fn func(string, *Client)) {
 	extpointHandlers[iface] = fn
+
+	for _, pl := range managedPlugins() {
+		if pl.activate() == nil && pl.implements(iface) {
+			fn(pl.Name, pl.Client)
+		}
+	}
+}
+
+// Register adds a plugin managed by the daemon, listening on addr, and
+// activates it. basePath is the host path of the root filesystem of the
+// plugin. A managed plugin is found by name before the plugin directories
+// are looked up.
+func Register(name, addr, basePath string) (*Plugin, error) {
+	pl := newLocalPlugin(name, addr)
+	pl.BasePath = basePath
+	pl.managed = true
+
+	storage.Lock()
+	if _, exists := storage.plugins[name]; exists {
+		storage.Unlock()
+		return nil, fmt.Errorf("plugin %s is already registered", name)
+	}
+	storage.plugins[name] = pl
+	storage.Unlock()
+
+	if err := pl.activate(); err != nil {
+		Unregister(name)
+		return nil, err
+	}
+	return pl, nil
+}
+
+// Unregister removes a plugin previously added with Register.
+func Unregister(name string) {
+	storage.Lock()
+	if pl, ok := storage.plugins[name]; ok && pl.managed {
+		delete(storage.plugins, name)
+	}
+	storage.Unlock()
+}
+
+func isManaged(managed []*Plugin, name string) bool {
+	for _, pl := range managed {
+		if pl.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func managedPlugins() []*Plugin {
+	var out []*Plugin
+	storage.Lock()
+	for _, pl := range storage.plugins {
+		if pl.managed {
+			out = append(out, pl)
+		}
+	}
+	storage.Unlock()
+	return out
 }
 
 // GetAll returns all the plugins for the specified implementation
@@ -199,6 +266,16 @@ func GetAll(imp string) ([]*Plugin, error) {
 		err error
 	}
 
+	// The managed plugins are not looked up in the plugin directories
+	managed := managedPlugins()
+	var names []string
+	for _, name := range pluginNames {
+		if !isManaged(managed, name) {
+			names = append(names, name)
+		}
+	}
+	pluginNames = names
+
 	chPl := make(chan plLoad, len(pluginNames))
 	for _, name := range pluginNames {
 		go func(name string) {
@@ -208,6 +285,11 @@ func GetAll(imp string) ([]*Plugin, error) {
 	}
 
 	var out []*Plugin
+	for _, pl := range managed {
+		if pl.activate() == nil && pl.implements(imp) {
+			out = append(out, pl)
+		}
+	}
 	for i := 0; i < len(pluginNames); i++ {
 		pl := <-chPl
 		if pl.err != nil {
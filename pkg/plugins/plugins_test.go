@@ -0,0 +1,62 @@
+package plugins
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestRegisterManagedPlugin(t *testing.T) {
+	addr := setupRemotePluginServer()
+	defer teardownRemotePluginServer()
+
+	mux.HandleFunc("/Plugin.Activate", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", versionMimetype)
+		json.NewEncoder(w).Encode(Manifest{Implements: []string{"ManagedTestDriver"}})
+	})
+
+	if _, err := Register("managed-test", addr, "/var/lib/docker/plugins/id/rootfs"); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("managed-test")
+	if _, err := Register("managed-test", addr, ""); err == nil {
+		t.Fatal("Expected an error registering a plugin twice")
+	}
+
+	pl, err := Get("managed-test", "ManagedTestDriver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pl.BasePath != "/var/lib/docker/plugins/id/rootfs" {
+		t.Fatalf("Expected the base path of the plugin to be kept, got %q", pl.BasePath)
+	}
+	if _, err := Get("managed-test", "VolumeDriver"); err != ErrNotImplements {
+		t.Fatalf("Expected ErrNotImplements, got %v", err)
+	}
+
+	defer delete(extpointHandlers, "ManagedTestDriver")
+	var handled []string
+	Handle("ManagedTestDriver", func(name string, c *Client) {
+		handled = append(handled, name)
+	})
+	if len(handled) != 1 || handled[0] != "managed-test" {
+		t.Fatalf("Expected the handler to be called for the registered plugin, got %v", handled)
+	}
+
+	all, err := GetAll("ManagedTestDriver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0] != pl {
+		t.Fatalf("Expected the registered plugin to be listed, got %v", all)
+	}
+
+	Unregister("managed-test")
+	all, err = GetAll("ManagedTestDriver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 0 {
+		t.Fatalf("Expected no plugin once unregistered, got %v", all)
+	}
+}
@@ -0,0 +1,508 @@
+// Package plugin manages the plugins installed in the daemon. A plugin is
+// pulled from a registry, along with a manifest describing how to run it, and
+// runs in a container managed by the daemon, with the privileges granted when
+// it is installed. Once enabled, a plugin is registered in pkg/plugins, where
+// the volume, authorization, network and graphdriver subsystems look plugins
+// up.
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/distribution"
+	derr "github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+const stateFile = "plugins.json"
+
+// errPluginStopped is returned by enable when the plugin is stopped by
+// Shutdown while it is activated.
+var errPluginStopped = errors.New("the plugin was stopped while being enabled")
+
+// plugin is an installed plugin, and the process running it once enabled.
+// enabling is set while the plugin is activated, with the lock of the manager
+// released.
+type plugin struct {
+	types.Plugin
+	process  *pluginProcess
+	enabling bool
+}
+
+// reference returns the reference the plugin was pulled with.
+func (p *plugin) reference() string {
+	if strings.Contains(p.Tag, ":") {
+		return p.Name + "@" + p.Tag
+	}
+	return p.Name + ":" + p.Tag
+}
+
+// key returns the name the plugin is registered with in pkg/plugins. The
+// default tag is omitted, so that a plugin is referred to by its name.
+func (p *plugin) key() string {
+	if p.Tag == reference.DefaultTag {
+		return p.Name
+	}
+	return p.reference()
+}
+
+// Manager installs plugins, and runs the enabled ones.
+type Manager struct {
+	mu              sync.Mutex
+	root            string
+	runRoot         string
+	registryService *registry.Service
+	plugins         map[string]*plugin
+}
+
+// NewManager returns a manager storing the plugins in root, and their
+// sockets in runRoot. The state of the plugins stored in root is loaded.
+func NewManager(root, runRoot string, registryService *registry.Service) (*Manager, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(runRoot, 0700); err != nil {
+		return nil, err
+	}
+	m := &Manager{
+		root:            root,
+		runRoot:         runRoot,
+		registryService: registryService,
+		plugins:         make(map[string]*plugin),
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(root, stateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	var state map[string]*types.Plugin
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("Error loading the plugins state: %v", err)
+	}
+	for id, p := range state {
+		m.plugins[id] = &plugin{Plugin: *p}
+	}
+	return m, nil
+}
+
+// Init starts the plugins which were enabled when the daemon stopped.
+func (m *Manager) Init() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var active []*plugin
+	for _, p := range m.plugins {
+		if p.Active {
+			active = append(active, p)
+		}
+	}
+	for _, p := range active {
+		// The lock is released while a plugin is activated, so the
+		// plugin may have been disabled or removed meanwhile.
+		if !p.Active || p.process != nil || m.plugins[p.ID] != p {
+			continue
+		}
+		if err := m.enable(p); err != nil {
+			logrus.Errorf("Error enabling plugin %s: %v", p.key(), err)
+			if err != errPluginStopped {
+				p.Active = false
+			}
+		}
+	}
+	if err := m.save(); err != nil {
+		logrus.Errorf("Error saving the plugins state: %v", err)
+	}
+}
+
+// Shutdown stops the enabled plugins. They stay enabled, and start again
+// when the manager is initialized.
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range m.plugins {
+		if p.process == nil {
+			continue
+		}
+		if err := m.disable(p); err != nil {
+			logrus.Errorf("Error stopping plugin %s: %v", p.key(), err)
+		}
+	}
+}
+
+// Privileges returns the privileges requested by the plugin referenced by
+// name in a registry, which are granted when the plugin is pulled.
+func (m *Manager) Privileges(name string, metaHeaders map[string][]string, authConfig *types.AuthConfig) (types.PluginPrivileges, error) {
+	ref, err := parseReference(name)
+	if err != nil {
+		return nil, err
+	}
+	config := &distribution.PluginPullConfig{
+		MetaHeaders:     metaHeaders,
+		AuthConfig:      authConfig,
+		RegistryService: m.registryService,
+	}
+	manifestJSON, err := distribution.PullPluginConfig(context.Background(), ref, config)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest types.PluginManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return nil, derr.ErrorCodeInvalidPlugin.WithArgs(name, err)
+	}
+	if err := validateManifest(&manifest); err != nil {
+		return nil, derr.ErrorCodeInvalidPlugin.WithArgs(name, err)
+	}
+	return pluginPrivileges(&manifest), nil
+}
+
+// Pull installs the plugin referenced by name from a registry, granting it
+// privileges, which must be the ones the plugin requests. The plugin is
+// installed disabled.
+func (m *Manager) Pull(name string, metaHeaders map[string][]string, authConfig *types.AuthConfig, privileges types.PluginPrivileges) (*types.Plugin, error) {
+	ref, err := parseReference(name)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if _, err := m.get(ref.String()); err == nil {
+		m.mu.Unlock()
+		return nil, derr.ErrorCodePluginExists.WithArgs(name)
+	}
+	m.mu.Unlock()
+
+	id := stringid.GenerateNonCryptoID()
+	dir := filepath.Join(m.root, id)
+	p := &plugin{Plugin: types.Plugin{ID: id, Name: ref.Name()}}
+	switch r := ref.(type) {
+	case reference.Canonical:
+		p.Tag = r.Digest().String()
+	case reference.NamedTagged:
+		p.Tag = r.Tag()
+	}
+
+	config := &distribution.PluginPullConfig{
+		MetaHeaders:     metaHeaders,
+		AuthConfig:      authConfig,
+		RegistryService: m.registryService,
+		ValidateConfig: func(manifestJSON []byte) error {
+			if err := json.Unmarshal(manifestJSON, &p.Manifest); err != nil {
+				return derr.ErrorCodeInvalidPlugin.WithArgs(name, err)
+			}
+			if err := validateManifest(&p.Manifest); err != nil {
+				return derr.ErrorCodeInvalidPlugin.WithArgs(name, err)
+			}
+			if !samePrivileges(pluginPrivileges(&p.Manifest), privileges) {
+				return derr.ErrorCodePluginPrivileges.WithArgs(name)
+			}
+			return nil
+		},
+	}
+	if _, err := distribution.PullPlugin(context.Background(), ref, config, filepath.Join(dir, "rootfs")); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.get(ref.String()); err == nil {
+		os.RemoveAll(dir)
+		return nil, derr.ErrorCodePluginExists.WithArgs(name)
+	}
+	m.plugins[id] = p
+	if err := m.save(); err != nil {
+		delete(m.plugins, id)
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	pl := p.Plugin
+	return &pl, nil
+}
+
+// List returns the installed plugins, sorted by name.
+func (m *Manager) List() []*types.Plugin {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := []*types.Plugin{}
+	for _, p := range m.plugins {
+		pl := p.Plugin
+		out = append(out, &pl)
+	}
+	sort.Sort(byName(out))
+	return out
+}
+
+// Inspect returns the plugin referenced by name or ID.
+func (m *Manager) Inspect(name string) (*types.Plugin, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.get(name)
+	if err != nil {
+		return nil, err
+	}
+	pl := p.Plugin
+	return &pl, nil
+}
+
+// Enable starts the plugin referenced by name or ID, and registers it in
+// pkg/plugins.
+func (m *Manager) Enable(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	if p.enabling {
+		return derr.ErrorCodePluginEnabling.WithArgs(name)
+	}
+	if p.Active {
+		return derr.ErrorCodePluginEnabled.WithArgs(name)
+	}
+	if err := m.enable(p); err != nil {
+		return err
+	}
+	p.Active = true
+	return m.save()
+}
+
+// Disable unregisters the plugin referenced by name or ID, and stops it.
+func (m *Manager) Disable(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	if p.enabling {
+		return derr.ErrorCodePluginEnabling.WithArgs(name)
+	}
+	if !p.Active {
+		return derr.ErrorCodePluginDisabled.WithArgs(name)
+	}
+	if err := m.disable(p); err != nil {
+		return err
+	}
+	p.Active = false
+	return m.save()
+}
+
+// Remove removes the plugin referenced by name or ID. An enabled plugin is
+// only removed if force is set, after being disabled.
+func (m *Manager) Remove(name string, force bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	if p.enabling {
+		return derr.ErrorCodePluginEnabling.WithArgs(name)
+	}
+	if p.Active {
+		if !force {
+			return derr.ErrorCodePluginEnabled.WithArgs(name)
+		}
+		if err := m.disable(p); err != nil {
+			return err
+		}
+		p.Active = false
+	}
+
+	delete(m.plugins, p.ID)
+	if err := m.save(); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(m.root, p.ID)); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(m.runRoot, p.ID))
+}
+
+// enable starts the plugin p, and registers it in pkg/plugins once it answers
+// on its socket. The caller must hold the lock. As the activation of the
+// plugin is retried until it answers, the lock is released meanwhile, with p
+// marked as enabling.
+func (m *Manager) enable(p *plugin) error {
+	if err := m.start(p); err != nil {
+		return err
+	}
+
+	p.enabling = true
+	m.mu.Unlock()
+	err := m.activate(p)
+	m.mu.Lock()
+	p.enabling = false
+
+	if p.process == nil {
+		plugins.Unregister(p.key())
+		return errPluginStopped
+	}
+	if err != nil {
+		if stopErr := m.stop(p); stopErr != nil {
+			logrus.Errorf("Error stopping plugin %s: %v", p.key(), stopErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// disable unregisters the plugin p from pkg/plugins, and stops it.
+func (m *Manager) disable(p *plugin) error {
+	plugins.Unregister(p.key())
+	if p.process == nil {
+		return nil
+	}
+	return m.stop(p)
+}
+
+// get returns the plugin referenced by name, or with the ID name. The caller
+// must hold the lock.
+func (m *Manager) get(name string) (*plugin, error) {
+	if p, ok := m.plugins[name]; ok {
+		return p, nil
+	}
+	if ref, err := parseReference(name); err == nil {
+		for _, p := range m.plugins {
+			if p.reference() == ref.String() {
+				return p, nil
+			}
+		}
+	}
+	return nil, derr.ErrorCodeNoSuchPlugin.WithArgs(name)
+}
+
+// save writes the state of the plugins to disk. The caller must hold the
+// lock.
+func (m *Manager) save() error {
+	state := make(map[string]*types.Plugin, len(m.plugins))
+	for id, p := range m.plugins {
+		pl := p.Plugin
+		state[id] = &pl
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(m.root, stateFile)
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// parseReference parses a plugin reference, with the default tag if none is
+// given.
+func parseReference(name string) (reference.Named, error) {
+	ref, err := reference.ParseNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	return reference.WithDefaultTag(ref), nil
+}
+
+// validateManifest checks that the plugin described by manifest can be run.
+func validateManifest(manifest *types.PluginManifest) error {
+	if len(manifest.Entrypoint) == 0 || !filepath.IsAbs(manifest.Entrypoint[0]) {
+		return fmt.Errorf("the entrypoint must be an absolute path")
+	}
+	if manifest.Workdir != "" && !filepath.IsAbs(manifest.Workdir) {
+		return fmt.Errorf("the working directory must be an absolute path")
+	}
+	socket := manifest.Interface.Socket
+	if socket == "" || socket != filepath.Base(socket) || socket == "." || socket == ".." {
+		return fmt.Errorf("invalid socket name %q", socket)
+	}
+	if len(manifest.Interface.Types) == 0 {
+		return fmt.Errorf("the plugin does not implement any interface")
+	}
+	if t := manifest.Network.Type; t != "" && t != "host" {
+		return fmt.Errorf("invalid network type %q", t)
+	}
+	if err := validateCapabilities(manifest.Capabilities); err != nil {
+		return err
+	}
+	if manifest.PropagatedMount != "" && !filepath.IsAbs(manifest.PropagatedMount) {
+		return fmt.Errorf("the propagated mount must be an absolute path")
+	}
+	return nil
+}
+
+// pluginPrivileges returns the privileges requested by the plugin described
+// by manifest.
+func pluginPrivileges(manifest *types.PluginManifest) types.PluginPrivileges {
+	privileges := types.PluginPrivileges{}
+	if manifest.Network.Type == "host" {
+		privileges = append(privileges, types.PluginPrivilege{
+			Name:        "network",
+			Description: "permission to access the network of the host",
+			Value:       []string{manifest.Network.Type},
+		})
+	}
+	if len(manifest.Capabilities) > 0 {
+		privileges = append(privileges, types.PluginPrivilege{
+			Name:        "capabilities",
+			Description: "permission to use the capabilities",
+			Value:       manifest.Capabilities,
+		})
+	}
+	if manifest.PropagatedMount != "" {
+		privileges = append(privileges, types.PluginPrivilege{
+			Name:        "mount",
+			Description: "permission to propagate the mounts of the directory to the host",
+			Value:       []string{manifest.PropagatedMount},
+		})
+	}
+	return privileges
+}
+
+// samePrivileges returns whether the privileges requested and granted are
+// the same.
+func samePrivileges(requested, granted types.PluginPrivileges) bool {
+	if len(requested) != len(granted) {
+		return false
+	}
+	for i := range requested {
+		if !reflect.DeepEqual(requested[i], granted[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+type byName []*types.Plugin
+
+func (r byName) Len() int      { return len(r) }
+func (r byName) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byName) Less(i, j int) bool {
+	if r[i].Name == r[j].Name {
+		return r[i].Tag < r[j].Tag
+	}
+	return r[i].Name < r[j].Name
+}
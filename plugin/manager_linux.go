@@ -0,0 +1,340 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/execdriver"
+	"github.com/docker/docker/daemon/execdriver/native/template"
+	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/docker/docker/pkg/symlink"
+	aaprofile "github.com/docker/docker/profiles/apparmor"
+	"github.com/docker/docker/profiles/seccomp"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/apparmor"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// pluginSocketDir is the directory of the root filesystem of a plugin where
+// the plugin creates its socket.
+const pluginSocketDir = "/run/docker/plugins"
+
+// stopTimeout is the time a plugin is given to exit before being killed.
+const stopTimeout = 10 * time.Second
+
+// pluginInitName is the name the daemon is re-executed with to initialize
+// the container of a plugin.
+const pluginInitName = "docker-plugin-init"
+
+const defaultApparmorProfile = "docker-default"
+
+func init() {
+	reexec.Register(pluginInitName, pluginInit)
+}
+
+// pluginInit runs in the namespaces of a plugin, and executes its entrypoint.
+func pluginInit() {
+	runtime.GOMAXPROCS(1)
+	runtime.LockOSThread()
+	factory, err := libcontainer.New("")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := factory.StartInitialization(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	panic("unreachable")
+}
+
+// pluginProcess is the process running an enabled plugin, in its container.
+type pluginProcess struct {
+	container libcontainer.Container
+	process   *libcontainer.Process
+	exited    chan struct{}
+	// propagatedMount is the host path of the propagated mount of the
+	// plugin, if any
+	propagatedMount string
+}
+
+// start starts the plugin p in a container, whose root filesystem is the one
+// of the plugin. The socket directory of the plugin is bind mounted from the
+// run root of the manager, to keep the path of the socket short.
+func (m *Manager) start(p *plugin) error {
+	rootfs := filepath.Join(m.root, p.ID, "rootfs")
+	socketDir := filepath.Join(m.runRoot, p.ID)
+	if err := os.MkdirAll(socketDir, 0700); err != nil {
+		return err
+	}
+	socket := filepath.Join(socketDir, p.Manifest.Interface.Socket)
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// The mounts the plugin makes in its propagated mount are propagated
+	// to the host, where they are found in the root filesystem of the
+	// plugin.
+	var propagatedMount string
+	if p.Manifest.PropagatedMount != "" {
+		var err error
+		propagatedMount, err = symlink.FollowSymlinkInScope(filepath.Join(rootfs, p.Manifest.PropagatedMount), rootfs)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(propagatedMount, 0755); err != nil {
+			return err
+		}
+		// The propagated mount is left mounted if the daemon died
+		// while the plugin was running. It is made private before
+		// being shared, to get a peer group of its own.
+		if err := unmountPropagatedMount(propagatedMount); err != nil {
+			return err
+		}
+		if err := mount.MakePrivate(propagatedMount); err != nil {
+			return err
+		}
+		if err := mount.MakeRShared(propagatedMount); err != nil {
+			return err
+		}
+	}
+
+	process, err := startPlugin(p, m.runRoot, rootfs, socketDir, propagatedMount)
+	if err != nil {
+		if propagatedMount != "" {
+			unmountPropagatedMount(propagatedMount)
+		}
+		return err
+	}
+	process.propagatedMount = propagatedMount
+	p.process = process
+	return nil
+}
+
+// activate registers the started plugin p in pkg/plugins, once it answers on
+// its socket. It is called without the lock held.
+func (m *Manager) activate(p *plugin) error {
+	socket := filepath.Join(m.runRoot, p.ID, p.Manifest.Interface.Socket)
+	_, err := plugins.Register(p.key(), "unix://"+socket, filepath.Join(m.root, p.ID, "rootfs"))
+	return err
+}
+
+// stop stops the process of the plugin p, killing it if it does not exit
+// within stopTimeout, and destroys its container.
+func (m *Manager) stop(p *plugin) error {
+	process := p.process
+
+	// The signals fail if the plugin already exited, which is handled
+	// below.
+	process.process.Signal(syscall.SIGTERM)
+	select {
+	case <-process.exited:
+	case <-time.After(stopTimeout):
+		logrus.Warnf("Plugin %s did not exit in %s, killing it", p.key(), stopTimeout)
+		process.process.Signal(syscall.SIGKILL)
+		<-process.exited
+	}
+
+	p.process = nil
+	err := process.container.Destroy()
+	if process.propagatedMount != "" {
+		if uerr := unmountPropagatedMount(process.propagatedMount); err == nil {
+			err = uerr
+		}
+	}
+	return err
+}
+
+// unmountPropagatedMount detaches the propagated mount of a plugin from the
+// host, along with the mounts the plugin made in it, such as its volumes.
+func unmountPropagatedMount(path string) error {
+	for {
+		mounted, err := mount.Mounted(path)
+		if err != nil || !mounted {
+			return err
+		}
+		if err := syscall.Unmount(path, syscall.MNT_DETACH); err != nil {
+			return err
+		}
+	}
+}
+
+// validateCapabilities checks that the capabilities requested by a plugin
+// are known.
+func validateCapabilities(capabilities []string) error {
+	_, err := execdriver.TweakCapabilities(nil, capabilities, nil)
+	return err
+}
+
+// pluginConfig returns the configuration of the container of the plugin p.
+// The plugin runs in its own namespaces, with the default capabilities of a
+// container and the ones it was granted when it was installed. It shares the
+// network of the host only if it was granted to.
+func pluginConfig(p *plugin, rootfs, socketDir, propagatedMount string) (*configs.Config, error) {
+	manifest := p.Manifest
+	config := template.New()
+	config.Rootfs = rootfs
+	config.Namespaces.Remove(configs.NEWUSER)
+	if manifest.Network.Type == "host" {
+		config.Namespaces.Remove(configs.NEWNET)
+	} else {
+		config.Networks = []*configs.Network{{Type: "loopback"}}
+	}
+	config.Cgroups.Name = p.ID
+	config.Cgroups.Parent = "/docker/plugins"
+	config.Cgroups.Resources.AllowedDevices = configs.DefaultAllowedDevices
+	config.Devices = configs.DefaultAutoCreatedDevices
+	config.NoPivotRoot = os.Getenv("DOCKER_RAMDISK") != ""
+
+	capabilities, err := execdriver.TweakCapabilities(config.Capabilities, manifest.Capabilities, nil)
+	if err != nil {
+		return nil, err
+	}
+	config.Capabilities = nil
+	sysAdmin := false
+	for _, c := range capabilities {
+		c = strings.ToUpper(c)
+		if !strings.HasPrefix(c, "CAP_") {
+			c = "CAP_" + c
+		}
+		if c == "CAP_SYS_ADMIN" {
+			sysAdmin = true
+		}
+		config.Capabilities = append(config.Capabilities, c)
+	}
+	// Plugins are granted CAP_SYS_ADMIN to mount filesystems, which the
+	// default profiles deny, as for privileged containers.
+	if sysAdmin {
+		if apparmor.IsEnabled() {
+			config.AppArmorProfile = "unconfined"
+		}
+	} else {
+		config.Seccomp = seccomp.GetDefaultProfile()
+	}
+
+	config.Mounts = append(config.Mounts, &configs.Mount{
+		Source:      socketDir,
+		Destination: pluginSocketDir,
+		Device:      "bind",
+		Flags:       syscall.MS_BIND | syscall.MS_REC,
+	})
+	execdriver.SetRootPropagation(config, mount.RPRIVATE)
+	if propagatedMount != "" {
+		config.Mounts = append(config.Mounts, &configs.Mount{
+			Source:           propagatedMount,
+			Destination:      manifest.PropagatedMount,
+			Device:           "bind",
+			Flags:            syscall.MS_BIND | syscall.MS_REC,
+			PropagationFlags: []int{mount.RSHARED},
+		})
+		execdriver.SetRootPropagation(config, mount.SHARED)
+	}
+	return config, nil
+}
+
+// startPlugin runs the entrypoint of the plugin p in a container created in
+// the libcontainer directory of runRoot. The output of the plugin is logged
+// by the daemon.
+func startPlugin(p *plugin, runRoot, rootfs, socketDir, propagatedMount string) (*pluginProcess, error) {
+	config, err := pluginConfig(p, rootfs, socketDir, propagatedMount)
+	if err != nil {
+		return nil, err
+	}
+	if config.AppArmorProfile == defaultApparmorProfile {
+		// The plugins start before the execution driver, which
+		// installs the profile for the containers.
+		if err := aaprofile.IsLoaded(defaultApparmorProfile); err != nil {
+			if err := aaprofile.InstallDefault(defaultApparmorProfile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	factory, err := libcontainer.New(filepath.Join(runRoot, "libcontainer"), libcontainer.InitPath(reexec.Self(), pluginInitName))
+	if err != nil {
+		return nil, err
+	}
+	if err := destroyStaleContainer(factory, p.ID); err != nil {
+		return nil, err
+	}
+	container, err := factory.Create(p.ID, config)
+	if err != nil {
+		return nil, err
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		container.Destroy()
+		return nil, err
+	}
+	manifest := p.Manifest
+	process := &libcontainer.Process{
+		Args:   manifest.Entrypoint,
+		Env:    manifest.Env,
+		Cwd:    manifest.Workdir,
+		Stdout: w,
+		Stderr: w,
+	}
+	if process.Cwd == "" {
+		process.Cwd = "/"
+	}
+	err = container.Start(process)
+	w.Close()
+	if err != nil {
+		r.Close()
+		container.Destroy()
+		return nil, err
+	}
+
+	log := logrus.WithField("plugin", p.key())
+	go func() {
+		defer r.Close()
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			log.Info(scanner.Text())
+		}
+	}()
+
+	pp := &pluginProcess{container: container, process: process, exited: make(chan struct{})}
+	go func() {
+		_, err := process.Wait()
+		log.Debugf("Plugin exited: %v", err)
+		close(pp.exited)
+	}()
+	return pp, nil
+}
+
+// destroyStaleContainer destroys the container left by the plugin id if the
+// daemon died while it was running, killing the plugin if it still runs.
+func destroyStaleContainer(factory libcontainer.Factory, id string) error {
+	container, err := factory.Load(id)
+	if err != nil {
+		if lerr, ok := err.(libcontainer.Error); ok && lerr.Code() == libcontainer.ContainerNotExists {
+			return nil
+		}
+		return err
+	}
+	if status, err := container.Status(); err == nil && status != libcontainer.Destroyed {
+		logrus.Warnf("Killing the process of plugin %s left by the previous daemon", id)
+		if err := container.Signal(syscall.SIGKILL); err != nil {
+			return err
+		}
+		for i := 0; i < 10; i++ {
+			if status, err := container.Status(); err != nil || status == libcontainer.Destroyed {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	return container.Destroy()
+}
@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/profiles/seccomp"
+	"github.com/docker/engine-api/types"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+func hasCapability(config *configs.Config, capability string) bool {
+	for _, c := range config.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPluginConfig(t *testing.T) {
+	p := &plugin{Plugin: types.Plugin{
+		ID: "1234",
+		Manifest: types.PluginManifest{
+			Entrypoint: []string{"/usr/bin/sshfs-plugin"},
+			Interface:  types.PluginInterface{Types: []string{"VolumeDriver"}, Socket: "sshfs.sock"},
+		},
+	}}
+
+	config, err := pluginConfig(p, "/plugins/1234/rootfs", "/run/plugins/1234", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Rootfs != "/plugins/1234/rootfs" {
+		t.Fatalf("Expected the root filesystem of the plugin, got %s", config.Rootfs)
+	}
+	for _, ns := range []configs.NamespaceType{configs.NEWNS, configs.NEWPID, configs.NEWNET, configs.NEWIPC, configs.NEWUTS} {
+		if !config.Namespaces.Contains(ns) {
+			t.Fatalf("Expected the plugin to run in its own %s namespace", ns)
+		}
+	}
+	if config.Namespaces.Contains(configs.NEWUSER) {
+		t.Fatal("Expected the plugin not to run in a user namespace")
+	}
+	if !hasCapability(config, "CAP_CHOWN") || hasCapability(config, "CAP_SYS_ADMIN") {
+		t.Fatalf("Expected the default capabilities, got %v", config.Capabilities)
+	}
+	if config.Seccomp != seccomp.GetDefaultProfile() {
+		t.Fatal("Expected the default seccomp profile")
+	}
+	found := false
+	for _, m := range config.Mounts {
+		if m.Destination == pluginSocketDir && m.Source == "/run/plugins/1234" && m.Device == "bind" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected the socket directory to be bind mounted, got %v", config.Mounts)
+	}
+	if config.RootPropagation != mount.RPRIVATE {
+		t.Fatalf("Expected the mounts of the plugin to be private, got %d", config.RootPropagation)
+	}
+
+	p.Manifest.Network.Type = "host"
+	p.Manifest.Capabilities = []string{"sys_admin"}
+	p.Manifest.PropagatedMount = "/mnt"
+	config, err = pluginConfig(p, "/plugins/1234/rootfs", "/run/plugins/1234", "/plugins/1234/rootfs/mnt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Namespaces.Contains(configs.NEWNET) {
+		t.Fatal("Expected the plugin to run in the network namespace of the host")
+	}
+	if !hasCapability(config, "CAP_CHOWN") || !hasCapability(config, "CAP_SYS_ADMIN") {
+		t.Fatalf("Expected the capabilities of the manifest to be added, got %v", config.Capabilities)
+	}
+	if config.Seccomp != nil {
+		t.Fatal("Expected no seccomp profile for a plugin granted CAP_SYS_ADMIN")
+	}
+	found = false
+	for _, m := range config.Mounts {
+		if m.Destination == "/mnt" && m.Source == "/plugins/1234/rootfs/mnt" && len(m.PropagationFlags) == 1 && m.PropagationFlags[0] == mount.RSHARED {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected the propagated mount to be shared, got %v", config.Mounts)
+	}
+	if config.RootPropagation != mount.SHARED {
+		t.Fatalf("Expected the root of the plugin to be shared, got %d", config.RootPropagation)
+	}
+
+	p.Manifest.Capabilities = []string{"NOT_A_CAPABILITY"}
+	if _, err := pluginConfig(p, "/plugins/1234/rootfs", "/run/plugins/1234", ""); err == nil {
+		t.Fatal("Expected an error for an unknown capability")
+	}
+}
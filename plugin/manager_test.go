@@ -0,0 +1,157 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestManagerStore(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "plugin-manager-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	m, err := NewManager(filepath.Join(tmp, "plugins"), filepath.Join(tmp, "run"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.plugins["1234"] = &plugin{Plugin: types.Plugin{ID: "1234", Name: "tiborvass/no-remove", Tag: "latest"}}
+	m.plugins["5678"] = &plugin{Plugin: types.Plugin{ID: "5678", Name: "tiborvass/no-remove", Tag: "1.0"}}
+	if err := os.MkdirAll(filepath.Join(m.root, "1234", "rootfs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = NewManager(m.root, m.runRoot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := m.List(); len(l) != 2 || l[0].ID != "5678" || l[1].ID != "1234" {
+		t.Fatalf("Expected the plugins to be loaded sorted by name, got %v", l)
+	}
+
+	for name, id := range map[string]string{
+		"tiborvass/no-remove":                     "1234",
+		"tiborvass/no-remove:latest":              "1234",
+		"docker.io/tiborvass/no-remove:1.0":       "5678",
+		"5678":                                    "5678",
+		"index.docker.io/tiborvass/no-remove:1.0": "5678",
+	} {
+		p, err := m.Inspect(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.ID != id {
+			t.Fatalf("Expected %s to be plugin %s, got %s", name, id, p.ID)
+		}
+	}
+	if _, err := m.Inspect("tiborvass/no-remove:2.0"); err == nil {
+		t.Fatal("Expected an error inspecting a missing plugin")
+	}
+	if key := m.plugins["1234"].key(); key != "tiborvass/no-remove" {
+		t.Fatalf("Expected the default tag to be omitted from the key, got %s", key)
+	}
+	if key := m.plugins["5678"].key(); key != "tiborvass/no-remove:1.0" {
+		t.Fatalf("Expected the tag in the key, got %s", key)
+	}
+
+	m.plugins["1234"].enabling = true
+	if err := m.Enable("tiborvass/no-remove"); err == nil {
+		t.Fatal("Expected an error enabling a plugin being enabled")
+	}
+	if err := m.Disable("tiborvass/no-remove"); err == nil {
+		t.Fatal("Expected an error disabling a plugin being enabled")
+	}
+	if err := m.Remove("tiborvass/no-remove", true); err == nil {
+		t.Fatal("Expected an error removing a plugin being enabled")
+	}
+	m.plugins["1234"].enabling = false
+
+	m.plugins["1234"].Active = true
+	if err := m.Remove("tiborvass/no-remove", false); err == nil {
+		t.Fatal("Expected an error removing an enabled plugin without force")
+	}
+	m.plugins["1234"].Active = false
+	if err := m.Remove("tiborvass/no-remove", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(m.root, "1234")); !os.IsNotExist(err) {
+		t.Fatalf("Expected the plugin directory to be removed, got %v", err)
+	}
+	m, err = NewManager(m.root, m.runRoot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := m.List(); len(l) != 1 || l[0].ID != "5678" {
+		t.Fatalf("Expected the removal to be saved, got %v", l)
+	}
+}
+
+func TestValidateManifest(t *testing.T) {
+	valid := types.PluginManifest{
+		Entrypoint: []string{"/usr/bin/sshfs-plugin"},
+		Interface:  types.PluginInterface{Types: []string{"VolumeDriver"}, Socket: "sshfs.sock"},
+	}
+	if err := validateManifest(&valid); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fn := range []func(m *types.PluginManifest){
+		func(m *types.PluginManifest) { m.Entrypoint = nil },
+		func(m *types.PluginManifest) { m.Entrypoint = []string{"sshfs-plugin"} },
+		func(m *types.PluginManifest) { m.Workdir = "tmp" },
+		func(m *types.PluginManifest) { m.Interface.Socket = "" },
+		func(m *types.PluginManifest) { m.Interface.Socket = "../sshfs.sock" },
+		func(m *types.PluginManifest) { m.Interface.Types = nil },
+		func(m *types.PluginManifest) { m.Network.Type = "bridge" },
+		func(m *types.PluginManifest) { m.PropagatedMount = "mnt" },
+	} {
+		manifest := valid
+		fn(&manifest)
+		if err := validateManifest(&manifest); err == nil {
+			t.Fatalf("Expected an error for the manifest %+v", manifest)
+		}
+	}
+}
+
+func TestPluginPrivileges(t *testing.T) {
+	manifest := types.PluginManifest{
+		Entrypoint: []string{"/usr/bin/sshfs-plugin"},
+		Interface:  types.PluginInterface{Types: []string{"VolumeDriver"}, Socket: "sshfs.sock"},
+	}
+	if privileges := pluginPrivileges(&manifest); len(privileges) != 0 {
+		t.Fatalf("Expected no privileges, got %v", privileges)
+	}
+	if !samePrivileges(pluginPrivileges(&manifest), nil) {
+		t.Fatal("Expected a plugin requesting no privileges to be installed without granting any")
+	}
+
+	manifest.Network.Type = "host"
+	manifest.Capabilities = []string{"SYS_ADMIN"}
+	manifest.PropagatedMount = "/mnt"
+	privileges := pluginPrivileges(&manifest)
+	if len(privileges) != 3 ||
+		privileges[0].Name != "network" || privileges[0].Value[0] != "host" ||
+		privileges[1].Name != "capabilities" || privileges[1].Value[0] != "SYS_ADMIN" ||
+		privileges[2].Name != "mount" || privileges[2].Value[0] != "/mnt" {
+		t.Fatalf("Expected the network, capabilities and mount privileges, got %v", privileges)
+	}
+	if samePrivileges(privileges, nil) || samePrivileges(privileges, privileges[:2]) {
+		t.Fatal("Expected the privileges not to be granted")
+	}
+	granted := append(types.PluginPrivileges{}, privileges...)
+	granted[1].Value = []string{"SYS_ADMIN", "NET_ADMIN"}
+	if samePrivileges(privileges, granted) {
+		t.Fatal("Expected other capabilities not to match")
+	}
+	if !samePrivileges(privileges, pluginPrivileges(&manifest)) {
+		t.Fatal("Expected the requested privileges to be granted")
+	}
+}
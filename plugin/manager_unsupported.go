@@ -0,0 +1,24 @@
+// +build !linux
+
+package plugin
+
+import "fmt"
+
+// pluginProcess is the process running an enabled plugin.
+type pluginProcess struct{}
+
+func validateCapabilities(capabilities []string) error {
+	return nil
+}
+
+func (m *Manager) start(p *plugin) error {
+	return fmt.Errorf("Plugins are not supported on this platform")
+}
+
+func (m *Manager) activate(p *plugin) error {
+	return nil
+}
+
+func (m *Manager) stop(p *plugin) error {
+	return nil
+}
@@ -61,5 +61,25 @@ func Compare(a, b *container.Config) bool {
 			return false
 		}
 	}
+	return compareHealthConfig(a.Healthcheck, b.Healthcheck)
+}
+
+// compareHealthConfig returns true if both healthchecks are unset or
+// describe the same probe.
+func compareHealthConfig(a, b *container.HealthConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if len(a.Test) != len(b.Test) ||
+		a.Interval != b.Interval ||
+		a.Timeout != b.Timeout ||
+		a.Retries != b.Retries {
+		return false
+	}
+	for i := 0; i < len(a.Test); i++ {
+		if a.Test[i] != b.Test[i] {
+			return false
+		}
+	}
 	return true
 }
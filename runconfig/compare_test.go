@@ -43,6 +43,9 @@ func TestCompare(t *testing.T) {
 	labels1 := map[string]string{"LABEL1": "value1", "LABEL2": "value2"}
 	labels2 := map[string]string{"LABEL1": "value1", "LABEL2": "value3"}
 	labels3 := map[string]string{"LABEL1": "value1", "LABEL2": "value2", "LABEL3": "value3"}
+	health1 := &container.HealthConfig{Test: []string{"CMD", "true"}}
+	health2 := &container.HealthConfig{Test: []string{"CMD", "false"}}
+	health3 := &container.HealthConfig{Test: []string{"CMD", "true"}, Retries: 5}
 
 	sameConfigs := map[*container.Config]*container.Config{
 		// Empty config
@@ -73,6 +76,8 @@ func TestCompare(t *testing.T) {
 		&container.Config{Entrypoint: entrypoint1}: {Entrypoint: entrypoint1},
 		// only volumes
 		&container.Config{Volumes: volumes1}: {Volumes: volumes1},
+		// only healthcheck
+		&container.Config{Healthcheck: health1}: {Healthcheck: health1},
 	}
 	differentConfigs := map[*container.Config]*container.Config{
 		nil: nil,
@@ -112,6 +117,10 @@ func TestCompare(t *testing.T) {
 		&container.Config{Volumes: volumes1}: {Volumes: volumes2},
 		// not the same number of labels
 		&container.Config{Volumes: volumes1}: {Volumes: volumes3},
+		// only healthcheck
+		&container.Config{Healthcheck: health1}: {Healthcheck: health2},
+		&container.Config{Healthcheck: health1}: {Healthcheck: health3},
+		&container.Config{Healthcheck: health1}: {},
 	}
 	for config1, config2 := range sameConfigs {
 		if !Compare(config1, config2) {
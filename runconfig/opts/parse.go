@@ -93,6 +93,11 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 		flStopSignal        = cmd.String([]string{"-stop-signal"}, signal.DefaultStopSignal, fmt.Sprintf("Signal to stop a container, %v by default", signal.DefaultStopSignal))
 		flIsolation         = cmd.String([]string{"-isolation"}, "", "Container isolation level")
 		flShmSize           = cmd.String([]string{"-shm-size"}, "", "Size of /dev/shm, default value is 64MB")
+		flHealthCmd         = cmd.String([]string{"-health-cmd"}, "", "Command to run to check health")
+		flHealthInterval    = cmd.Duration([]string{"-health-interval"}, 0, "Time between running the check")
+		flHealthTimeout     = cmd.Duration([]string{"-health-timeout"}, 0, "Maximum time to allow one check to run")
+		flHealthRetries     = cmd.Int([]string{"-health-retries"}, 0, "Consecutive failures needed to report unhealthy")
+		flNoHealthcheck     = cmd.Bool([]string{"-no-healthcheck"}, false, "Disable any container-specified HEALTHCHECK")
 	)
 
 	cmd.Var(&flAttach, []string{"a", "-attach"}, "Attach to STDIN, STDOUT or STDERR")
@@ -353,6 +358,40 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 		Devices:              deviceMappings,
 	}
 
+	// Healthcheck
+	var healthConfig *container.HealthConfig
+	haveHealthSettings := *flHealthCmd != "" ||
+		*flHealthInterval != 0 ||
+		*flHealthTimeout != 0 ||
+		*flHealthRetries != 0
+	if *flNoHealthcheck {
+		if haveHealthSettings {
+			return nil, nil, nil, cmd, fmt.Errorf("--no-healthcheck conflicts with --health-* options")
+		}
+		healthConfig = &container.HealthConfig{Test: []string{"NONE"}}
+	} else if haveHealthSettings {
+		var probe []string
+		if *flHealthCmd != "" {
+			probe = []string{"CMD-SHELL", *flHealthCmd}
+		}
+		if *flHealthInterval < 0 {
+			return nil, nil, nil, cmd, fmt.Errorf("--health-interval cannot be negative")
+		}
+		if *flHealthTimeout < 0 {
+			return nil, nil, nil, cmd, fmt.Errorf("--health-timeout cannot be negative")
+		}
+		if *flHealthRetries < 0 {
+			return nil, nil, nil, cmd, fmt.Errorf("--health-retries cannot be negative")
+		}
+
+		healthConfig = &container.HealthConfig{
+			Test:     probe,
+			Interval: *flHealthInterval,
+			Timeout:  *flHealthTimeout,
+			Retries:  *flHealthRetries,
+		}
+	}
+
 	config := &container.Config{
 		Hostname:     hostname,
 		Domainname:   domainname,
@@ -376,6 +415,7 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 		WorkingDir:      *flWorkingDir,
 		Labels:          ConvertKVStringsToMap(labels),
 		StopSignal:      *flStopSignal,
+		Healthcheck:     healthConfig,
 	}
 
 	hostConfig := &container.HostConfig{
@@ -1,13 +1,16 @@
 package volumedrivers
 
 import (
+	"path/filepath"
+
 	"github.com/docker/docker/pkg/plugins"
 	"github.com/docker/docker/volume"
 )
 
 type volumeDriverAdapter struct {
-	name  string
-	proxy *volumeDriverProxy
+	name         string
+	baseHostPath string
+	proxy        *volumeDriverProxy
 }
 
 func (a *volumeDriverAdapter) Name() string {
@@ -20,9 +23,10 @@ func (a *volumeDriverAdapter) Create(name string, opts map[string]string) (volum
 		return nil, err
 	}
 	return &volumeAdapter{
-		proxy:      a.proxy,
-		name:       name,
-		driverName: a.name}, nil
+		proxy:        a.proxy,
+		name:         name,
+		driverName:   a.name,
+		baseHostPath: a.baseHostPath}, nil
 }
 
 func (a *volumeDriverAdapter) Remove(v volume.Volume) error {
@@ -38,10 +42,11 @@ func (a *volumeDriverAdapter) List() ([]volume.Volume, error) {
 	var out []volume.Volume
 	for _, vp := range ls {
 		out = append(out, &volumeAdapter{
-			proxy:      a.proxy,
-			name:       vp.Name,
-			driverName: a.name,
-			eMount:     vp.Mountpoint,
+			proxy:        a.proxy,
+			name:         vp.Name,
+			driverName:   a.name,
+			baseHostPath: a.baseHostPath,
+			eMount:       hostPath(a.baseHostPath, vp.Mountpoint),
 		})
 	}
 	return out, nil
@@ -58,23 +63,28 @@ func (a *volumeDriverAdapter) Get(name string) (volume.Volume, error) {
 	}
 
 	return &volumeAdapter{
-		proxy:      a.proxy,
-		name:       v.Name,
-		driverName: a.Name(),
-		eMount:     v.Mountpoint,
+		proxy:        a.proxy,
+		name:         v.Name,
+		driverName:   a.Name(),
+		baseHostPath: a.baseHostPath,
+		eMount:       hostPath(a.baseHostPath, v.Mountpoint),
+		status:       v.Status,
 	}, nil
 }
 
 type volumeAdapter struct {
-	proxy      *volumeDriverProxy
-	name       string
-	driverName string
-	eMount     string // ephemeral host volume path
+	proxy        *volumeDriverProxy
+	name         string
+	driverName   string
+	baseHostPath string
+	eMount       string // ephemeral host volume path
+	status       map[string]interface{}
 }
 
 type proxyVolume struct {
 	Name       string
 	Mountpoint string
+	Status     map[string]interface{}
 }
 
 func (a *volumeAdapter) Name() string {
@@ -90,15 +100,35 @@ func (a *volumeAdapter) Path() string {
 		return a.eMount
 	}
 	m, _ := a.proxy.Path(a.name)
-	return m
+	return hostPath(a.baseHostPath, m)
 }
 
 func (a *volumeAdapter) Mount() (string, error) {
-	var err error
-	a.eMount, err = a.proxy.Mount(a.name)
+	m, err := a.proxy.Mount(a.name)
+	a.eMount = hostPath(a.baseHostPath, m)
 	return a.eMount, err
 }
 
 func (a *volumeAdapter) Unmount() error {
 	return a.proxy.Unmount(a.name)
 }
+
+// Status returns the status reported by the plugin when the volume was last
+// retrieved from it.
+func (a *volumeAdapter) Status() map[string]interface{} {
+	out := make(map[string]interface{}, len(a.status))
+	for k, v := range a.status {
+		out[k] = v
+	}
+	return out
+}
+
+// hostPath returns the host path of the mountpoint returned by a plugin. The
+// mountpoints of a managed plugin are relative to its root filesystem,
+// baseHostPath.
+func hostPath(baseHostPath, mountpoint string) string {
+	if baseHostPath == "" || mountpoint == "" {
+		return mountpoint
+	}
+	return filepath.Join(baseHostPath, mountpoint)
+}
@@ -19,9 +19,10 @@ var drivers = &driverExtpoint{extensions: make(map[string]volume.Driver), driver
 const extName = "VolumeDriver"
 
 // NewVolumeDriver returns a driver has the given name mapped on the given client.
-func NewVolumeDriver(name string, c client) volume.Driver {
+// The mountpoints returned by the driver are relative to baseHostPath, if set.
+func NewVolumeDriver(name, baseHostPath string, c client) volume.Driver {
 	proxy := &volumeDriverProxy{c}
-	return &volumeDriverAdapter{name, proxy}
+	return &volumeDriverAdapter{name, baseHostPath, proxy}
 }
 
 type opts map[string]string
@@ -109,7 +110,7 @@ func Lookup(name string) (volume.Driver, error) {
 		return ext, nil
 	}
 
-	d := NewVolumeDriver(name, pl.Client)
+	d := NewVolumeDriver(name, pl.BasePath, pl.Client)
 	drivers.extensions[name] = d
 	return d, nil
 }
@@ -156,7 +157,7 @@ func GetAllDrivers() ([]volume.Driver, error) {
 			continue
 		}
 
-		ext = NewVolumeDriver(p.Name, p.Client)
+		ext = NewVolumeDriver(p.Name, p.BasePath, p.Client)
 		drivers.extensions[p.Name] = ext
 		ds = append(ds, ext)
 	}
@@ -4,6 +4,7 @@
 package local
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -11,8 +12,11 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/graphdriver/quota"
 	derr "github.com/docker/docker/errors"
 	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/utils"
 	"github.com/docker/docker/volume"
 )
@@ -23,6 +27,9 @@ import (
 const (
 	VolumeDataPathName = "_data"
 	volumesPathName    = "volumes"
+	// optsFileName is the name of the file the options of a volume are
+	// stored in, next to its data directory.
+	optsFileName = "opts.json"
 )
 
 var (
@@ -52,23 +59,87 @@ func New(scope string, rootUID, rootGID int) (*Root, error) {
 		rootGID: rootGID,
 	}
 
+	quotaCtl, err := quota.NewControl(rootDirectory)
+	if err != nil {
+		logrus.Debugf("size option of local volumes not supported: %v", err)
+	} else {
+		r.quotaCtl = quotaCtl
+	}
+
 	dirs, err := ioutil.ReadDir(rootDirectory)
 	if err != nil {
 		return nil, err
 	}
 
+	mountInfos, err := mount.GetMounts()
+	if err != nil {
+		logrus.Debugf("error looking up mounts for local volume cleanup: %v", err)
+	}
+
 	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+
 		name := filepath.Base(d.Name())
-		r.volumes[name] = &localVolume{
+		v := &localVolume{
 			driverName: r.Name(),
 			name:       name,
 			path:       r.DataPath(name),
 		}
+		r.volumes[name] = v
+
+		b, err := ioutil.ReadFile(filepath.Join(rootDirectory, name, optsFileName))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			continue
+		}
+		if err := json.Unmarshal(b, &v.opts); err != nil {
+			return nil, fmt.Errorf("error reading the options of volume %s: %v", name, err)
+		}
+		if v.hasQuota() {
+			v.quotaCtl = r.quotaCtl
+		}
+		if !v.needsMount() {
+			continue
+		}
+
+		// the volumes may still be mounted, either after an unclean
+		// shutdown or because they are used by containers which kept
+		// running, they are unmounted by UnmountUnused once their uses
+		// are known.
+		for _, info := range mountInfos {
+			if info.Mountpoint == v.path {
+				v.mounted = true
+				break
+			}
+		}
 	}
 
 	return r, nil
 }
 
+// UnmountUnused unmounts the filesystems of the volumes which are mounted but
+// not used, such as the ones left mounted by a previous run of the daemon.
+func (r *Root) UnmountUnused() {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, v := range r.volumes {
+		v.m.Lock()
+		if v.mounted && v.usedCount == 0 {
+			if err := mount.Unmount(v.path); err != nil {
+				logrus.Errorf("error unmounting volume %s: %v", v.name, err)
+			} else {
+				v.mounted = false
+			}
+		}
+		v.m.Unlock()
+	}
+}
+
 // Root implements the Driver interface for the volume package and
 // manages the creation/removal of volumes. It uses only standard vfs
 // commands to create/remove dirs within its provided scope.
@@ -79,6 +150,9 @@ type Root struct {
 	volumes map[string]*localVolume
 	rootUID int
 	rootGID int
+	// quotaCtl sets the quotas of the volumes with a size option. It is
+	// nil if the filesystem of path does not support project quotas.
+	quotaCtl *quota.Control
 }
 
 // List lists all the volumes
@@ -102,11 +176,16 @@ func (r *Root) Name() string {
 
 // Create creates a new volume.Volume with the provided name, creating
 // the underlying directory tree required for this volume in the
-// process.
-func (r *Root) Create(name string, _ map[string]string) (volume.Volume, error) {
+// process. The options, if any, describe a filesystem mounted on the data
+// directory of the volume while it is in use, or the size limit of the
+// volume, and are stored with it.
+func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error) {
 	if err := r.validateName(name); err != nil {
 		return nil, err
 	}
+	if err := validateOpts(opts); err != nil {
+		return nil, err
+	}
 
 	r.m.Lock()
 	defer r.m.Unlock()
@@ -117,17 +196,42 @@ func (r *Root) Create(name string, _ map[string]string) (volume.Volume, error) {
 	}
 
 	path := r.DataPath(name)
+	v = &localVolume{
+		driverName: r.Name(),
+		name:       name,
+		path:       path,
+	}
+	if len(opts) != 0 {
+		v.opts = newOptsConfig(opts)
+	}
+
+	// the quota is set on the directory of the volume before the data
+	// directory is created, so that the data directory inherits it.
+	if err := idtools.MkdirAllAs(filepath.Dir(path), 0755, r.rootUID, r.rootGID); err != nil {
+		return nil, err
+	}
+	if err := r.setQuota(v); err != nil {
+		os.RemoveAll(filepath.Dir(path))
+		return nil, fmt.Errorf("error setting the size of volume %s: %v", name, err)
+	}
 	if err := idtools.MkdirAllAs(path, 0755, r.rootUID, r.rootGID); err != nil {
 		if os.IsExist(err) {
 			return nil, fmt.Errorf("volume already exists under %s", filepath.Dir(path))
 		}
 		return nil, err
 	}
-	v = &localVolume{
-		driverName: r.Name(),
-		name:       name,
-		path:       path,
+
+	if v.opts != nil {
+		b, err := json.Marshal(v.opts)
+		if err == nil {
+			err = ioutil.WriteFile(filepath.Join(filepath.Dir(path), optsFileName), b, 0600)
+		}
+		if err != nil {
+			os.RemoveAll(filepath.Dir(path))
+			return nil, fmt.Errorf("error storing the options of volume %s: %v", name, err)
+		}
 	}
+
 	r.volumes[name] = v
 	return v, nil
 }
@@ -157,6 +261,13 @@ func (r *Root) Remove(v volume.Volume) error {
 		return fmt.Errorf("Unable to remove a directory of out the Docker root %s: %s", r.scope, realPath)
 	}
 
+	if lv.needsMount() {
+		// never remove the data of the filesystem mounted on the volume
+		if err := mount.Unmount(lv.path); err != nil {
+			return fmt.Errorf("error unmounting volume %s: %v", lv.name, err)
+		}
+	}
+
 	if err := removePath(realPath); err != nil {
 		return err
 	}
@@ -204,6 +315,13 @@ type localVolume struct {
 	path string
 	// driverName is the name of the driver that created the volume.
 	driverName string
+	// opts is the configuration of the filesystem mounted on path while
+	// the volume is in use, or of the size limit of the volume, if any.
+	opts *optsConfig
+	// mounted is whether the filesystem described by opts is mounted.
+	mounted bool
+	// quotaCtl reads the quota of the volumes with a size limit.
+	quotaCtl *quota.Control
 }
 
 // Name returns the name of the given Volume.
@@ -222,11 +340,57 @@ func (v *localVolume) Path() string {
 }
 
 // Mount implements the localVolume interface, returning the data location.
+// The filesystem described by the options of the volume, if any, is mounted
+// on the first use of the volume.
 func (v *localVolume) Mount() (string, error) {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if v.needsMount() {
+		if !v.mounted {
+			if err := v.mount(); err != nil {
+				return "", err
+			}
+			v.mounted = true
+		}
+		v.usedCount++
+	}
 	return v.path, nil
 }
 
-// Umount is for satisfying the localVolume interface and does not do anything in this driver.
+// Unmount releases a use of the volume. The filesystem described by the
+// options of the volume, if any, is unmounted when the volume is not used
+// anymore.
 func (v *localVolume) Unmount() error {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	if v.needsMount() && v.usedCount > 0 {
+		v.usedCount--
+		if v.usedCount == 0 && v.mounted {
+			if err := mount.Unmount(v.path); err != nil {
+				v.usedCount++
+				return fmt.Errorf("error unmounting volume %s: %v", v.name, err)
+			}
+			v.mounted = false
+		}
+	}
 	return nil
 }
+
+// Status returns the size limit and the disk usage, in bytes, of the
+// volumes with a size option.
+func (v *localVolume) Status() map[string]interface{} {
+	if v.quotaCtl == nil {
+		return nil
+	}
+	var q quota.Quota
+	if err := v.quotaCtl.GetQuota(filepath.Dir(v.path), &q); err != nil {
+		logrus.Debugf("error getting the quota of volume %s: %v", v.name, err)
+		return nil
+	}
+	return map[string]interface{}{
+		"Size": q.Size,
+		"Used": q.Used,
+	}
+}
@@ -0,0 +1,199 @@
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/pkg/mount"
+)
+
+func TestValidateOpts(t *testing.T) {
+	cases := map[string]struct {
+		opts  map[string]string
+		valid bool
+	}{
+		"none":      {nil, true},
+		"tmpfs":     {map[string]string{"type": "tmpfs", "device": "tmpfs", "o": "size=1m"}, true},
+		"nfs":       {map[string]string{"type": "nfs", "device": ":/export", "o": "addr=10.0.0.1,rw"}, true},
+		"bind":      {map[string]string{"device": "/srv/data", "o": "bind"}, true},
+		"unknown":   {map[string]string{"type": "tmpfs", "device": "tmpfs", "size": "1m"}, false},
+		"no device": {map[string]string{"type": "tmpfs"}, false},
+		"no type":   {map[string]string{"device": "/dev/sdb1"}, false},
+		"relative":  {map[string]string{"device": "data", "o": "bind"}, false},
+		"size":      {map[string]string{"size": "10g"}, true},
+		"bad size":  {map[string]string{"size": "ten"}, false},
+		"zero size": {map[string]string{"size": "0"}, false},
+		"size+type": {map[string]string{"size": "10g", "type": "tmpfs", "device": "tmpfs"}, false},
+	}
+	for name, c := range cases {
+		err := validateOpts(c.opts)
+		if c.valid && err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCreateWithOpts(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("root required")
+	}
+
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Create("invalid", map[string]string{"invalidopt": "1"}); err == nil {
+		t.Fatal("expected an error creating a volume with an invalid option")
+	}
+	if _, err := os.Stat(filepath.Join(rootDir, volumesPathName, "invalid")); !os.IsNotExist(err) {
+		t.Fatalf("expected no volume directory for an invalid volume, got %v", err)
+	}
+
+	opts := map[string]string{"type": "tmpfs", "device": "tmpfs", "o": "size=1m,uid=1000"}
+	v, err := r.Create("test", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vol := v.(*localVolume)
+
+	// the filesystem is mounted once, on the first use, and unmounted
+	// with the last one
+	for i := 0; i < 2; i++ {
+		dir, err := vol.Mount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dir != vol.Path() {
+			t.Fatalf("expected mount path %s, got %s", vol.Path(), dir)
+		}
+	}
+	if mounted, err := mount.Mounted(vol.Path()); err != nil || !mounted {
+		t.Fatalf("expected the volume to be mounted: %v", err)
+	}
+	mountInfos, err := mount.GetMounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range mountInfos {
+		if info.Mountpoint == vol.Path() {
+			if info.Fstype != "tmpfs" || info.Source != "tmpfs" {
+				t.Fatalf("expected a tmpfs mount, got %+v", info)
+			}
+		}
+	}
+
+	if err := vol.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+	if mounted, err := mount.Mounted(vol.Path()); err != nil || !mounted {
+		t.Fatalf("expected the volume to be mounted while still used: %v", err)
+	}
+	if err := vol.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+	if mounted, err := mount.Mounted(vol.Path()); err != nil || mounted {
+		t.Fatalf("expected the volume to be unmounted: %v", err)
+	}
+
+	// the options are restored with the volume, and a mount left behind
+	// is kept for the containers which may still use it
+	if _, err := vol.Mount(); err != nil {
+		t.Fatal(err)
+	}
+	r, err = New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mounted, err := mount.Mounted(vol.Path()); err != nil || !mounted {
+		t.Fatalf("expected the volume to be still mounted on restore: %v", err)
+	}
+	v, err = r.Get("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := v.(*localVolume)
+	if restored.opts == nil || *restored.opts != *vol.opts {
+		t.Fatalf("expected the options to be restored, got %v", restored.opts)
+	}
+
+	// a restored use of the volume keeps the mount, which is unmounted
+	// with the last use
+	if _, err := restored.Mount(); err != nil {
+		t.Fatal(err)
+	}
+	r.UnmountUnused()
+	if mounted, err := mount.Mounted(vol.Path()); err != nil || !mounted {
+		t.Fatalf("expected the used volume to be still mounted: %v", err)
+	}
+	if err := restored.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+	if mounted, err := mount.Mounted(vol.Path()); err != nil || mounted {
+		t.Fatalf("expected the volume to be unmounted after its last use: %v", err)
+	}
+
+	// a mount left behind which is not used is cleaned up
+	if _, err := vol.Mount(); err != nil {
+		t.Fatal(err)
+	}
+	r, err = New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.UnmountUnused()
+	if mounted, err := mount.Mounted(vol.Path()); err != nil || mounted {
+		t.Fatalf("expected the unused volume to be unmounted: %v", err)
+	}
+	v, err = r.Get("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored = v.(*localVolume)
+
+	if err := r.Remove(restored); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateWithSize(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := r.Create("sized", map[string]string{"size": "1m"})
+	if r.quotaCtl == nil {
+		if err == nil {
+			t.Fatal("Expected an error creating a volume with a size without quota support")
+		}
+		if _, err := os.Stat(filepath.Join(rootDir, volumesPathName, "sized")); !os.IsNotExist(err) {
+			t.Fatalf("Expected no volume directory for a volume which cannot be created, got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := v.Status()
+	if status["Size"] != uint64(1<<20) {
+		t.Fatalf("Expected a size of 1m in the status of the volume, got %v", status)
+	}
+}
@@ -6,11 +6,135 @@
 package local
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
+
+	"github.com/docker/docker/daemon/graphdriver/quota"
+	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/go-units"
+)
+
+var (
+	oldVfsDir = filepath.Join("vfs", "dir")
+
+	validOpts = map[string]bool{
+		"type":   true, // specify the filesystem type for mount, e.g. nfs
+		"o":      true, // generic mount options
+		"device": true, // device to mount from
+		"size":   true, // size limit of the volume, enforced with a quota
+	}
 )
 
-var oldVfsDir = filepath.Join("vfs", "dir")
+// optsConfig is the configuration of the filesystem mounted on a volume, or
+// of the size limit of the volume.
+type optsConfig struct {
+	MountType   string
+	MountOpts   string
+	MountDevice string
+	Size        uint64 `json:",omitempty"`
+}
+
+func (o *optsConfig) String() string {
+	return fmt.Sprintf("type='%s' device='%s' o='%s'", o.MountType, o.MountDevice, o.MountOpts)
+}
+
+// newOptsConfig returns the configuration of the filesystem or of the size
+// limit described by the options of a volume, which must be valid.
+func newOptsConfig(opts map[string]string) *optsConfig {
+	o := &optsConfig{
+		MountType:   opts["type"],
+		MountOpts:   opts["o"],
+		MountDevice: opts["device"],
+	}
+	if size, ok := opts["size"]; ok {
+		n, _ := units.RAMInBytes(size)
+		o.Size = uint64(n)
+	}
+	return o
+}
+
+// validateOpts checks the options of a volume at creation time, so that
+// invalid options are reported before the volume is used.
+func validateOpts(opts map[string]string) error {
+	if len(opts) == 0 {
+		return nil
+	}
+	for opt := range opts {
+		if !validOpts[opt] {
+			return fmt.Errorf("invalid option key: %q", opt)
+		}
+	}
+	if size, ok := opts["size"]; ok {
+		if len(opts) > 1 {
+			return fmt.Errorf("the size option cannot be combined with other options")
+		}
+		n, err := units.RAMInBytes(size)
+		if err != nil {
+			return fmt.Errorf("invalid size: %q", size)
+		}
+		if n <= 0 {
+			return fmt.Errorf("the size must be positive: %q", size)
+		}
+		return nil
+	}
+	if opts["device"] == "" {
+		return fmt.Errorf("missing device in volume options")
+	}
+	if opts["type"] == "" && !isBindOpts(opts["o"]) {
+		return fmt.Errorf("missing type in volume options")
+	}
+	if isBindOpts(opts["o"]) && !filepath.IsAbs(opts["device"]) {
+		return fmt.Errorf("the device of a bind mount must be an absolute path: %q", opts["device"])
+	}
+	return nil
+}
+
+// isBindOpts returns whether the mount options o describe a bind mount.
+func isBindOpts(o string) bool {
+	for _, opt := range strings.Split(o, ",") {
+		if opt == "bind" || opt == "rbind" {
+			return true
+		}
+	}
+	return false
+}
+
+// needsMount returns whether a filesystem is mounted on the data directory of
+// the volume while it is in use.
+func (v *localVolume) needsMount() bool {
+	return v.opts != nil && v.opts.MountDevice != ""
+}
+
+// hasQuota returns whether the size of the volume is limited.
+func (v *localVolume) hasQuota() bool {
+	return v.opts != nil && v.opts.Size > 0
+}
+
+// setQuota limits the size of the directory of the volume, if the options of
+// the volume have a size.
+func (r *Root) setQuota(v *localVolume) error {
+	if !v.hasQuota() {
+		return nil
+	}
+	if r.quotaCtl == nil {
+		return fmt.Errorf("the size option is only supported on xfs filesystems mounted with the pquota option")
+	}
+	if err := r.quotaCtl.SetQuota(filepath.Dir(v.path), quota.Quota{Size: v.opts.Size}); err != nil {
+		return err
+	}
+	v.quotaCtl = r.quotaCtl
+	return nil
+}
+
+// mount mounts the filesystem described by the options of the volume on its
+// data directory.
+func (v *localVolume) mount() error {
+	if err := mount.Mount(v.opts.MountDevice, v.path, v.opts.MountType, v.opts.MountOpts); err != nil {
+		return fmt.Errorf("error mounting volume %s with options %s: %v", v.name, v.opts, err)
+	}
+	return nil
+}
 
 // scopedPath verifies that the path where the volume is located
 // is under Docker's root and the valid local paths.
@@ -4,10 +4,46 @@
 package local
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 )
 
+// optsConfig is the configuration of the filesystem mounted on a volume,
+// which is not supported on Windows.
+type optsConfig struct{}
+
+// newOptsConfig returns nil, as mounting filesystems on volumes is not
+// supported on Windows.
+func newOptsConfig(opts map[string]string) *optsConfig {
+	return nil
+}
+
+// validateOpts checks the options of a volume at creation time. Options are
+// not supported on Windows.
+func validateOpts(opts map[string]string) error {
+	if len(opts) > 0 {
+		return fmt.Errorf("options are not supported on this platform")
+	}
+	return nil
+}
+
+func (v *localVolume) needsMount() bool {
+	return false
+}
+
+func (v *localVolume) hasQuota() bool {
+	return false
+}
+
+func (r *Root) setQuota(v *localVolume) error {
+	return nil
+}
+
+func (v *localVolume) mount() error {
+	return nil
+}
+
 // scopedPath verifies that the path where the volume is located
 // is under Docker's root and the valid local paths.
 func (r *Root) scopedPath(realPath string) bool {
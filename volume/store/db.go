@@ -0,0 +1,67 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/boltdb/bolt"
+)
+
+const (
+	volumeDataDir    = "volumes"
+	volumeBucketName = "volume"
+	volumeDBName     = "metadata.db"
+)
+
+// volumeMetadata is the metadata the store keeps for a volume, independently
+// of its driver.
+type volumeMetadata struct {
+	Name      string
+	Driver    string
+	Labels    map[string]string
+	Options   map[string]string
+	CreatedAt time.Time
+}
+
+// setMeta stores the metadata of the volume name in the database of the
+// store, if any.
+func (s *VolumeStore) setMeta(name string, meta volumeMetadata) error {
+	if s.db == nil {
+		return nil
+	}
+	b, err := json.Marshal(&meta)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(volumeBucketName)).Put([]byte(name), b)
+	})
+}
+
+// removeMeta removes the metadata of the volume name from the database of
+// the store, if any.
+func (s *VolumeStore) removeMeta(name string) error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(volumeBucketName)).Delete([]byte(name))
+	})
+}
+
+// listMeta returns the metadata of all the volumes of the database. The
+// entries which cannot be decoded are skipped.
+func listMeta(tx *bolt.Tx) []volumeMetadata {
+	var ls []volumeMetadata
+	tx.Bucket([]byte(volumeBucketName)).ForEach(func(k, v []byte) error {
+		var meta volumeMetadata
+		if err := json.Unmarshal(v, &meta); err != nil {
+			logrus.Warnf("Error reading the metadata of volume %s: %v", string(k), err)
+			return nil
+		}
+		ls = append(ls, meta)
+		return nil
+	})
+	return ls
+}
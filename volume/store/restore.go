@@ -0,0 +1,108 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/drivers"
+)
+
+// restore registers the volume described by meta, which was stored by a
+// previous run of the daemon. Its driver is not contacted, as plugins may not
+// be started yet.
+func (s *VolumeStore) restore(meta volumeMetadata) {
+	s.labels[meta.Name] = meta.Labels
+	s.options[meta.Name] = meta.Options
+	s.createdAt[meta.Name] = meta.CreatedAt
+	// the volumes stored by older daemons have no driver, they are stored
+	// again once found in a driver
+	if meta.Driver != "" {
+		s.names[meta.Name] = &restoredVolume{name: meta.Name, driverName: meta.Driver}
+	}
+}
+
+// restoredVolume is a volume restored from the metadata of the store. It is
+// looked up in its driver, which may be a plugin started after the daemon,
+// the first time it is used.
+type restoredVolume struct {
+	name       string
+	driverName string
+
+	mu sync.Mutex
+	v  volume.Volume
+}
+
+// get returns the volume of the driver.
+func (v *restoredVolume) get() (volume.Volume, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.v != nil {
+		return v.v, nil
+	}
+	vd, err := volumedrivers.GetDriver(v.driverName)
+	if err != nil {
+		return nil, err
+	}
+	vol, err := vd.Get(v.name)
+	if err != nil {
+		return nil, err
+	}
+	v.v = vol
+	return vol, nil
+}
+
+// Name returns the name of the volume.
+func (v *restoredVolume) Name() string {
+	return v.name
+}
+
+// DriverName returns the name of the driver of the volume.
+func (v *restoredVolume) DriverName() string {
+	return v.driverName
+}
+
+// cached returns the volume of the driver if it was already looked up. It
+// is used to describe the volume without waiting for its driver, which would
+// block the listing of the volumes while a plugin is missing.
+func (v *restoredVolume) cached() volume.Volume {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.v
+}
+
+// Path returns the path of the volume, or an empty string if it was not
+// looked up in its driver yet.
+func (v *restoredVolume) Path() string {
+	if vol := v.cached(); vol != nil {
+		return vol.Path()
+	}
+	return ""
+}
+
+// Mount mounts the volume.
+func (v *restoredVolume) Mount() (string, error) {
+	vol, err := v.get()
+	if err != nil {
+		return "", err
+	}
+	return vol.Mount()
+}
+
+// Unmount unmounts the volume.
+func (v *restoredVolume) Unmount() error {
+	vol, err := v.get()
+	if err != nil {
+		return err
+	}
+	return vol.Unmount()
+}
+
+// Status returns the status of the volume, or nil if it was not looked up in
+// its driver yet.
+func (v *restoredVolume) Status() map[string]interface{} {
+	if vol := v.cached(); vol != nil {
+		return vol.Status()
+	}
+	return nil
+}
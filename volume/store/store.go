@@ -1,9 +1,13 @@
 package store
 
 import (
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/boltdb/bolt"
 	"github.com/docker/docker/pkg/locker"
 	"github.com/docker/docker/volume"
 	"github.com/docker/docker/volume/drivers"
@@ -11,12 +15,55 @@ import (
 
 // New initializes a VolumeStore to keep
 // reference counting of volumes in the system.
-func New() *VolumeStore {
-	return &VolumeStore{
-		locks: &locker.Locker{},
-		names: make(map[string]volume.Volume),
-		refs:  make(map[string][]string),
+// The metadata of the volumes, such as their driver and labels, is stored in
+// a database under rootPath, or only kept in memory if rootPath is empty.
+// The volumes of the database are restored without contacting their drivers,
+// which are only looked up when the volumes are used.
+func New(rootPath string) (*VolumeStore, error) {
+	vs := &VolumeStore{
+		locks:     &locker.Locker{},
+		names:     make(map[string]volume.Volume),
+		refs:      make(map[string][]string),
+		labels:    make(map[string]map[string]string),
+		options:   make(map[string]map[string]string),
+		createdAt: make(map[string]time.Time),
 	}
+
+	if rootPath != "" {
+		volPath := filepath.Join(rootPath, volumeDataDir)
+		if err := os.MkdirAll(volPath, 0700); err != nil {
+			return nil, err
+		}
+
+		var err error
+		vs.db, err = bolt.Open(filepath.Join(volPath, volumeDBName), 0600, &bolt.Options{Timeout: 1 * time.Second})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := vs.db.Update(func(tx *bolt.Tx) error {
+			if _, err := tx.CreateBucketIfNotExists([]byte(volumeBucketName)); err != nil {
+				return err
+			}
+			for _, meta := range listMeta(tx) {
+				vs.restore(meta)
+			}
+			return nil
+		}); err != nil {
+			vs.db.Close()
+			return nil, err
+		}
+	}
+
+	return vs, nil
+}
+
+// Shutdown releases the database of the metadata of the volumes.
+func (s *VolumeStore) Shutdown() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
 }
 
 func (s *VolumeStore) getNamed(name string) (volume.Volume, bool) {
@@ -26,22 +73,112 @@ func (s *VolumeStore) getNamed(name string) (volume.Volume, bool) {
 	return v, exists
 }
 
+// setNamed stores the volume v under its name, with the reference ref if not
+// empty. The metadata of the volumes stored for the first time is persisted,
+// so that their names keep resolving to the same driver after a restart.
 func (s *VolumeStore) setNamed(v volume.Volume, ref string) {
+	name := v.Name()
+
 	s.globalLock.Lock()
-	s.names[v.Name()] = v
+	_, known := s.names[name]
+	s.names[name] = v
 	if len(ref) > 0 {
-		s.refs[v.Name()] = append(s.refs[v.Name()], ref)
+		s.refs[name] = append(s.refs[name], ref)
+	}
+	meta := volumeMetadata{
+		Name:      name,
+		Driver:    v.DriverName(),
+		Labels:    s.labels[name],
+		Options:   s.options[name],
+		CreatedAt: s.createdAt[name],
 	}
 	s.globalLock.Unlock()
+
+	if !known {
+		if err := s.setMeta(name, meta); err != nil {
+			logrus.Errorf("Error storing the metadata of volume %s: %v", name, err)
+		}
+	}
 }
 
 func (s *VolumeStore) purge(name string) {
 	s.globalLock.Lock()
 	delete(s.names, name)
 	delete(s.refs, name)
+	delete(s.labels, name)
+	delete(s.options, name)
+	delete(s.createdAt, name)
 	s.globalLock.Unlock()
 }
 
+// purgeStale removes the volume name, which was restored from the metadata
+// but no longer exists in its driver or whose driver is gone, from the store
+// and its database.
+func (s *VolumeStore) purgeStale(name string) {
+	logrus.Warnf("Volume %s no longer exists in its driver, removing it from the store", name)
+	s.purge(name)
+	if err := s.removeMeta(name); err != nil {
+		logrus.Errorf("Error removing the metadata of volume %s: %v", name, err)
+	}
+}
+
+// wrap returns the volume v with the labels, options and creation time
+// stored for it.
+func (s *VolumeStore) wrap(v volume.Volume) volume.Volume {
+	name := normaliseVolumeName(v.Name())
+	s.globalLock.Lock()
+	labels, options, createdAt := s.labels[name], s.options[name], s.createdAt[name]
+	s.globalLock.Unlock()
+	return volumeWrapper{Volume: v, labels: labels, options: options, createdAt: createdAt}
+}
+
+// volumeWrapper is a volume with the labels, options and creation time kept
+// by the store.
+type volumeWrapper struct {
+	volume.Volume
+	labels    map[string]string
+	options   map[string]string
+	createdAt time.Time
+}
+
+// Labels returns the labels of the volume.
+func (v volumeWrapper) Labels() map[string]string {
+	return v.labels
+}
+
+// Options returns the driver options the volume was created with.
+func (v volumeWrapper) Options() map[string]string {
+	return v.options
+}
+
+// CreatedAt returns the time the volume was created at, or a zero time if
+// it was not created through the store.
+func (v volumeWrapper) CreatedAt() time.Time {
+	return v.createdAt
+}
+
+// isRestored returns whether v is a volume restored from the metadata of the
+// store, which is replaced by the volume of its driver once listed.
+func isRestored(v volume.Volume) bool {
+	if vol, ok := v.(volumeWrapper); ok {
+		v = vol.Volume
+	}
+	_, ok := v.(*restoredVolume)
+	return ok
+}
+
+// unwrap returns the volume of the driver wrapped by v, if any, as drivers
+// expect their own volumes.
+func unwrap(v volume.Volume) (volume.Volume, error) {
+	if vol, ok := v.(volumeWrapper); ok {
+		v = vol.Volume
+	}
+	if vol, ok := v.(*restoredVolume); ok {
+		return vol.get()
+	}
+	return v, nil
+}
+
 // VolumeStore is a struct that stores the list of volumes available and keeps track of their usage counts
 type VolumeStore struct {
 	locks      *locker.Locker
@@ -51,6 +188,14 @@ type VolumeStore struct {
 	names map[string]volume.Volume
 	// refs stores the volume name and the list of things referencing it
 	refs map[string][]string
+	// labels stores the volume name and the labels it was created with
+	labels map[string]map[string]string
+	// options stores the volume name and the driver options it was created with
+	options map[string]map[string]string
+	// createdAt stores the volume name and the time it was created at
+	createdAt map[string]time.Time
+	// db stores the metadata of the volumes, so that it survives restarts
+	db *bolt.DB
 }
 
 // List proxies to all registered volume drivers to get the full list of volumes
@@ -68,7 +213,9 @@ func (s *VolumeStore) List() ([]volume.Volume, []string, error) {
 
 		s.locks.Lock(name)
 		storedV, exists := s.getNamed(name)
-		if !exists {
+		// replace the volumes restored from the metadata by the ones of
+		// their driver, once it is available
+		if _, restored := storedV.(*restoredVolume); !exists || restored && storedV.DriverName() == v.DriverName() {
 			s.setNamed(v, "")
 		}
 		if exists && storedV.DriverName() != v.DriverName() {
@@ -77,7 +224,7 @@ func (s *VolumeStore) List() ([]volume.Volume, []string, error) {
 			continue
 		}
 
-		out = append(out, v)
+		out = append(out, s.wrap(v))
 		s.locks.Unlock(v.Name())
 	}
 	return out, warnings, nil
@@ -124,52 +271,83 @@ func (s *VolumeStore) list() ([]volume.Volume, []string, error) {
 		ls = append(ls, vs.vols...)
 	}
 
-	if len(badDrivers) > 0 {
-		for _, v := range s.names {
-			if _, exists := badDrivers[v.DriverName()]; exists {
-				ls = append(ls, v)
+	// the volumes of the drivers which failed to list them, or which are not
+	// available yet, such as plugins which are not started, are listed from
+	// the store
+	listed := make(map[string]struct{}, len(drivers))
+	for _, d := range drivers {
+		listed[d.Name()] = struct{}{}
+	}
+	found := make(map[string]struct{}, len(ls))
+	for _, v := range ls {
+		found[normaliseVolumeName(v.Name())] = struct{}{}
+	}
+	var stale []string
+	s.globalLock.Lock()
+	for name, v := range s.names {
+		_, bad := badDrivers[v.DriverName()]
+		_, available := listed[v.DriverName()]
+		if bad || !available {
+			ls = append(ls, v)
+			continue
+		}
+		// the volumes restored from the metadata which their driver does
+		// not list anymore were removed while the daemon was stopped
+		if _, restored := v.(*restoredVolume); restored {
+			if _, exists := found[name]; !exists {
+				stale = append(stale, name)
 			}
 		}
 	}
+	s.globalLock.Unlock()
+
+	for _, name := range stale {
+		s.locks.Lock(name)
+		if v, exists := s.getNamed(name); exists && isRestored(v) {
+			s.purgeStale(name)
+		}
+		s.locks.Unlock(name)
+	}
 	return ls, warnings, nil
 }
 
 // CreateWithRef creates a volume with the given name and driver and stores the ref
 // This is just like Create() except we store the reference while holding the lock.
 // This ensures there's no race between creating a volume and then storing a reference.
-func (s *VolumeStore) CreateWithRef(name, driverName, ref string, opts map[string]string) (volume.Volume, error) {
+func (s *VolumeStore) CreateWithRef(name, driverName, ref string, opts, labels map[string]string) (volume.Volume, error) {
 	name = normaliseVolumeName(name)
 	s.locks.Lock(name)
 	defer s.locks.Unlock(name)
 
-	v, err := s.create(name, driverName, opts)
+	v, err := s.create(name, driverName, opts, labels)
 	if err != nil {
 		return nil, &OpErr{Err: err, Name: name, Op: "create"}
 	}
 
 	s.setNamed(v, ref)
-	return v, nil
+	return s.wrap(v), nil
 }
 
 // Create creates a volume with the given name and driver.
-func (s *VolumeStore) Create(name, driverName string, opts map[string]string) (volume.Volume, error) {
+func (s *VolumeStore) Create(name, driverName string, opts, labels map[string]string) (volume.Volume, error) {
 	name = normaliseVolumeName(name)
 	s.locks.Lock(name)
 	defer s.locks.Unlock(name)
 
-	v, err := s.create(name, driverName, opts)
+	v, err := s.create(name, driverName, opts, labels)
 	if err != nil {
 		return nil, &OpErr{Err: err, Name: name, Op: "create"}
 	}
 	s.setNamed(v, "")
-	return v, nil
+	return s.wrap(v), nil
 }
 
 // create asks the given driver to create a volume with the name/opts.
 // If a volume with the name is already known, it will ask the stored driver for the volume.
 // If the passed in driver name does not match the driver name which is stored for the given volume name, an error is returned.
+// The labels and options are only stored for the volumes which are actually created.
 // It is expected that callers of this function hold any neccessary locks.
-func (s *VolumeStore) create(name, driverName string, opts map[string]string) (volume.Volume, error) {
+func (s *VolumeStore) create(name, driverName string, opts, labels map[string]string) (volume.Volume, error) {
 	// Validate the name in a platform-specific manner
 	valid, err := volume.IsVolumeNameValid(name)
 	if err != nil {
@@ -180,10 +358,47 @@ func (s *VolumeStore) create(name, driverName string, opts map[string]string) (v
 	}
 
 	if v, exists := s.getNamed(name); exists {
-		if v.DriverName() != driverName && driverName != "" && driverName != volume.DefaultDriverName {
-			return nil, errNameConflict
+		conflict := v.DriverName() != driverName && driverName != "" && driverName != volume.DefaultDriverName
+		rv, ok := v.(*restoredVolume)
+		if !ok {
+			if conflict {
+				return nil, errNameConflict
+			}
+			return v, nil
+		}
+		vd, err := volumedrivers.GetDriver(rv.DriverName())
+		if err == nil {
+			if v, err := vd.Get(name); err == nil {
+				if conflict {
+					return nil, errNameConflict
+				}
+				return v, nil
+			}
+		} else if !conflict {
+			return nil, err
+		}
+
+		if conflict {
+			// the volume is gone with its driver, or was removed from it
+			// while the daemon was stopped, so the name is free to be used
+			// with another driver
+			s.purgeStale(name)
+		} else {
+			// the volume was removed from its driver while the daemon was
+			// stopped, create it again, by default with the same options
+			// and labels
+			logrus.Debugf("Volume %s not found in driver %s, creating it again", name, rv.DriverName())
+			s.globalLock.Lock()
+			if opts == nil {
+				opts = s.options[name]
+			}
+			if labels == nil {
+				labels = s.labels[name]
+			}
+			s.globalLock.Unlock()
+			s.purge(name)
+			driverName = rv.DriverName()
 		}
-		return v, nil
 	}
 
 	logrus.Debugf("Registering new volume reference: driver %s, name %s", driverName, name)
@@ -195,7 +410,18 @@ func (s *VolumeStore) create(name, driverName string, opts map[string]string) (v
 	if v, err := vd.Get(name); err == nil {
 		return v, nil
 	}
-	return vd.Create(name, opts)
+	v, err := vd.Create(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.globalLock.Lock()
+	s.labels[name] = labels
+	s.options[name] = opts
+	s.createdAt[name] = time.Now().UTC()
+	s.globalLock.Unlock()
+
+	return v, nil
 }
 
 // GetWithRef gets a volume with the given name from the passed in driver and stores the ref
@@ -217,7 +443,7 @@ func (s *VolumeStore) GetWithRef(name, driverName, ref string) (volume.Volume, e
 	}
 
 	s.setNamed(v, ref)
-	return v, nil
+	return s.wrap(v), nil
 }
 
 // Get looks if a volume with the given name exists and returns it if so
@@ -231,7 +457,7 @@ func (s *VolumeStore) Get(name string) (volume.Volume, error) {
 		return nil, &OpErr{Err: err, Name: name, Op: "get"}
 	}
 	s.setNamed(v, "")
-	return v, nil
+	return s.wrap(v), nil
 }
 
 // get requests the volume, if the driver info is stored it just access that driver,
@@ -242,9 +468,20 @@ func (s *VolumeStore) getVolume(name string) (volume.Volume, error) {
 	if v, exists := s.names[name]; exists {
 		vd, err := volumedrivers.GetDriver(v.DriverName())
 		if err != nil {
+			// the volumes restored from the metadata are still returned
+			// when their driver is gone, so that they can be removed
+			if isRestored(v) {
+				return v, nil
+			}
 			return nil, err
 		}
-		return vd.Get(name)
+		vol, err := vd.Get(name)
+		if err == nil || !isRestored(v) {
+			return vol, err
+		}
+		// the volume was removed from its driver while the daemon was
+		// stopped, it may still be found in another driver
+		s.purgeStale(name)
 	}
 
 	logrus.Debugf("Probing all drivers for volume with name: %s", name)
@@ -275,15 +512,32 @@ func (s *VolumeStore) Remove(v volume.Volume) error {
 
 	vd, err := volumedrivers.GetDriver(v.DriverName())
 	if err != nil {
-		return &OpErr{Err: err, Name: vd.Name(), Op: "remove"}
+		// only the entry of the store is left of a restored volume whose
+		// driver is gone
+		if isRestored(v) {
+			s.purgeStale(name)
+			return nil
+		}
+		return &OpErr{Err: err, Name: v.DriverName(), Op: "remove"}
+	}
+	vol, err := unwrap(v)
+	if err != nil {
+		if isRestored(v) {
+			s.purgeStale(name)
+			return nil
+		}
+		return &OpErr{Err: err, Name: name, Op: "remove"}
 	}
 
 	logrus.Debugf("Removing volume reference: driver %s, name %s", v.DriverName(), name)
-	if err := vd.Remove(v); err != nil {
+	if err := vd.Remove(vol); err != nil {
 		return &OpErr{Err: err, Name: name, Op: "remove"}
 	}
 
 	s.purge(name)
+	if err := s.removeMeta(name); err != nil {
+		logrus.Errorf("Error removing the metadata of volume %s: %v", name, err)
+	}
 	return nil
 }
 
@@ -333,6 +587,9 @@ func (s *VolumeStore) FilterByDriver(name string) ([]volume.Volume, error) {
 	if err != nil {
 		return nil, &OpErr{Err: err, Name: name, Op: "list"}
 	}
+	for i, v := range ls {
+		ls[i] = s.wrap(v)
+	}
 	return ls, nil
 }
 
@@ -2,9 +2,13 @@ package store
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/boltdb/bolt"
+	"github.com/docker/docker/volume"
 	"github.com/docker/docker/volume/drivers"
 	vt "github.com/docker/docker/volume/testutils"
 )
@@ -12,8 +16,11 @@ import (
 func TestCreate(t *testing.T) {
 	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
 	defer volumedrivers.Unregister("fake")
-	s := New()
-	v, err := s.Create("fake1", "fake", nil)
+	s, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := s.Create("fake1", "fake", nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -24,11 +31,11 @@ func TestCreate(t *testing.T) {
 		t.Fatalf("Expected 1 volume in the store, got %v: %v", len(l), l)
 	}
 
-	if _, err := s.Create("none", "none", nil); err == nil {
+	if _, err := s.Create("none", "none", nil, nil); err == nil {
 		t.Fatalf("Expected unknown driver error, got nil")
 	}
 
-	_, err = s.Create("fakeerror", "fake", map[string]string{"error": "create error"})
+	_, err = s.Create("fakeerror", "fake", map[string]string{"error": "create error"}, nil)
 	expected := &OpErr{Op: "create", Name: "fakeerror", Err: errors.New("create error")}
 	if err != nil && err.Error() != expected.Error() {
 		t.Fatalf("Expected create fakeError: create error, got %v", err)
@@ -40,7 +47,10 @@ func TestRemove(t *testing.T) {
 	volumedrivers.Register(vt.NewFakeDriver("noop"), "noop")
 	defer volumedrivers.Unregister("fake")
 	defer volumedrivers.Unregister("noop")
-	s := New()
+	s, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// doing string compare here since this error comes directly from the driver
 	expected := "no such volume"
@@ -48,7 +58,7 @@ func TestRemove(t *testing.T) {
 		t.Fatalf("Expected error %q, got %v", expected, err)
 	}
 
-	v, err := s.CreateWithRef("fake1", "fake", "fake", nil)
+	v, err := s.CreateWithRef("fake1", "fake", "fake", nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -71,11 +81,14 @@ func TestList(t *testing.T) {
 	defer volumedrivers.Unregister("fake")
 	defer volumedrivers.Unregister("fake2")
 
-	s := New()
-	if _, err := s.Create("test", "fake", nil); err != nil {
+	s, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("test", "fake", nil, nil); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := s.Create("test2", "fake2", nil); err != nil {
+	if _, err := s.Create("test2", "fake2", nil, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -88,7 +101,10 @@ func TestList(t *testing.T) {
 	}
 
 	// and again with a new store
-	s = New()
+	s, err = New("")
+	if err != nil {
+		t.Fatal(err)
+	}
 	ls, _, err = s.List()
 	if err != nil {
 		t.Fatal(err)
@@ -103,15 +119,18 @@ func TestFilterByDriver(t *testing.T) {
 	volumedrivers.Register(vt.NewFakeDriver("noop"), "noop")
 	defer volumedrivers.Unregister("fake")
 	defer volumedrivers.Unregister("noop")
-	s := New()
+	s, err := New("")
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	if _, err := s.Create("fake1", "fake", nil); err != nil {
+	if _, err := s.Create("fake1", "fake", nil, nil); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := s.Create("fake2", "fake", nil); err != nil {
+	if _, err := s.Create("fake2", "fake", nil, nil); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := s.Create("fake3", "noop", nil); err != nil {
+	if _, err := s.Create("fake3", "noop", nil, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -127,12 +146,17 @@ func TestFilterByDriver(t *testing.T) {
 func TestFilterByUsed(t *testing.T) {
 	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
 	volumedrivers.Register(vt.NewFakeDriver("noop"), "noop")
+	defer volumedrivers.Unregister("fake")
+	defer volumedrivers.Unregister("noop")
 
-	s := New()
-	if _, err := s.CreateWithRef("fake1", "fake", "volReference", nil); err != nil {
+	s, err := New("")
+	if err != nil {
 		t.Fatal(err)
 	}
-	if _, err := s.Create("fake2", "fake", nil); err != nil {
+	if _, err := s.CreateWithRef("fake1", "fake", "volReference", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("fake2", "fake", nil, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -157,3 +181,291 @@ func TestFilterByUsed(t *testing.T) {
 		t.Fatalf("expected used volume fake1, got %s", used[0].Name())
 	}
 }
+
+func TestLabels(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	defer volumedrivers.Unregister("fake")
+
+	dir, err := ioutil.TempDir("", "test-volume-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("labeled", "fake", nil, map[string]string{"team": "storage"}); err != nil {
+		t.Fatal(err)
+	}
+	// the labels of an existing volume are not changed
+	if _, err := s.Create("labeled", "fake", nil, map[string]string{"team": "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// and again with a new store
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+
+	v, err := s.Get("labeled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lv, ok := v.(volume.LabeledVolume)
+	if !ok || lv.Labels()["team"] != "storage" {
+		t.Fatalf("Expected the labels of the volume to be restored, got %v", v)
+	}
+	ls, _, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls) != 1 || ls[0].(volume.LabeledVolume).Labels()["team"] != "storage" {
+		t.Fatalf("Expected the labels of the listed volume, got %v", ls)
+	}
+
+	if err := s.Remove(v); err != nil {
+		t.Fatal(err)
+	}
+	v, err = s.Create("labeled", "fake", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if labels := v.(volume.LabeledVolume).Labels(); len(labels) != 0 {
+		t.Fatalf("Expected the labels to be removed with the volume, got %v", labels)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	driver := vt.NewFakeDriver("fake")
+	volumedrivers.Register(driver, "fake")
+	defer volumedrivers.Unregister("fake")
+
+	dir, err := ioutil.TempDir("", "test-volume-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := s.Create("restored", "fake", map[string]string{"size": "1g"}, map[string]string{"team": "storage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createdAt := v.(volume.TimedVolume).CreatedAt()
+	if createdAt.IsZero() {
+		t.Fatal("Expected the creation time of the volume to be set")
+	}
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the driver is not available yet when the store is restored
+	volumedrivers.Unregister("fake")
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+
+	ls, _, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls) != 1 || ls[0].Name() != "restored" || ls[0].DriverName() != "fake" {
+		t.Fatalf("Expected the restored volume to be listed, got %v", ls)
+	}
+
+	// the volume is looked up once its driver is available
+	volumedrivers.Register(driver, "fake")
+	if _, err := s.Create("restored", "other", nil, nil); !IsNameConflict(err) {
+		t.Fatalf("Expected a name conflict with another driver, got %v", err)
+	}
+	v, err = s.CreateWithRef("restored", "", "container", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := unwrapped(v).(vt.FakeVolume); !ok {
+		t.Fatalf("Expected the volume of the driver, got %#v", v)
+	}
+	if labels := v.(volume.LabeledVolume).Labels(); labels["team"] != "storage" {
+		t.Fatalf("Expected the labels of the volume to be restored, got %v", labels)
+	}
+	if opts := v.(volumeWrapper).Options(); opts["size"] != "1g" {
+		t.Fatalf("Expected the options of the volume to be restored, got %v", opts)
+	}
+	if c := v.(volume.TimedVolume).CreatedAt(); !c.Equal(createdAt) {
+		t.Fatalf("Expected the creation time of the volume to be restored as %v, got %v", createdAt, c)
+	}
+}
+
+func TestRestoreRemovedVolume(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	defer volumedrivers.Unregister("fake")
+
+	dir, err := ioutil.TempDir("", "test-volume-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("removed", "fake", nil, map[string]string{"team": "storage"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the volume is removed from its driver while the daemon is stopped
+	volumedrivers.Unregister("fake")
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+
+	v, err := s.Create("removed", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.DriverName() != "fake" || v.(volume.LabeledVolume).Labels()["team"] != "storage" {
+		t.Fatalf("Expected the volume to be created again with its labels, got %#v", v)
+	}
+}
+
+func TestRestoreStaleVolume(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	defer volumedrivers.Unregister("fake")
+	other := vt.NewFakeDriver("other")
+	volumedrivers.Register(other, "other")
+	defer volumedrivers.Unregister("other")
+
+	dir, err := ioutil.TempDir("", "test-volume-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"listed", "created"} {
+		if _, err := s.Create(name, "fake", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the volumes are removed from their driver while the daemon is stopped
+	volumedrivers.Unregister("fake")
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the name of a stale volume can be used with another driver
+	if _, err := s.Create("created", "other", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Get("created"); err != nil {
+		t.Fatalf("Expected the volume to be created with the other driver: %v", err)
+	}
+
+	// the stale volumes are not listed anymore once their driver is listed
+	ls, _, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls) != 1 || ls[0].Name() != "created" {
+		t.Fatalf("Expected only the volume created again to be listed, got %v", ls)
+	}
+
+	// the stale volumes are removed from the database too
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+	if _, exists := s.getNamed("listed"); exists {
+		t.Fatal("Expected the stale volume to be removed from the database")
+	}
+}
+
+func TestRemoveRestoredVolumeWithoutDriver(t *testing.T) {
+	volumedrivers.Register(vt.NewFakeDriver("fake"), "fake")
+	defer volumedrivers.Unregister("fake")
+
+	dir, err := ioutil.TempDir("", "test-volume-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("orphan", "fake", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the driver is uninstalled while the daemon is stopped
+	volumedrivers.Unregister("fake")
+	s, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+
+	ls, _, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls) != 1 {
+		t.Fatalf("Expected the restored volume to be listed, got %v", ls)
+	}
+	if err := s.Remove(ls[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("orphan"); !IsNotExist(err) {
+		t.Fatalf("Expected the volume to be removed, got %v", err)
+	}
+	s.db.View(func(tx *bolt.Tx) error {
+		if ls := listMeta(tx); len(ls) != 0 {
+			t.Fatalf("Expected the metadata of the volume to be removed, got %v", ls)
+		}
+		return nil
+	})
+}
+
+// unwrapped returns the volume wrapped by the store.
+func unwrapped(v volume.Volume) volume.Volume {
+	if vw, ok := v.(volumeWrapper); ok {
+		return vw.Volume
+	}
+	return v
+}
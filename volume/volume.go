@@ -4,6 +4,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	derr "github.com/docker/docker/errors"
@@ -41,6 +42,22 @@ type Volume interface {
 	Mount() (string, error)
 	// Unmount unmounts the volume when it is no longer in use.
 	Unmount() error
+	// Status returns low-level status information about the volume, as
+	// reported by its driver.
+	Status() map[string]interface{}
+}
+
+// LabeledVolume wraps a Volume with user-defined labels
+type LabeledVolume interface {
+	Labels() map[string]string
+	Volume
+}
+
+// TimedVolume wraps a Volume with the time it was created at, which is zero
+// if it is not known
+type TimedVolume interface {
+	CreatedAt() time.Time
+	Volume
 }
 
 // MountPoint is the intersection point between a volume and a container. It